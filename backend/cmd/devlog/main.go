@@ -0,0 +1,262 @@
+// Command devlog manages a DevLog installation from the shell.
+//
+//	devlog admin create --username alice [--email a@example.com] [--role owner]
+//	devlog admin list
+//	devlog admin set-password <username>
+//	devlog admin deactivate <username>
+//	devlog admin reset-2fa <username>
+//
+// It reads the same environment (or .env file) as the server. Passwords are
+// read from the terminal without echo, or as one line from piped stdin.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/term"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"backend/config"
+	"backend/database"
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"backend/internal/service"
+)
+
+const usage = `Usage: devlog admin <command> [arguments]
+
+Commands:
+  create --username NAME [--email EMAIL] [--role owner|editor|author|moderator]
+                           create an admin, prompting for the password
+  list                     list active admins
+  set-password USERNAME    set a new password and end the admin's sessions
+  deactivate USERNAME      deactivate an admin and end their sessions
+  reset-2fa USERNAME       turn off two-factor authentication of an admin
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 3 || os.Args[1] != "admin" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func(*adminCLI, []string) error{
+		"create":       (*adminCLI).create,
+		"list":         (*adminCLI).list,
+		"set-password": (*adminCLI).setPassword,
+		"deactivate":   (*adminCLI).deactivate,
+		"reset-2fa":    (*adminCLI).resetTwoFactor,
+	}
+	command, ok := commands[os.Args[2]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[2], usage)
+		os.Exit(2)
+	}
+
+	cli, err := connect()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer database.Close()
+
+	if err := command(cli, os.Args[3:]); err != nil {
+		log.Fatalf("devlog admin %s: %v", os.Args[2], err)
+	}
+}
+
+type adminCLI struct {
+	adminRepo   repository.AdminRepository
+	userService service.UserService
+	stdin       *bufio.Reader
+}
+
+func connect() (*adminCLI, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := database.Connect(cfg.Database.DSN()); err != nil {
+		return nil, err
+	}
+	database.DB.Logger = logger.Default.LogMode(logger.Silent)
+
+	adminRepo := repository.NewAdminRepository(database.DB)
+	return &adminCLI{
+		adminRepo: adminRepo,
+		userService: service.NewUserService(
+			adminRepo,
+			repository.NewAuthTokenRepository(database.DB),
+			repository.NewRecoveryCodeRepository(database.DB),
+		),
+		stdin: bufio.NewReader(os.Stdin),
+	}, nil
+}
+
+func (cli *adminCLI) create(args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	username := flags.String("username", "", "login name (required)")
+	email := flags.String("email", "", "email address")
+	role := flags.String("role", entity.RoleOwner, "owner, editor, author or moderator")
+	flags.Parse(args)
+
+	if *username == "" {
+		return errors.New("--username is required")
+	}
+	if !isRole(*role) {
+		return fmt.Errorf("unknown role %q", *role)
+	}
+
+	password, err := cli.readNewPassword(*username)
+	if err != nil {
+		return err
+	}
+
+	user, err := cli.userService.CreateUser(dto.CreateUserRequest{
+		Username: *username,
+		Email:    *email,
+		Password: password,
+		Role:     *role,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created %s %s (%s)\n", user.Role, user.Username, user.ID)
+	return nil
+}
+
+func (cli *adminCLI) list(args []string) error {
+	users, err := cli.userService.ListUsers()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "USERNAME\tROLE\tEMAIL\t2FA\tLAST LOGIN")
+	for _, user := range users {
+		lastLogin := "never"
+		if user.LastLogin != nil {
+			lastLogin = user.LastLogin.Local().Format("2006-01-02 15:04")
+		}
+		twoFactor := "off"
+		if user.TwoFactorEnabled {
+			twoFactor = "on"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", user.Username, user.Role, user.Email, twoFactor, lastLogin)
+	}
+	return w.Flush()
+}
+
+func (cli *adminCLI) setPassword(args []string) error {
+	admin, err := cli.findAdmin(args)
+	if err != nil {
+		return err
+	}
+
+	password, err := cli.readNewPassword(admin.Username)
+	if err != nil {
+		return err
+	}
+	if _, err := cli.userService.UpdateUser(admin.ID, dto.UpdateUserRequest{Password: &password}); err != nil {
+		return err
+	}
+	fmt.Printf("Password of %s changed, their sessions were ended\n", admin.Username)
+	return nil
+}
+
+func (cli *adminCLI) deactivate(args []string) error {
+	admin, err := cli.findAdmin(args)
+	if err != nil {
+		return err
+	}
+
+	if err := cli.userService.DeleteUser(admin.ID, service.Actor{}); err != nil {
+		return err
+	}
+	fmt.Printf("Deactivated %s\n", admin.Username)
+	return nil
+}
+
+func (cli *adminCLI) resetTwoFactor(args []string) error {
+	admin, err := cli.findAdmin(args)
+	if err != nil {
+		return err
+	}
+
+	if err := cli.userService.ResetTwoFactor(admin.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Two-factor authentication of %s turned off\n", admin.Username)
+	return nil
+}
+
+// findAdmin resolves the single USERNAME argument to an active admin
+func (cli *adminCLI) findAdmin(args []string) (*entity.Admin, error) {
+	if len(args) != 1 {
+		return nil, errors.New("expected exactly one USERNAME argument")
+	}
+
+	admin, err := cli.adminRepo.FindByUsername(args[0])
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("no active admin named %q", args[0])
+	}
+	return admin, err
+}
+
+// readNewPassword prompts twice on a terminal and checks the password
+// policy before anything is written
+func (cli *adminCLI) readNewPassword(username string) (string, error) {
+	password, err := cli.readPassword("New password: ")
+	if err != nil {
+		return "", err
+	}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		confirm, err := cli.readPassword("Repeat password: ")
+		if err != nil {
+			return "", err
+		}
+		if confirm != password {
+			return "", errors.New("passwords do not match")
+		}
+	}
+
+	if err := service.ValidatePassword(password, username); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+func (cli *adminCLI) readPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt)
+		password, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(password), err
+	}
+
+	line, err := cli.stdin.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", errors.New("no password on stdin")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func isRole(role string) bool {
+	switch role {
+	case entity.RoleOwner, entity.RoleEditor, entity.RoleAuthor, entity.RoleModerator:
+		return true
+	}
+	return false
+}
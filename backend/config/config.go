@@ -1,17 +1,27 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	OSS      OSSConfig
-	SEO      SEOConfig
+	Database  DatabaseConfig
+	Server    ServerConfig
+	JWT       JWTConfig
+	Login     LoginConfig
+	OIDC      OIDCConfig
+	Audit     AuditConfig
+	Storage   StorageConfig
+	Upload    UploadConfig
+	SEO       SEOConfig
+	Analytics AnalyticsConfig
+	Feed      FeedConfig
 }
 
 type DatabaseConfig struct {
@@ -31,6 +41,95 @@ type ServerConfig struct {
 	JKSPassword string
 }
 
+// JWTConfig - 管理员令牌签名密钥, 最后一个密钥用于签发新令牌, 其余仅用于验证
+type JWTConfig struct {
+	Keys       []JWTKey
+	AccessTTL  string // 访问令牌有效期, e.g. "15m"
+	RefreshTTL string // 刷新令牌有效期, 每次刷新后重新计算, e.g. "720h"
+}
+
+// JWTKey - 以 kid 标识的签名密钥
+type JWTKey struct {
+	ID     string
+	Secret string
+}
+
+// insecureJWTSecrets 是示例配置中的占位密钥, release 模式下拒绝使用
+var insecureJWTSecrets = []string{
+	"your-secret-key-change-in-production",
+	"your-super-secret-jwt-key-change-in-production",
+	"change-this-secret-in-production",
+}
+
+// LoginConfig - 登录失败限制, 按用户名和 IP 分别计数
+type LoginConfig struct {
+	MaxFailures      int    // 同一用户名连续失败该次数后锁定, 之前每次失败的等待时间翻倍
+	IPMaxFailures    int    // 同一 IP 连续失败该次数后锁定
+	LockoutDuration  string // 首次锁定时长, 再次锁定时翻倍, e.g. "15m"
+	FailureWindow    string // 最后一次失败及其锁定结束超过该时间后重新计数, e.g. "1h"
+	HistoryRetention string // 登录记录保留时长, 超过后每小时清理一次, "0" 为永久保留, e.g. "2160h"
+}
+
+// OIDCConfig - OpenID Connect 单点登录, OIDC_PROVIDERS 中每个名称对应一组
+// OIDC_<NAME>_* 配置
+type OIDCConfig struct {
+	RedirectURL string // 身份提供方登录后跳回的前端地址, e.g. "https://blog.example.com/?view=sso"
+	Providers   []OIDCProviderConfig
+}
+
+// OIDCProviderConfig - 一个身份提供方, 通过 Issuer 的 discovery 文档获取端点
+type OIDCProviderConfig struct {
+	Name           string // 小写名称, 用于接口路径和环境变量
+	DisplayName    string // 登录按钮上显示的名称
+	Issuer         string // e.g. "https://login.example.com/realms/company"
+	ClientID       string
+	ClientSecret   string   // 公共客户端可为空, 仅依靠 PKCE
+	Scopes         []string // 除 openid 外请求的 scope
+	AutoProvision  bool     // 首次登录时自动创建管理员账号
+	DefaultRole    string   // 自动创建的账号的角色
+	AllowedDomains []string // 自动创建和按邮箱关联时允许的邮箱域名, 为空时不限制
+	LinkByEmail    bool     // 按已验证的邮箱关联已有账号
+}
+
+// AuditConfig - 管理操作审计日志
+type AuditConfig struct {
+	Retention string // 审计日志保留时长, 超过后每小时清理一次, "0" 为永久保留, e.g. "2160h"
+}
+
+// Storage drivers
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+	StorageOSS   = "oss"
+)
+
+// StorageConfig - 上传文件存储, Driver 为 local、s3 或 oss; 未设置时配置了
+// OSS_* 则使用 oss, 否则使用 local
+type StorageConfig struct {
+	Driver string
+	Local  LocalStorageConfig
+	S3     S3Config
+	OSS    OSSConfig
+}
+
+// LocalStorageConfig - 保存到本地目录, 由后端在 /uploads/ 下提供访问
+type LocalStorageConfig struct {
+	Dir     string // 存储目录
+	BaseURL string // 文件访问地址前缀, 默认为 SEO_SITE_URL
+}
+
+// S3Config - S3 兼容的对象存储, 如 MinIO、Cloudflare R2、AWS S3
+type S3Config struct {
+	Endpoint        string // e.g. "s3.amazonaws.com", "localhost:9000"
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Bucket          string
+	UseSSL          bool
+	PathStyle       bool   // 使用 endpoint/bucket/key 形式的地址, MinIO 需要开启
+	BaseURL         string // CDN 或 bucket 的公开访问地址, 为空时由 endpoint 和 bucket 拼接
+}
+
 type OSSConfig struct {
 	Endpoint        string
 	AccessKeyID     string
@@ -39,12 +138,68 @@ type OSSConfig struct {
 	BaseURL         string // CDN or bucket public URL
 }
 
+// UploadConfig - 上传文件校验, 文件类型以内容识别结果为准; JPEG、PNG、WebP
+// 图片会移除元数据并生成缩放版本
+type UploadConfig struct {
+	MaxSize      int64    // 单个文件的最大字节数
+	AllowedTypes []string // 允许上传的 MIME 类型, e.g. "image/png"
+	ImageWidths  []int    // 缩放版本的宽度, 不小于原图宽度的跳过
+	ImageQuality int      // JPEG 压缩质量, 1-100
+	ImageWebP    bool     // 同时生成 WebP 版本, 仅在比 JPEG/PNG 小时保留
+}
+
 type SEOConfig struct {
 	SiteURL      string
-	BaiduSite    string // 百度站点域名
-	BaiduToken   string // 百度推送token
-	BingAPIKey   string // Bing IndexNow API Key
-	PushInterval string // 推送间隔, e.g. "24h"
+	PushInterval string // 推送队列处理间隔, e.g. "5m"
+
+	Baidu    BaiduPushConfig
+	IndexNow IndexNowConfig
+	Google   GooglePushConfig
+
+	RobotsDisallow string // robots.txt 禁止抓取的路径, 逗号分隔
+	RobotsFile     string // 自定义 robots.txt 文件路径, 设置后替代生成的内容
+}
+
+// BaiduPushConfig - 百度站长平台推送, 配置站点与 token 后启用
+type BaiduPushConfig struct {
+	Enabled    bool
+	Site       string // 百度站点域名
+	Token      string // 百度推送token
+	Endpoint   string // API 地址, 可替换为本地测试桩
+	DailyQuota int    // 每日推送配额
+}
+
+// IndexNowConfig - IndexNow 推送 (Bing 等), 配置 Key 或 Enabled 后启用
+type IndexNowConfig struct {
+	Enabled    bool   // 未配置 Key 时也启用, 密钥自动生成
+	Key        string // Bing IndexNow API Key, 首次启用时作为初始密钥
+	Endpoint   string // API 地址, 可替换为本地测试桩
+	DailyQuota int    // 每日推送配额
+}
+
+// GooglePushConfig - Google Indexing API, 配置服务账号密钥文件后启用
+type GooglePushConfig struct {
+	Enabled         bool
+	CredentialsFile string // 服务账号 JSON 密钥文件路径
+	Endpoint        string // API 地址, 可替换为本地测试桩
+	TokenURL        string // OAuth token 地址, 为空时使用密钥文件中的 token_uri
+	DailyQuota      int    // 每日推送配额
+}
+
+type AnalyticsConfig struct {
+	Salt              string // 访客匿名哈希的盐值，为空时启动时随机生成
+	ViewDedupWindow   string // 同一访客重复浏览同一文章的去重窗口, e.g. "30m"
+	ViewFlushInterval string // 浏览量批量写入间隔, e.g. "30s"
+	ViewBatchSize     int    // 缓冲浏览量达到该数量时立即写入
+	MaxSearchQueries  int    // 每个写入间隔内最多缓冲的不同搜索词, 超出的新搜索词不计入
+	Retention         string // 每日流量来源与搜索统计的保留时长, 超过后每小时清理一次, "0" 为永久保留, e.g. "8760h"
+}
+
+type FeedConfig struct {
+	Title       string // 订阅源标题
+	Description string // 订阅源描述
+	Language    string // 订阅源语言, e.g. "zh-CN"
+	Limit       int    // 订阅源包含的最新文章数
 }
 
 func Load() (*Config, error) {
@@ -54,6 +209,24 @@ func Load() (*Config, error) {
 		fmt.Println("Warning: .env file not found, using environment variables")
 	}
 
+	mode := getEnv("GIN_MODE", "debug")
+	jwtConfig, err := loadJWTConfig(mode)
+	if err != nil {
+		return nil, err
+	}
+	oidcConfig, err := loadOIDCConfig()
+	if err != nil {
+		return nil, err
+	}
+	storageConfig, err := loadStorageConfig()
+	if err != nil {
+		return nil, err
+	}
+	uploadConfig, err := loadUploadConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -65,11 +238,189 @@ func Load() (*Config, error) {
 		},
 		Server: ServerConfig{
 			Port:        getEnv("SERVER_PORT", "8080"),
-			Mode:        getEnv("GIN_MODE", "debug"),
+			Mode:        mode,
 			SSL:         getEnv("SERVER_SSL", "false") == "true",
 			JKSPath:     getEnv("SERVER_JKS_PATH", "JKS/blog.ubanillx.com.jks"),
 			JKSPassword: getEnv("SERVER_JKS_PASSWORD", "123456"),
 		},
+		JWT: jwtConfig,
+		Login: LoginConfig{
+			MaxFailures:      getEnvInt("LOGIN_MAX_FAILURES", 5),
+			IPMaxFailures:    getEnvInt("LOGIN_IP_MAX_FAILURES", 20),
+			LockoutDuration:  getEnv("LOGIN_LOCKOUT_DURATION", "15m"),
+			FailureWindow:    getEnv("LOGIN_FAILURE_WINDOW", "1h"),
+			HistoryRetention: getEnv("LOGIN_HISTORY_RETENTION", "2160h"),
+		},
+		OIDC: oidcConfig,
+		Audit: AuditConfig{
+			Retention: getEnv("AUDIT_RETENTION", "2160h"),
+		},
+		Storage: storageConfig,
+		Upload:  uploadConfig,
+		SEO: SEOConfig{
+			SiteURL:      getEnv("SEO_SITE_URL", ""),
+			PushInterval: getEnv("SEO_PUSH_INTERVAL", "5m"),
+
+			Baidu: BaiduPushConfig{
+				Enabled:    getEnv("SEO_BAIDU_ENABLED", "true") == "true",
+				Site:       getEnv("SEO_BAIDU_SITE", ""),
+				Token:      getEnv("SEO_BAIDU_TOKEN", ""),
+				Endpoint:   getEnv("SEO_BAIDU_ENDPOINT", "http://data.zz.baidu.com"),
+				DailyQuota: getEnvInt("SEO_BAIDU_DAILY_QUOTA", 10),
+			},
+			IndexNow: IndexNowConfig{
+				Enabled:    getEnv("SEO_INDEXNOW_ENABLED", "false") == "true",
+				Key:        getEnv("SEO_BING_API_KEY", ""),
+				Endpoint:   getEnv("SEO_INDEXNOW_ENDPOINT", "https://api.indexnow.org/indexnow"),
+				DailyQuota: getEnvInt("SEO_INDEXNOW_DAILY_QUOTA", 10000),
+			},
+			Google: GooglePushConfig{
+				Enabled:         getEnv("SEO_GOOGLE_ENABLED", "true") == "true",
+				CredentialsFile: getEnv("SEO_GOOGLE_CREDENTIALS_FILE", ""),
+				Endpoint:        getEnv("SEO_GOOGLE_ENDPOINT", "https://indexing.googleapis.com/v3/urlNotifications:publish"),
+				TokenURL:        getEnv("SEO_GOOGLE_TOKEN_URL", ""),
+				DailyQuota:      getEnvInt("SEO_GOOGLE_DAILY_QUOTA", 200),
+			},
+
+			RobotsDisallow: getEnv("SEO_ROBOTS_DISALLOW", "/swagger/,/api/v1/admin/"),
+			RobotsFile:     getEnv("SEO_ROBOTS_FILE", ""),
+		},
+		Analytics: AnalyticsConfig{
+			Salt:              getEnv("ANALYTICS_SALT", ""),
+			ViewDedupWindow:   getEnv("ANALYTICS_VIEW_DEDUP_WINDOW", "30m"),
+			ViewFlushInterval: getEnv("ANALYTICS_VIEW_FLUSH_INTERVAL", "30s"),
+			ViewBatchSize:     getEnvInt("ANALYTICS_VIEW_BATCH_SIZE", 500),
+			MaxSearchQueries:  getEnvInt("ANALYTICS_MAX_SEARCH_QUERIES", 1000),
+			Retention:         getEnv("ANALYTICS_RETENTION", "8760h"),
+		},
+		Feed: FeedConfig{
+			Title:       getEnv("FEED_TITLE", "UbanillxのDevLog"),
+			Description: getEnv("FEED_DESCRIPTION", "Technical blog feed"),
+			Language:    getEnv("FEED_LANGUAGE", "zh-CN"),
+			Limit:       getEnvInt("FEED_LIMIT", 20),
+		},
+	}, nil
+}
+
+// loadJWTConfig reads the signing keys: JWT_SECRET is the key "default",
+// followed by the "kid:secret" pairs of JWT_KEYS, oldest first. Without any
+// key the placeholder secret is used, which release mode refuses.
+func loadJWTConfig(mode string) (JWTConfig, error) {
+	cfg := JWTConfig{
+		AccessTTL:  getEnv("JWT_ACCESS_TTL", "15m"),
+		RefreshTTL: getEnv("JWT_REFRESH_TTL", "720h"),
+	}
+	if secret := getEnv("JWT_SECRET", ""); secret != "" {
+		cfg.Keys = append(cfg.Keys, JWTKey{ID: "default", Secret: secret})
+	}
+	for _, pair := range strings.Split(getEnv("JWT_KEYS", ""), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			return cfg, fmt.Errorf("invalid JWT_KEYS entry %q, expected kid:secret", pair)
+		}
+		cfg.Keys = append(cfg.Keys, JWTKey{ID: id, Secret: secret})
+	}
+
+	seen := make(map[string]bool)
+	for _, key := range cfg.Keys {
+		if seen[key.ID] {
+			return cfg, fmt.Errorf("duplicate JWT key ID %q", key.ID)
+		}
+		seen[key.ID] = true
+
+		if mode == "release" {
+			for _, insecure := range insecureJWTSecrets {
+				if key.Secret == insecure {
+					return cfg, fmt.Errorf("JWT key %q uses the example secret, set a random one", key.ID)
+				}
+			}
+			if len(key.Secret) < 32 {
+				return cfg, fmt.Errorf("JWT key %q is shorter than 32 characters", key.ID)
+			}
+		}
+	}
+
+	if len(cfg.Keys) == 0 {
+		if mode == "release" {
+			return cfg, errors.New("JWT_SECRET or JWT_KEYS must be set in release mode")
+		}
+		fmt.Println("Warning: JWT_SECRET not configured, signing tokens with an insecure default key")
+		cfg.Keys = []JWTKey{{ID: "default", Secret: insecureJWTSecrets[0]}}
+	}
+	return cfg, nil
+}
+
+// loadOIDCConfig reads the providers named in OIDC_PROVIDERS. A provider
+// "company" is configured by OIDC_COMPANY_ISSUER, OIDC_COMPANY_CLIENT_ID and
+// so on.
+func loadOIDCConfig() (OIDCConfig, error) {
+	cfg := OIDCConfig{RedirectURL: getEnv("OIDC_REDIRECT_URL", "")}
+
+	for _, name := range splitList(getEnv("OIDC_PROVIDERS", "")) {
+		name = strings.ToLower(name)
+		if !validProviderName(name) {
+			return cfg, fmt.Errorf("invalid OIDC provider name %q, use letters, digits and hyphens", name)
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+		provider := OIDCProviderConfig{
+			Name:           name,
+			DisplayName:    getEnv(prefix+"DISPLAY_NAME", name),
+			Issuer:         getEnv(prefix+"ISSUER", ""),
+			ClientID:       getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret:   getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:         splitList(getEnv(prefix+"SCOPES", "profile,email")),
+			AutoProvision:  getEnv(prefix+"AUTO_PROVISION", "false") == "true",
+			DefaultRole:    getEnv(prefix+"DEFAULT_ROLE", "author"),
+			AllowedDomains: splitList(strings.ToLower(getEnv(prefix+"ALLOWED_DOMAINS", ""))),
+			LinkByEmail:    getEnv(prefix+"LINK_BY_EMAIL", "false") == "true",
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return cfg, fmt.Errorf("OIDC provider %q needs %sISSUER and %sCLIENT_ID", name, prefix, prefix)
+		}
+		cfg.Providers = append(cfg.Providers, provider)
+	}
+
+	if len(cfg.Providers) > 0 && cfg.RedirectURL == "" {
+		return cfg, errors.New("OIDC_REDIRECT_URL must be set when OIDC providers are configured")
+	}
+	return cfg, nil
+}
+
+func validProviderName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// loadStorageConfig reads the upload storage. Instances configured for OSS
+// before STORAGE_DRIVER existed keep using it.
+func loadStorageConfig() (StorageConfig, error) {
+	cfg := StorageConfig{
+		Local: LocalStorageConfig{
+			Dir:     getEnv("STORAGE_LOCAL_DIR", "data"),
+			BaseURL: strings.TrimSuffix(getEnv("STORAGE_LOCAL_BASE_URL", getEnv("SEO_SITE_URL", "")), "/"),
+		},
+		S3: S3Config{
+			Endpoint:        getEnv("S3_ENDPOINT", ""),
+			Region:          getEnv("S3_REGION", "us-east-1"),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			Bucket:          getEnv("S3_BUCKET", ""),
+			UseSSL:          getEnv("S3_USE_SSL", "true") == "true",
+			PathStyle:       getEnv("S3_PATH_STYLE", "false") == "true",
+			BaseURL:         getEnv("S3_BASE_URL", ""),
+		},
 		OSS: OSSConfig{
 			Endpoint:        getEnv("OSS_ENDPOINT", ""),
 			AccessKeyID:     getEnv("OSS_ACCESS_KEY_ID", ""),
@@ -77,14 +428,88 @@ func Load() (*Config, error) {
 			BucketName:      getEnv("OSS_BUCKET_NAME", ""),
 			BaseURL:         getEnv("OSS_BASE_URL", ""),
 		},
-		SEO: SEOConfig{
-			SiteURL:      getEnv("SEO_SITE_URL", ""),
-			BaiduSite:    getEnv("SEO_BAIDU_SITE", ""),
-			BaiduToken:   getEnv("SEO_BAIDU_TOKEN", ""),
-			BingAPIKey:   getEnv("SEO_BING_API_KEY", ""),
-			PushInterval: getEnv("SEO_PUSH_INTERVAL", "24h"),
-		},
-	}, nil
+	}
+
+	oss := cfg.OSS
+	ossConfigured := oss.AccessKeyID != "" && oss.AccessKeySecret != "" && oss.BucketName != ""
+	cfg.Driver = strings.ToLower(getEnv("STORAGE_DRIVER", ""))
+	if cfg.Driver == "" {
+		cfg.Driver = StorageLocal
+		if ossConfigured {
+			cfg.Driver = StorageOSS
+		}
+	}
+
+	switch cfg.Driver {
+	case StorageLocal:
+		if cfg.Local.Dir == "" {
+			return cfg, errors.New("STORAGE_LOCAL_DIR must not be empty")
+		}
+	case StorageS3:
+		if cfg.S3.Endpoint == "" || cfg.S3.AccessKeyID == "" || cfg.S3.SecretAccessKey == "" || cfg.S3.Bucket == "" {
+			return cfg, errors.New("S3 storage needs S3_ENDPOINT, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY and S3_BUCKET")
+		}
+	case StorageOSS:
+		if oss.Endpoint == "" || !ossConfigured {
+			return cfg, errors.New("OSS storage needs OSS_ENDPOINT, OSS_ACCESS_KEY_ID, OSS_ACCESS_KEY_SECRET and OSS_BUCKET_NAME")
+		}
+	default:
+		return cfg, fmt.Errorf("unknown STORAGE_DRIVER %q, use local, s3 or oss", cfg.Driver)
+	}
+	return cfg, nil
+}
+
+// defaultUploadTypes are the images, PDF documents and videos accepted
+// without UPLOAD_ALLOWED_TYPES. GIF and AVIF images are stored as they are,
+// with their metadata such as the GPS position, so they must be enabled
+// explicitly.
+const defaultUploadTypes = "image/jpeg,image/png,image/webp,image/svg+xml,application/pdf,video/mp4,video/webm"
+
+// loadUploadConfig reads the upload limits. UPLOAD_MAX_SIZE_MB is in
+// megabytes.
+func loadUploadConfig() (UploadConfig, error) {
+	maxSizeMB := getEnvInt("UPLOAD_MAX_SIZE_MB", 10)
+	if maxSizeMB <= 0 {
+		return UploadConfig{}, errors.New("UPLOAD_MAX_SIZE_MB must be positive")
+	}
+
+	cfg := UploadConfig{
+		MaxSize:      int64(maxSizeMB) << 20,
+		AllowedTypes: splitList(strings.ToLower(getEnv("UPLOAD_ALLOWED_TYPES", defaultUploadTypes))),
+		ImageQuality: getEnvInt("UPLOAD_IMAGE_QUALITY", 82),
+		ImageWebP:    getEnv("UPLOAD_IMAGE_WEBP", "true") == "true",
+	}
+	if len(cfg.AllowedTypes) == 0 {
+		return cfg, errors.New("UPLOAD_ALLOWED_TYPES must not be empty")
+	}
+	if cfg.ImageQuality < 1 || cfg.ImageQuality > 100 {
+		return cfg, errors.New("UPLOAD_IMAGE_QUALITY must be between 1 and 100")
+	}
+
+	// An empty UPLOAD_IMAGE_WIDTHS keeps only the full size image
+	widths := "480,960,1600"
+	if value, ok := os.LookupEnv("UPLOAD_IMAGE_WIDTHS"); ok {
+		widths = value
+	}
+	for _, item := range splitList(widths) {
+		width, err := strconv.Atoi(item)
+		if err != nil || width <= 0 {
+			return cfg, fmt.Errorf("invalid UPLOAD_IMAGE_WIDTHS entry %q, expected a width in pixels", item)
+		}
+		cfg.ImageWidths = append(cfg.ImageWidths, width)
+	}
+	return cfg, nil
+}
+
+// splitList splits a comma separated value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 func (d *DatabaseConfig) DSN() string {
@@ -100,3 +525,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
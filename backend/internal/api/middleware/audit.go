@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"backend/internal/service"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// auditMaxBody is the size of request and response bodies the audit
+// middleware reads, larger bodies are passed through without a summary
+const auditMaxBody = 64 << 10
+
+// auditRoute names the action of a mutating admin route and its target.
+// The target ID is the :id parameter, the field responseKey of the response
+// data for routes creating their target, or the admin for self targets.
+type auditRoute struct {
+	action      string
+	targetType  string
+	responseKey string
+	self        bool
+}
+
+// auditRoutes maps "METHOD /path" of the mutating admin routes in router.go
+// to their audit action. Routes missing here are still recorded, under
+// their method and path.
+var auditRoutes = map[string]auditRoute{
+	"POST /api/v1/auth/logout":                  {action: "session.logout", targetType: service.AuditTargetSession},
+	"POST /api/v1/auth/logout-all":              {action: "session.logout_all", targetType: service.AuditTargetSession},
+	"DELETE /api/v1/auth/sessions/:id":          {action: "session.revoke", targetType: service.AuditTargetSession},
+	"PUT /api/v1/auth/me":                       {action: "account.update_profile", targetType: service.AuditTargetUser, self: true},
+	"PUT /api/v1/auth/password":                 {action: "account.change_password", targetType: service.AuditTargetUser, self: true},
+	"POST /api/v1/auth/2fa/setup":               {action: "account.2fa_setup", targetType: service.AuditTargetUser, self: true},
+	"POST /api/v1/auth/2fa/enable":              {action: "account.2fa_enable", targetType: service.AuditTargetUser, self: true},
+	"POST /api/v1/auth/2fa/disable":             {action: "account.2fa_disable", targetType: service.AuditTargetUser, self: true},
+	"POST /api/v1/auth/2fa/recovery-codes":      {action: "account.2fa_recovery_codes", targetType: service.AuditTargetUser, self: true},
+	"POST /api/v1/auth/oidc/link":               {action: "account.oidc_link", targetType: service.AuditTargetUser, self: true},
+	"POST /api/v1/auth/tokens":                  {action: "api_token.create", targetType: service.AuditTargetAPIToken, responseKey: "id"},
+	"DELETE /api/v1/auth/tokens/:id":            {action: "api_token.revoke", targetType: service.AuditTargetAPIToken},
+	"POST /api/v1/auth/lockouts/unlock":         {action: "lockout.unlock", targetType: service.AuditTargetLockout},
+	"POST /api/v1/admin/users":                  {action: "user.create", targetType: service.AuditTargetUser, responseKey: "id"},
+	"PUT /api/v1/admin/users/:id":               {action: "user.update", targetType: service.AuditTargetUser},
+	"DELETE /api/v1/admin/users/:id":            {action: "user.delete", targetType: service.AuditTargetUser},
+	"POST /api/v1/admin/users/:id/reset-2fa":    {action: "user.reset_2fa", targetType: service.AuditTargetUser},
+	"POST /api/v1/posts":                        {action: "post.create", targetType: service.AuditTargetPost, responseKey: "id"},
+	"PUT /api/v1/posts/:id":                     {action: "post.update", targetType: service.AuditTargetPost},
+	"DELETE /api/v1/posts/:id":                  {action: "post.delete", targetType: service.AuditTargetPost},
+	"POST /api/v1/tags":                         {action: "tag.create", targetType: service.AuditTargetTag, responseKey: "id"},
+	"DELETE /api/v1/tags/:id":                   {action: "tag.delete", targetType: service.AuditTargetTag},
+	"POST /api/v1/comments/:id/reply":           {action: "comment.reply", targetType: service.AuditTargetComment, responseKey: "id"},
+	"DELETE /api/v1/comments/:id":               {action: "comment.delete", targetType: service.AuditTargetComment},
+	"POST /api/v1/admin/seo/history/:id/repush": {action: "seo.repush", targetType: service.AuditTargetSEO},
+	"POST /api/v1/admin/seo/push-all":           {action: "seo.push_all", targetType: service.AuditTargetSEO},
+	"POST /api/v1/admin/seo/indexnow/rotate":    {action: "seo.indexnow_rotate", targetType: service.AuditTargetSEO},
+	"POST /api/v1/upload":                       {action: "upload.create", targetType: service.AuditTargetUpload, responseKey: "url"},
+}
+
+// unauditedRoutes are admin routes that use POST without changing anything
+var unauditedRoutes = map[string]bool{
+	"POST /api/v1/ai/excerpt":   true,
+	"POST /api/v1/ai/readtime":  true,
+	"POST /api/v1/ai/tags":      true,
+	"POST /api/v1/ai/summarize": true,
+}
+
+// auditResponseWriter keeps the start of the response body, to find the ID
+// of a created target
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	if w.body.Len() < auditMaxBody {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	if w.body.Len() < auditMaxBody {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// AuditMiddleware records every mutating request of the admin set by
+// AuthMiddleware in the audit log, including refused and failed ones, with
+// a before and after snapshot of the target where the service has one
+func AuditMiddleware(auditService service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		routeKey := c.Request.Method + " " + c.FullPath()
+		if unauditedRoutes[routeKey] {
+			c.Next()
+			return
+		}
+
+		route, ok := auditRoutes[routeKey]
+		if !ok {
+			route = auditRoute{action: strings.ToLower(routeKey)}
+		}
+
+		entry := service.AuditEntry{
+			Action:     route.action,
+			TargetType: route.targetType,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Visitor: service.Visitor{
+				IP:        c.ClientIP(),
+				UserAgent: c.Request.UserAgent(),
+			},
+		}
+		if value, ok := c.Get("adminID"); ok {
+			entry.ActorID, _ = value.(uuid.UUID)
+		}
+		if value, ok := c.Get("adminUsername"); ok {
+			entry.ActorUsername, _ = value.(string)
+		}
+		if value, ok := c.Get("apiToken"); ok {
+			if apiToken, ok := value.(*service.ValidatedAPIToken); ok {
+				entry.APITokenID = &apiToken.ID
+			}
+		}
+
+		switch {
+		case route.self:
+			entry.TargetID = entry.ActorID.String()
+		case route.responseKey == "":
+			entry.TargetID = c.Param("id")
+		}
+		if entry.TargetID != "" {
+			entry.Before = auditService.Snapshot(entry.TargetType, entry.TargetID)
+		}
+		entry.Request = readAuditRequest(c)
+
+		var writer *auditResponseWriter
+		if route.responseKey != "" {
+			writer = &auditResponseWriter{ResponseWriter: c.Writer}
+			c.Writer = writer
+		}
+
+		c.Next()
+
+		if writer != nil {
+			c.Writer = writer.ResponseWriter
+			entry.TargetID = responseTargetID(writer.body.Bytes(), route.responseKey)
+		}
+		if entry.TargetID != "" {
+			entry.After = auditService.Snapshot(entry.TargetType, entry.TargetID)
+		}
+		entry.Status = c.Writer.Status()
+		auditService.Record(entry)
+	}
+}
+
+// readAuditRequest decodes the fields of a JSON request body and puts the
+// body back for the handler
+func readAuditRequest(c *gin.Context) map[string]interface{} {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+
+	original := c.Request.Body
+	data, err := io.ReadAll(io.LimitReader(original, auditMaxBody+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), original), original}
+	if err != nil || len(data) > auditMaxBody {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// responseTargetID returns the field key of the response data as a string
+func responseTargetID(body []byte, key string) string {
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	id, _ := response.Data[key].(string)
+	return id
+}
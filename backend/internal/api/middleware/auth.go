@@ -7,9 +7,13 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
+// AuthMiddleware accepts a JWT access token or a personal API token. An API
+// token acts as its admin limited to its scopes, which RequirePermission
+// enforces.
+func AuthMiddleware(authService service.AuthService, apiTokenService service.APITokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -27,23 +31,104 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		adminID, err := authService.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, dto.Error(401, "Invalid or expired token"))
-			c.Abort()
-			return
+		var adminID uuid.UUID
+		if strings.HasPrefix(tokenString, service.APITokenPrefix) {
+			apiToken, err := apiTokenService.Authenticate(tokenString, service.Visitor{
+				IP:        c.ClientIP(),
+				UserAgent: c.Request.UserAgent(),
+			})
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, dto.Error(401, "Invalid or expired token"))
+				c.Abort()
+				return
+			}
+			adminID = apiToken.AdminID
+			c.Set("apiToken", apiToken)
+		} else {
+			token, err := authService.ValidateToken(tokenString)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, dto.Error(401, "Invalid or expired token"))
+				c.Abort()
+				return
+			}
+			adminID = token.AdminID
+			c.Set("accessToken", token)
 		}
 
 		// Get admin info and set in context
-		admin, err := authService.GetAdminByID(*adminID)
+		admin, err := authService.GetAdminByID(adminID)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, dto.Error(401, "Admin not found"))
 			c.Abort()
 			return
 		}
 
-		c.Set("adminID", *adminID)
+		c.Set("adminID", adminID)
 		c.Set("adminUsername", admin.Username)
+		c.Set("adminRole", admin.Role)
+		c.Next()
+	}
+}
+
+// RequireSession refuses API tokens on routes that manage the admin's own
+// account, like sessions, passwords and other tokens
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("apiToken"); ok {
+			c.JSON(http.StatusForbidden, dto.Error(403, "This action requires a login session, not an API token"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware identifies a logged-in admin when a valid token of
+// an active admin is present, but lets anonymous requests through unchanged.
+func OptionalAuthMiddleware(authService service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if token, err := authService.ValidateToken(parts[1]); err == nil {
+				if _, err := authService.GetAdminByID(token.AdminID); err == nil {
+					c.Set("adminID", token.AdminID)
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+// ActorFromContext returns the admin AuthMiddleware authenticated, with the
+// scopes of the API token when one was used
+func ActorFromContext(c *gin.Context) service.Actor {
+	var actor service.Actor
+	if id, ok := c.Get("adminID"); ok {
+		actor.ID, _ = id.(uuid.UUID)
+	}
+	if role, ok := c.Get("adminRole"); ok {
+		actor.Role, _ = role.(string)
+	}
+	if value, ok := c.Get("apiToken"); ok {
+		// Requests with a token never get the permissions of a session
+		actor.Scopes = []service.Permission{}
+		if apiToken, ok := value.(*service.ValidatedAPIToken); ok {
+			actor.Scopes = apiToken.Scopes
+		}
+	}
+	return actor
+}
+
+// RequirePermission lets a request through only if the role of the admin
+// set by AuthMiddleware grants the permission, and the scopes of the API
+// token too when one was used
+func RequirePermission(perm service.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ActorFromContext(c).Can(perm) {
+			c.JSON(http.StatusForbidden, dto.Error(403, service.ErrForbidden.Error()))
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -1,11 +1,16 @@
 package api
 
 import (
+	"backend/config"
 	"backend/internal/api/middleware"
 	v1 "backend/internal/api/v1"
 	"backend/internal/repository"
 	"backend/internal/service"
+	"context"
 	"log"
+	"net/http"
+	"path/filepath"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -14,10 +19,15 @@ import (
 )
 
 type Router struct {
-	engine *gin.Engine
+	engine             *gin.Engine
+	authService        service.AuthService
+	auditService       service.AuditService
+	viewService        service.ViewService
+	searchStatsService service.SearchStatsService
+	seoService         service.SEOService
 }
 
-func NewRouter(db *gorm.DB) *Router {
+func NewRouter(db *gorm.DB, cfg *config.Config) *Router {
 	engine := gin.Default()
 
 	// Global Middleware
@@ -28,12 +38,48 @@ func NewRouter(db *gorm.DB) *Router {
 	tagRepo := repository.NewTagRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
 	adminRepo := repository.NewAdminRepository(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	viewRepo := repository.NewViewRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	aiUsageRepo := repository.NewAIUsageRepository(db)
+	searchStatRepo := repository.NewSearchStatRepository(db)
+	seoRepo := repository.NewSEORepository(db)
+	authTokenRepo := repository.NewAuthTokenRepository(db)
+	authEventRepo := repository.NewAuthEventRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(db)
+	apiTokenRepo := repository.NewAPITokenRepository(db)
+	adminIdentityRepo := repository.NewAdminIdentityRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
 
 	// Initialize Services
-	postService := service.NewPostService(postRepo, tagRepo)
+	postEvents := service.NewPostEventBus()
+	postService := service.NewPostService(postRepo, tagRepo, reactionRepo, postEvents, cfg.SEO.SiteURL)
 	tagService := service.NewTagService(tagRepo)
 	commentService := service.NewCommentService(commentRepo, postRepo)
-	authService := service.NewAuthService(adminRepo)
+	userService := service.NewUserService(adminRepo, authTokenRepo, recoveryCodeRepo)
+	loginThrottleService := service.NewLoginThrottleService(loginThrottleRepo, adminRepo, authEventRepo, cfg.Login)
+	twoFactorService := service.NewTwoFactorService(adminRepo, recoveryCodeRepo, loginThrottleService)
+	authService := service.NewAuthService(adminRepo, authTokenRepo, authEventRepo, twoFactorService, loginThrottleService, cfg.JWT, cfg.Login)
+	apiTokenService := service.NewAPITokenService(apiTokenRepo)
+	oidcService := service.NewOIDCService(adminRepo, adminIdentityRepo, authService, cfg.OIDC, cfg.JWT)
+	auditService := service.NewAuditService(auditLogRepo, postRepo, tagRepo, commentRepo, adminRepo, cfg.Audit)
+	reactionService := service.NewReactionService(reactionRepo, postRepo, cfg.Analytics.Salt)
+	reportService := service.NewReportService(reportRepo)
+	viewService := service.NewViewService(cfg.Analytics, cfg.SEO.SiteURL, viewRepo)
+	statsService := service.NewStatsService(statsRepo)
+	feedService := service.NewFeedService(cfg.Feed, postRepo, tagRepo)
+	sitemapService := service.NewSitemapService(cfg.SEO, postRepo, tagRepo)
+	pageService := service.NewPageService(cfg.Feed, postRepo)
+	ogImageService := service.NewOGImageService(cfg.Feed, postRepo)
+	searchStatsService := service.NewSearchStatsService(searchStatRepo, cfg.Analytics)
+	indexNowService := service.NewIndexNowService(cfg.SEO, seoRepo)
+	searchEngines := service.NewSearchEnginePushers(cfg.SEO, service.SearchEngineDeps{IndexNow: indexNowService})
+	seoService := service.NewSEOService(cfg.SEO, postRepo, seoRepo, searchEngines)
+
+	// Queue search engine pushes when posts change
+	postEvents.Subscribe(seoService.HandlePostEvent)
 
 	// Initialize AI Service (optional, won't crash if not configured)
 	aiService, err := service.NewAIServiceFromEnv()
@@ -41,31 +87,49 @@ func NewRouter(db *gorm.DB) *Router {
 	if err != nil {
 		log.Printf("AI service not available: %v", err)
 	} else {
-		aiHandler = v1.NewAIHandler(aiService)
+		aiHandler = v1.NewAIHandler(service.NewAIUsageRecorder(aiService, aiUsageRepo))
 	}
 
-	// Initialize OSS Service (optional)
-	ossService, err := service.NewOSSServiceFromEnv()
-	var uploadHandler *v1.UploadHandler
+	// Initialize upload storage, local disk unless S3 or OSS is configured
+	storage, err := service.NewStorage(cfg.Storage)
 	if err != nil {
-		log.Printf("OSS service not available: %v", err)
-	} else {
-		uploadHandler = v1.NewUploadHandler(ossService)
+		log.Fatalf("Upload storage not available: %v", err)
+	}
+	log.Printf("Uploads are stored with the %s storage driver", storage.Name())
+	uploadService, err := service.NewUploadService(storage, cfg.Upload)
+	if err != nil {
+		log.Fatalf("Invalid upload configuration: %v", err)
 	}
 
 	// Initialize Handlers
-	postHandler := v1.NewPostHandler(postService)
+	postHandler := v1.NewPostHandler(postService, viewService, searchStatsService)
 	tagHandler := v1.NewTagHandler(tagService)
 	commentHandler := v1.NewCommentHandler(commentService)
 	authHandler := v1.NewAuthHandler(authService)
+	twoFactorHandler := v1.NewTwoFactorHandler(twoFactorService)
+	loginThrottleHandler := v1.NewLoginThrottleHandler(loginThrottleService)
+	userHandler := v1.NewUserHandler(userService)
+	apiTokenHandler := v1.NewAPITokenHandler(apiTokenService)
+	oidcHandler := v1.NewOIDCHandler(oidcService)
+	auditHandler := v1.NewAuditHandler(auditService)
+	reactionHandler := v1.NewReactionHandler(reactionService)
+	reportHandler := v1.NewReportHandler(reportService)
+	statsHandler := v1.NewStatsHandler(statsService)
+	feedHandler := v1.NewFeedHandler(feedService, cfg.SEO.SiteURL)
+	sitemapHandler := v1.NewSitemapHandler(sitemapService, cfg.SEO.SiteURL)
+	pageHandler := v1.NewPageHandler(pageService, cfg.SEO.SiteURL)
+	ogImageHandler := v1.NewOGImageHandler(ogImageService)
+	seoHandler := v1.NewSEOHandler(seoService)
+	indexNowHandler := v1.NewIndexNowHandler(indexNowService)
+	uploadHandler := v1.NewUploadHandler(uploadService)
 
 	// API v1 Routes
 	apiV1 := engine.Group("/api/v1")
 	{
 		// Public Routes
 		// Posts
-		apiV1.GET("/posts", postHandler.GetPosts)
-		apiV1.GET("/posts/:id", postHandler.GetPostByID)
+		apiV1.GET("/posts", middleware.OptionalAuthMiddleware(authService), postHandler.GetPosts)
+		apiV1.GET("/posts/:id", middleware.OptionalAuthMiddleware(authService), postHandler.GetPostByID)
 
 		// Tags
 		apiV1.GET("/tags", tagHandler.GetTags)
@@ -75,43 +139,121 @@ func NewRouter(db *gorm.DB) *Router {
 		apiV1.POST("/posts/:id/comments", commentHandler.CreateComment)
 		apiV1.POST("/comments/:id/guest-reply", commentHandler.GuestReplyComment)
 
+		// Reactions
+		apiV1.POST("/posts/:id/reactions", reactionHandler.React)
+		apiV1.DELETE("/posts/:id/reactions/:reaction", reactionHandler.Unreact)
+
 		// Auth
 		apiV1.POST("/auth/login", authHandler.Login)
+		apiV1.POST("/auth/login/2fa", authHandler.VerifyLogin)
+		apiV1.POST("/auth/refresh", authHandler.Refresh)
+
+		// Single sign-on
+		apiV1.GET("/auth/oidc/providers", oidcHandler.ListProviders)
+		apiV1.POST("/auth/oidc/:provider/start", oidcHandler.Start)
+		apiV1.POST("/auth/oidc/callback", oidcHandler.Callback)
 
-		// Protected Routes (Admin)
+		// Protected Routes (Admin), every change is recorded in the audit log
 		admin := apiV1.Group("")
-		admin.Use(middleware.AuthMiddleware(authService))
+		admin.Use(middleware.AuthMiddleware(authService, apiTokenService), middleware.AuditMiddleware(auditService))
 		{
 			// Auth
 			admin.GET("/auth/me", authHandler.GetCurrentUser)
 
-			// Posts (Admin)
-			admin.GET("/admin/posts", postHandler.GetAllPosts)
-			admin.POST("/posts", postHandler.CreatePost)
-			admin.PUT("/posts/:id", postHandler.UpdatePost)
-			admin.DELETE("/posts/:id", postHandler.DeletePost)
+			// Own account, not with API tokens
+			account := admin.Group("", middleware.RequireSession())
+			account.POST("/auth/logout", authHandler.Logout)
+			account.POST("/auth/logout-all", authHandler.LogoutAll)
+			account.GET("/auth/sessions", authHandler.ListSessions)
+			account.DELETE("/auth/sessions/:id", authHandler.RevokeSession)
+			account.GET("/auth/login-history", authHandler.GetLoginHistory)
+			account.PUT("/auth/me", userHandler.UpdateProfile)
+			account.PUT("/auth/password", userHandler.ChangePassword)
+
+			// Two-factor authentication
+			account.GET("/auth/2fa", twoFactorHandler.GetStatus)
+			account.POST("/auth/2fa/setup", twoFactorHandler.Setup)
+			account.POST("/auth/2fa/enable", twoFactorHandler.Enable)
+			account.POST("/auth/2fa/disable", twoFactorHandler.Disable)
+			account.POST("/auth/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+
+			// Single sign-on identities
+			account.POST("/auth/oidc/:provider/link/start", oidcHandler.StartLink)
+			account.POST("/auth/oidc/link", oidcHandler.Link)
+
+			// Personal API tokens
+			account.GET("/auth/tokens", apiTokenHandler.ListTokens)
+			account.POST("/auth/tokens", apiTokenHandler.CreateToken)
+			account.DELETE("/auth/tokens/:id", apiTokenHandler.RevokeToken)
+
+			// Login lockouts (Owner)
+			security := admin.Group("", middleware.RequirePermission(service.PermManageSecurity))
+			security.GET("/auth/lockouts", loginThrottleHandler.ListLocked)
+			security.POST("/auth/lockouts/unlock", loginThrottleHandler.Unlock)
+
+			// Users (Owner)
+			users := admin.Group("", middleware.RequirePermission(service.PermManageUsers))
+			users.GET("/admin/users", userHandler.ListUsers)
+			users.POST("/admin/users", userHandler.CreateUser)
+			users.GET("/admin/users/:id", userHandler.GetUser)
+			users.PUT("/admin/users/:id", userHandler.UpdateUser)
+			users.DELETE("/admin/users/:id", userHandler.DeleteUser)
+			users.POST("/admin/users/:id/reset-2fa", userHandler.ResetTwoFactor)
+
+			// Audit log (Owner)
+			admin.GET("/admin/audit", middleware.RequirePermission(service.PermViewAudit), auditHandler.ListAuditLogs)
+
+			// Posts (Admin) - authors may only edit their own, see PostService
+			posts := admin.Group("", middleware.RequirePermission(service.PermWritePosts))
+			posts.GET("/admin/posts", postHandler.GetAllPosts)
+			posts.POST("/posts", postHandler.CreatePost)
+			posts.PUT("/posts/:id", postHandler.UpdatePost)
+			posts.DELETE("/posts/:id", postHandler.DeletePost)
 
 			// Tags (Admin)
-			admin.POST("/tags", tagHandler.CreateTag)
-			admin.DELETE("/tags/:id", tagHandler.DeleteTag)
+			tags := admin.Group("", middleware.RequirePermission(service.PermManageTags))
+			tags.POST("/tags", tagHandler.CreateTag)
+			tags.DELETE("/tags/:id", tagHandler.DeleteTag)
 
 			// Comments (Admin)
-			admin.GET("/admin/comments", commentHandler.GetAllComments)
-			admin.POST("/comments/:id/reply", commentHandler.ReplyComment)
-			admin.DELETE("/comments/:id", commentHandler.DeleteComment)
+			comments := admin.Group("", middleware.RequirePermission(service.PermModerateComment))
+			comments.GET("/admin/comments", commentHandler.GetAllComments)
+			comments.POST("/comments/:id/reply", commentHandler.ReplyComment)
+			comments.DELETE("/comments/:id", commentHandler.DeleteComment)
+
+			// Reports and stats (Admin)
+			stats := admin.Group("", middleware.RequirePermission(service.PermViewStats))
+			stats.GET("/admin/reports/engagement", reportHandler.GetEngagementReport)
+			stats.GET("/admin/stats/overview", statsHandler.GetOverview)
+			stats.GET("/admin/stats/views", statsHandler.GetViewSeries)
+			stats.GET("/admin/stats/top-posts", statsHandler.GetTopPosts)
+			stats.GET("/admin/stats/referrers", statsHandler.GetTopReferrers)
+			stats.GET("/admin/stats/tags", statsHandler.GetTopTags)
+			stats.GET("/admin/stats/ai", statsHandler.GetAIUsage)
+			stats.GET("/admin/stats/posts/:id/sources", statsHandler.GetPostSources)
+			stats.GET("/admin/stats/searches", statsHandler.GetSearchTerms)
+			stats.GET("/admin/stats/searches/zero-results", statsHandler.GetZeroResultSearches)
+
+			// SEO push (Admin)
+			seo := admin.Group("", middleware.RequirePermission(service.PermManageSEO))
+			seo.GET("/admin/seo/status", seoHandler.GetStatus)
+			seo.GET("/admin/seo/history", seoHandler.GetHistory)
+			seo.POST("/admin/seo/history/:id/repush", seoHandler.Repush)
+			seo.POST("/admin/seo/push-all", seoHandler.PushAll)
+			seo.POST("/admin/seo/indexnow/rotate", indexNowHandler.RotateKey)
+			seo.GET("/admin/seo/indexnow/check", indexNowHandler.Check)
 
 			// AI (Admin) - only if AI service is available
 			if aiHandler != nil {
-				admin.POST("/ai/excerpt", aiHandler.GenerateExcerpt)
-				admin.POST("/ai/readtime", aiHandler.GenerateReadTime)
-				admin.POST("/ai/tags", aiHandler.GenerateTags)
-				admin.POST("/ai/summarize", aiHandler.SummarizePost)
+				ai := admin.Group("", middleware.RequirePermission(service.PermUseAI))
+				ai.POST("/ai/excerpt", aiHandler.GenerateExcerpt)
+				ai.POST("/ai/readtime", aiHandler.GenerateReadTime)
+				ai.POST("/ai/tags", aiHandler.GenerateTags)
+				ai.POST("/ai/summarize", aiHandler.SummarizePost)
 			}
 
-			// Upload (Admin) - only if OSS service is available
-			if uploadHandler != nil {
-				admin.POST("/upload", uploadHandler.UploadFile)
-			}
+			// Upload (Admin)
+			admin.POST("/upload", middleware.RequirePermission(service.PermUpload), uploadHandler.UploadFile)
 		}
 
 		// AI Chat (Public - rate limited in production)
@@ -121,6 +263,36 @@ func NewRouter(db *gorm.DB) *Router {
 		}
 	}
 
+	// Feeds (RSS 2.0, Atom 1.0, JSON Feed 1.1), also per tag
+	feedMethods := []string{http.MethodGet, http.MethodHead}
+	engine.Match(feedMethods, "/feed.xml", feedHandler.RSS)
+	engine.Match(feedMethods, "/atom.xml", feedHandler.Atom)
+	engine.Match(feedMethods, "/feed.json", feedHandler.JSON)
+	engine.Match(feedMethods, "/tags/:slug/feed.xml", feedHandler.RSS)
+	engine.Match(feedMethods, "/tags/:slug/atom.xml", feedHandler.Atom)
+	engine.Match(feedMethods, "/tags/:slug/feed.json", feedHandler.JSON)
+	engine.GET("/rss.xml", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/feed.xml")
+	})
+
+	// Sitemap and robots.txt
+	engine.GET("/sitemap.xml", sitemapHandler.Sitemap)
+	engine.GET("/sitemaps/:page", sitemapHandler.SitemapPage)
+	engine.GET("/robots.txt", sitemapHandler.Robots)
+
+	// Server-rendered post pages and share images for crawlers and link previews
+	engine.Match(feedMethods, "/p/:slug", pageHandler.Post)
+	engine.Match(feedMethods, "/og/posts/:file", ogImageHandler.PostImage)
+
+	// Uploads of the local storage driver
+	if cfg.Storage.Driver == config.StorageLocal {
+		localFileHandler := v1.NewLocalFileHandler(filepath.Join(cfg.Storage.Local.Dir, service.UploadPrefix))
+		engine.Match(feedMethods, "/"+service.UploadPrefix+"/*filepath", localFileHandler.Serve)
+	}
+
+	// IndexNow key verification file, /{key}.txt
+	engine.GET("/:file", indexNowHandler.KeyFile)
+
 	// Health Check
 	engine.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -129,7 +301,33 @@ func NewRouter(db *gorm.DB) *Router {
 	// Swagger Documentation
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	return &Router{engine: engine}
+	return &Router{
+		engine:             engine,
+		authService:        authService,
+		auditService:       auditService,
+		viewService:        viewService,
+		searchStatsService: searchStatsService,
+		seoService:         seoService,
+	}
+}
+
+// Start runs the router's background workers until ctx is cancelled
+func (r *Router) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, worker := range []func(context.Context){
+		r.viewService.Start,
+		r.searchStatsService.Start,
+		r.seoService.Start,
+		r.authService.Start,
+		r.auditService.Start,
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker(ctx)
+		}()
+	}
+	wg.Wait()
 }
 
 func (r *Router) Run(addr string) error {
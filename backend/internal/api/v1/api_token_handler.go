@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"backend/internal/api/middleware"
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APITokenHandler struct {
+	apiTokenService service.APITokenService
+}
+
+func NewAPITokenHandler(apiTokenService service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{apiTokenService: apiTokenService}
+}
+
+// ListTokens godoc
+// @Summary List own API tokens
+// @Description Tokens that are not revoked; the secret is never returned again, only its prefix
+// @Tags auth
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=[]dto.APITokenResponse}
+// @Router /auth/tokens [get]
+func (h *APITokenHandler) ListTokens(c *gin.Context) {
+	tokens, err := h.apiTokenService.ListTokens(middleware.ActorFromContext(c).ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch API tokens"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(tokens))
+}
+
+// CreateToken godoc
+// @Summary Create an API token
+// @Description Long-lived token for scripts, sent as "Authorization: Bearer dlp_...". It acts as you, limited to its scopes: posts:write, posts:edit_any, tags:manage, comments:moderate, stats:view, seo:manage, ai:use, files:upload. The token is returned only in this response.
+// @Tags auth
+// @Security BearerAuth
+// @Param token body dto.CreateAPITokenRequest true "Name, scopes and expiry"
+// @Success 201 {object} dto.APIResponse{data=dto.CreatedAPITokenResponse}
+// @Failure 400 {object} dto.APIResponse "Unknown scope"
+// @Failure 403 {object} dto.APIResponse "Your role does not grant a scope"
+// @Router /auth/tokens [post]
+func (h *APITokenHandler) CreateToken(c *gin.Context) {
+	var req dto.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	token, err := h.apiTokenService.CreateToken(middleware.ActorFromContext(c), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidScope):
+			c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, dto.Error(403, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to create API token"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Created(token))
+}
+
+// RevokeToken godoc
+// @Summary Revoke an API token
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "Token ID"
+// @Success 200 {object} dto.APIResponse
+// @Failure 404 {object} dto.APIResponse
+// @Router /auth/tokens/{id} [delete]
+func (h *APITokenHandler) RevokeToken(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid token ID"))
+		return
+	}
+
+	if err := h.apiTokenService.RevokeToken(middleware.ActorFromContext(c).ID, id); err != nil {
+		if errors.Is(err, service.ErrAPITokenNotFound) {
+			c.JSON(http.StatusNotFound, dto.Error(404, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to revoke API token"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(nil))
+}
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditLogs godoc
+// @Summary List the audit log
+// @Description Mutating admin requests, newest first, with the changed fields of the target. Refused and failed requests are included with their status.
+// @Tags audit
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param actor_id query string false "Admin ID"
+// @Param action query string false "Action, e.g. post.update"
+// @Param target_type query string false "Target type" Enums(post, tag, comment, user, session, api_token, lockout, seo, upload)
+// @Param target_id query string false "Target ID"
+// @Param since query string false "First day, YYYY-MM-DD"
+// @Param until query string false "Last day, YYYY-MM-DD"
+// @Success 200 {object} dto.APIResponse{data=dto.AuditLogListResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Router /admin/audit [get]
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	var query dto.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.auditService.List(query)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAuditFilter) {
+			c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch audit log"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
@@ -3,7 +3,10 @@ package v1
 import (
 	"backend/internal/model/dto"
 	"backend/internal/service"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,9 +22,12 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 
 // Login godoc
 // @Summary Admin login
+// @Description For admins with two-factor authentication only twoFactorRequired and a challengeToken are returned, to be completed at /auth/login/2fa
 // @Tags auth
 // @Param credentials body dto.LoginRequest true "Login credentials"
 // @Success 200 {object} dto.APIResponse{data=dto.LoginResponse}
+// @Failure 401 {object} dto.APIResponse
+// @Failure 429 {object} dto.APIResponse "Too many failed attempts, see the Retry-After header"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
@@ -30,8 +36,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(req)
+	response, err := h.authService.Login(req, visitorFromRequest(c))
 	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
 		c.JSON(http.StatusUnauthorized, dto.Error(401, "Invalid credentials"))
 		return
 	}
@@ -39,11 +48,202 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.Success(response))
 }
 
+// VerifyLogin godoc
+// @Summary Complete a login with a two-factor code
+// @Description Exchanges the challenge token from /auth/login and a 6 digit authenticator code or a recovery code for tokens
+// @Tags auth
+// @Param request body dto.TwoFactorLoginRequest true "Challenge and code"
+// @Success 200 {object} dto.APIResponse{data=dto.LoginResponse}
+// @Failure 401 {object} dto.APIResponse
+// @Failure 429 {object} dto.APIResponse "Too many failed attempts, see the Retry-After header"
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) VerifyLogin(c *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.authService.VerifyLogin(req, visitorFromRequest(c))
+	if err != nil {
+		if respondThrottled(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidChallenge) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			c.JSON(http.StatusUnauthorized, dto.Error(401, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to verify login"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Refresh godoc
+// @Summary Exchange a refresh token for new tokens
+// @Description Each refresh token works once. Presenting a used one again revokes its session.
+// @Tags auth
+// @Param request body dto.RefreshRequest true "Refresh token"
+// @Success 200 {object} dto.APIResponse{data=dto.LoginResponse}
+// @Failure 401 {object} dto.APIResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.authService.Refresh(req.RefreshToken, visitorFromRequest(c))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, dto.Error(401, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to refresh token"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Logout godoc
+// @Summary Log out the current session
+// @Tags auth
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	if err := h.authService.Logout(token); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to log out"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// LogoutAll godoc
+// @Summary Log out all sessions of the current user
+// @Tags auth
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.LogoutAllResponse}
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	revoked, err := h.authService.LogoutAll(token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to log out"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(dto.LogoutAllResponse{Revoked: revoked}))
+}
+
+// ListSessions godoc
+// @Summary List active sessions of the current user
+// @Description One session per login, newest activity first; current marks the session of this request
+// @Tags auth
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=[]dto.SessionResponse}
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch sessions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(sessions))
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session of the current user
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} dto.APIResponse
+// @Failure 404 {object} dto.APIResponse
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid session ID"))
+		return
+	}
+
+	if err := h.authService.RevokeSession(token.AdminID, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, dto.Error(404, "Session not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to revoke session"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// GetLoginHistory godoc
+// @Summary Get login history of the current user
+// @Description Successful and failed login attempts, newest first
+// @Tags auth
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} dto.APIResponse{data=dto.LoginHistoryResponse}
+// @Router /auth/login-history [get]
+func (h *AuthHandler) GetLoginHistory(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	var query dto.LoginHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.authService.GetLoginHistory(token.AdminID, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch login history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
 // GetCurrentUser godoc
 // @Summary Get current logged in user
+// @Description Includes the 10 most recent login attempts
 // @Tags auth
 // @Security BearerAuth
-// @Success 200 {object} dto.APIResponse{data=dto.AdminResponse}
+// @Success 200 {object} dto.APIResponse{data=dto.CurrentUserResponse}
 // @Router /auth/me [get]
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	adminID, exists := c.Get("adminID")
@@ -58,7 +258,7 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.GetAdminByID(uid)
+	response, err := h.authService.GetCurrentUser(uid)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to get user info"))
 		return
@@ -66,3 +266,28 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.Success(response))
 }
+
+// respondThrottled answers 429 with Retry-After while a login is throttled.
+// The message stays the generic one, so it does not tell whether the
+// username exists.
+func respondThrottled(c *gin.Context, err error) bool {
+	var throttled *service.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+
+	seconds := int(math.Ceil(throttled.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, dto.Error(429, "Invalid credentials"))
+	return true
+}
+
+// accessTokenFromContext returns the token AuthMiddleware validated
+func accessTokenFromContext(c *gin.Context) (*service.AccessToken, bool) {
+	value, exists := c.Get("accessToken")
+	if !exists {
+		return nil, false
+	}
+	token, ok := value.(*service.AccessToken)
+	return token, ok
+}
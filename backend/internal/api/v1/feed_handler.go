@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedHandler serves the syndication feeds at the site root, outside the
+// JSON API.
+type FeedHandler struct {
+	feedService service.FeedService
+	siteURL     string
+}
+
+func NewFeedHandler(feedService service.FeedService, siteURL string) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		siteURL:     siteURL,
+	}
+}
+
+// RSS serves /feed.xml and /tags/:slug/feed.xml
+func (h *FeedHandler) RSS(c *gin.Context) {
+	h.serve(c, service.FeedRSS)
+}
+
+// Atom serves /atom.xml and /tags/:slug/atom.xml
+func (h *FeedHandler) Atom(c *gin.Context) {
+	h.serve(c, service.FeedAtom)
+}
+
+// JSON serves /feed.json and /tags/:slug/feed.json
+func (h *FeedHandler) JSON(c *gin.Context) {
+	h.serve(c, service.FeedJSON)
+}
+
+// serve renders a feed. Full post content is included unless the query
+// asks for ?content=excerpt. Conditional GETs are answered by
+// http.ServeContent using the ETag and Last-Modified of the feed.
+func (h *FeedHandler) serve(c *gin.Context, format string) {
+	var query dto.FeedQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	siteURL, ok := siteURLFromRequest(c, h.siteURL)
+	if !ok {
+		c.JSON(http.StatusNotFound, dto.Error(404, "Not found"))
+		return
+	}
+
+	// The request is the render cache key, so other query parameters are
+	// left out of the feed path
+	excerptOnly := query.Content == "excerpt"
+	feedPath := c.Request.URL.Path
+	if excerptOnly {
+		feedPath += "?content=excerpt"
+	}
+
+	doc, err := h.feedService.GetFeed(service.FeedRequest{
+		Format:      format,
+		Tag:         c.Param("slug"),
+		ExcerptOnly: excerptOnly,
+		SiteURL:     siteURL,
+		FeedPath:    feedPath,
+		Uncached:    h.siteURL == "",
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrFeedTagNotFound) {
+			c.JSON(http.StatusNotFound, dto.Error(404, "Tag not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to generate feed"))
+		return
+	}
+
+	c.Header("Content-Type", doc.ContentType)
+	c.Header("ETag", doc.ETag)
+	c.Header("Cache-Control", siteCacheControl(h.siteURL, 300))
+	http.ServeContent(c.Writer, c.Request, "", doc.LastModified, bytes.NewReader(doc.Body))
+}
@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IndexNowHandler struct {
+	indexNowService service.IndexNowService
+}
+
+func NewIndexNowHandler(indexNowService service.IndexNowService) *IndexNowHandler {
+	return &IndexNowHandler{indexNowService: indexNowService}
+}
+
+// KeyFile serves the IndexNow key verification file /{key}.txt at the site root
+func (h *IndexNowHandler) KeyFile(c *gin.Context) {
+	name := c.Param("file")
+	if !strings.HasSuffix(name, ".txt") {
+		c.String(http.StatusNotFound, "Not found")
+		return
+	}
+
+	key, err := h.indexNowService.GetKeyFile(strings.TrimSuffix(name, ".txt"))
+	if err != nil {
+		if errors.Is(err, service.ErrIndexNowKeyNotFound) {
+			c.String(http.StatusNotFound, "Not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Failed to read key")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.String(http.StatusOK, key)
+}
+
+// RotateKey godoc
+// @Summary Rotate the IndexNow key (Admin)
+// @Description Generates a new key for pushes. The previous key is still served for 7 days.
+// @Tags seo
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.IndexNowKeyResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Router /admin/seo/indexnow/rotate [post]
+func (h *IndexNowHandler) RotateKey(c *gin.Context) {
+	response, err := h.indexNowService.RotateKey()
+	if err != nil {
+		h.respondError(c, err, "Failed to rotate IndexNow key")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Check godoc
+// @Summary Check IndexNow key verification (Admin)
+// @Description Fetches the key file through SEO_SITE_URL like a search engine would and lists any problems
+// @Tags seo
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.IndexNowCheckResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Router /admin/seo/indexnow/check [get]
+func (h *IndexNowHandler) Check(c *gin.Context) {
+	response, err := h.indexNowService.Check()
+	if err != nil {
+		h.respondError(c, err, "Failed to check IndexNow key")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+func (h *IndexNowHandler) respondError(c *gin.Context, err error, message string) {
+	if errors.Is(err, service.ErrIndexNowDisabled) {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.Error(500, message))
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginThrottleHandler struct {
+	throttleService service.LoginThrottleService
+}
+
+func NewLoginThrottleHandler(throttleService service.LoginThrottleService) *LoginThrottleHandler {
+	return &LoginThrottleHandler{throttleService: throttleService}
+}
+
+// ListLocked godoc
+// @Summary List locked usernames and IPs
+// @Description Usernames and IPs that may not log in until lockedUntil after repeated failures
+// @Tags auth
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=[]dto.LoginLockoutResponse}
+// @Router /auth/lockouts [get]
+func (h *LoginThrottleHandler) ListLocked(c *gin.Context) {
+	lockouts, err := h.throttleService.ListLocked()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch lockouts"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(lockouts))
+}
+
+// Unlock godoc
+// @Summary Unlock a username or IP
+// @Description Lifts the lockout and resets the failed attempts
+// @Tags auth
+// @Security BearerAuth
+// @Param request body dto.UnlockLoginRequest true "Scope and value"
+// @Success 200 {object} dto.APIResponse
+// @Failure 404 {object} dto.APIResponse
+// @Router /auth/lockouts/unlock [post]
+func (h *LoginThrottleHandler) Unlock(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	var req dto.UnlockLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	if err := h.throttleService.Unlock(req, token, visitorFromRequest(c)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrLockoutNotFound):
+			c.JSON(http.StatusNotFound, dto.Error(404, err.Error()))
+		case errors.Is(err, service.ErrInvalidLockScope):
+			c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to unlock"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(nil))
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"backend/internal/service"
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OGImageHandler serves the generated share images of posts
+type OGImageHandler struct {
+	ogImageService service.OGImageService
+}
+
+func NewOGImageHandler(ogImageService service.OGImageService) *OGImageHandler {
+	return &OGImageHandler{ogImageService: ogImageService}
+}
+
+// PostImage serves /og/posts/{id}.png
+func (h *OGImageHandler) PostImage(c *gin.Context) {
+	id, err := uuid.Parse(strings.TrimSuffix(c.Param("file"), ".png"))
+	if err != nil || !strings.HasSuffix(c.Param("file"), ".png") {
+		c.String(http.StatusNotFound, "Not found")
+		return
+	}
+
+	image, err := h.ogImageService.GetPostImage(id)
+	if err != nil {
+		if errors.Is(err, service.ErrOGImageNotFound) {
+			c.String(http.StatusNotFound, "Not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Failed to render image")
+		return
+	}
+
+	c.Header("Content-Type", "image/png")
+	c.Header("ETag", image.ETag)
+	c.Header("Cache-Control", "public, max-age=3600")
+	http.ServeContent(c.Writer, c.Request, "", image.LastModified, bytes.NewReader(image.Body))
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCHandler struct {
+	oidcService service.OIDCService
+}
+
+func NewOIDCHandler(oidcService service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+// ListProviders godoc
+// @Summary List single sign-on providers
+// @Description OpenID Connect providers admins can sign in with; empty when SSO is not configured
+// @Tags auth
+// @Success 200 {object} dto.APIResponse{data=[]dto.OIDCProviderResponse}
+// @Router /auth/oidc/providers [get]
+func (h *OIDCHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.Success(h.oidcService.Providers()))
+}
+
+// Start godoc
+// @Summary Start a single sign-on login
+// @Description Returns the provider URL to send the browser to, using the authorization code flow with PKCE. Keep stateToken to complete the login at /auth/oidc/callback.
+// @Tags auth
+// @Param provider path string true "Provider name"
+// @Success 200 {object} dto.APIResponse{data=dto.OIDCStartResponse}
+// @Failure 404 {object} dto.APIResponse
+// @Failure 502 {object} dto.APIResponse "Provider discovery failed"
+// @Router /auth/oidc/{provider}/start [post]
+func (h *OIDCHandler) Start(c *gin.Context) {
+	response, err := h.oidcService.Start(c.Param("provider"))
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Callback godoc
+// @Summary Complete a single sign-on login
+// @Description Exchanges the code and state the provider redirected back with, and the stateToken from the start, for tokens. Local two-factor authentication is not asked for.
+// @Tags auth
+// @Param request body dto.OIDCCallbackRequest true "Code, state and state token"
+// @Success 200 {object} dto.APIResponse{data=dto.LoginResponse}
+// @Failure 400 {object} dto.APIResponse "Expired or mismatched state"
+// @Failure 401 {object} dto.APIResponse
+// @Failure 403 {object} dto.APIResponse "No admin account is linked to the identity, or the admin must link it from their account"
+// @Router /auth/oidc/callback [post]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.oidcService.Callback(req, visitorFromRequest(c))
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// StartLink godoc
+// @Summary Start linking a single sign-on identity
+// @Description Like /auth/oidc/{provider}/start, but the identity is linked to the current admin at /auth/oidc/link. Admins with two-factor authentication and the owner are never linked by email, so they link their identity this way.
+// @Tags auth
+// @Security BearerAuth
+// @Param provider path string true "Provider name"
+// @Success 200 {object} dto.APIResponse{data=dto.OIDCStartResponse}
+// @Failure 404 {object} dto.APIResponse
+// @Failure 502 {object} dto.APIResponse "Provider discovery failed"
+// @Router /auth/oidc/{provider}/link/start [post]
+func (h *OIDCHandler) StartLink(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	response, err := h.oidcService.StartLink(c.Param("provider"), token.AdminID)
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Link godoc
+// @Summary Link a single sign-on identity to the current user
+// @Description Completes /auth/oidc/{provider}/link/start with the code and state the provider redirected back with. Later logins with the identity sign in as the current admin.
+// @Tags auth
+// @Security BearerAuth
+// @Param request body dto.OIDCCallbackRequest true "Code, state and state token"
+// @Success 200 {object} dto.APIResponse{data=dto.OIDCIdentityResponse}
+// @Failure 400 {object} dto.APIResponse "Expired or mismatched state"
+// @Failure 401 {object} dto.APIResponse
+// @Failure 409 {object} dto.APIResponse "Identity is linked to another admin"
+// @Router /auth/oidc/link [post]
+func (h *OIDCHandler) Link(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.oidcService.Link(req, token.AdminID)
+	if err != nil {
+		respondOIDCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+func respondOIDCError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOIDCProviderNotFound):
+		c.JSON(http.StatusNotFound, dto.Error(404, err.Error()))
+	case errors.Is(err, service.ErrOIDCProviderUnavailable):
+		c.JSON(http.StatusBadGateway, dto.Error(502, err.Error()))
+	case errors.Is(err, service.ErrInvalidOIDCState):
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+	case errors.Is(err, service.ErrOIDCLoginFailed):
+		c.JSON(http.StatusUnauthorized, dto.Error(401, err.Error()))
+	case errors.Is(err, service.ErrOIDCNoAccount), errors.Is(err, service.ErrOIDCLinkRequired):
+		c.JSON(http.StatusForbidden, dto.Error(403, err.Error()))
+	case errors.Is(err, service.ErrOIDCIdentityLinked):
+		c.JSON(http.StatusConflict, dto.Error(409, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to sign in"))
+	}
+}
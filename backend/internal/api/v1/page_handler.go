@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"backend/internal/service"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PageHandler serves the server-rendered post pages at /p/{slug}
+type PageHandler struct {
+	pageService service.PageService
+	siteURL     string
+}
+
+func NewPageHandler(pageService service.PageService, siteURL string) *PageHandler {
+	return &PageHandler{
+		pageService: pageService,
+		siteURL:     siteURL,
+	}
+}
+
+// Post serves /p/{slug}. Crawlers and link previews get the rendered
+// page, browsers are redirected to the post in the SPA.
+func (h *PageHandler) Post(c *gin.Context) {
+	siteURL, ok := siteURLFromRequest(c, h.siteURL)
+	if !ok {
+		c.String(http.StatusNotFound, "Not found")
+		return
+	}
+	slug := c.Param("slug")
+
+	page, err := h.pageService.RenderPost(siteURL, slug)
+	if err != nil {
+		if errors.Is(err, service.ErrPageNotFound) {
+			c.String(http.StatusNotFound, "Not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Failed to render page")
+		return
+	}
+
+	if !visitorFromRequest(c).IsBot() {
+		c.Redirect(http.StatusFound, "/?post="+page.PostID.String())
+		return
+	}
+
+	// Posts linked by ID move to their slug URL
+	if slug != page.Slug {
+		c.Redirect(http.StatusMovedPermanently, "/p/"+url.PathEscape(page.Slug))
+		return
+	}
+
+	c.Header("Cache-Control", siteCacheControl(h.siteURL, 600))
+	c.Header("Vary", "User-Agent")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.HTML)
+}
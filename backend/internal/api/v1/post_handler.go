@@ -1,8 +1,10 @@
 package v1
 
 import (
+	"backend/internal/api/middleware"
 	"backend/internal/model/dto"
 	"backend/internal/service"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,11 +12,17 @@ import (
 )
 
 type PostHandler struct {
-	postService service.PostService
+	postService        service.PostService
+	viewService        service.ViewService
+	searchStatsService service.SearchStatsService
 }
 
-func NewPostHandler(postService service.PostService) *PostHandler {
-	return &PostHandler{postService: postService}
+func NewPostHandler(postService service.PostService, viewService service.ViewService, searchStatsService service.SearchStatsService) *PostHandler {
+	return &PostHandler{
+		postService:        postService,
+		viewService:        viewService,
+		searchStatsService: searchStatsService,
+	}
 }
 
 // GetPosts godoc
@@ -45,6 +53,11 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 		return
 	}
 
+	// Record the search term once per search, not for every page of results
+	if _, isAdmin := c.Get("adminID"); !isAdmin && query.Search != "" && query.Page <= 1 {
+		h.searchStatsService.Track(query.Search, response.Total, visitorFromRequest(c))
+	}
+
 	c.JSON(http.StatusOK, dto.Success(response))
 }
 
@@ -52,6 +65,10 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 // @Summary Get post by ID
 // @Tags posts
 // @Param id path string true "Post ID"
+// @Param ref query string false "Page referrer (document.referrer), defaults to the Referer header"
+// @Param utm_source query string false "Campaign source"
+// @Param utm_medium query string false "Campaign medium"
+// @Param utm_campaign query string false "Campaign name"
 // @Success 200 {object} dto.APIResponse{data=dto.PostResponse}
 // @Router /posts/{id} [get]
 func (h *PostHandler) GetPostByID(c *gin.Context) {
@@ -63,6 +80,22 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 		return
 	}
 
+	// Count the view unless it is an admin previewing the post
+	if _, isAdmin := c.Get("adminID"); !isAdmin && response.IsPublished {
+		if postID, err := uuid.Parse(response.ID); err == nil {
+			source := service.TrafficSource{
+				Referrer:    c.Query("ref"),
+				UTMSource:   c.Query("utm_source"),
+				UTMMedium:   c.Query("utm_medium"),
+				UTMCampaign: c.Query("utm_campaign"),
+			}
+			if source.Referrer == "" {
+				source.Referrer = c.Request.Referer()
+			}
+			h.viewService.Track(postID, visitorFromRequest(c), source)
+		}
+	}
+
 	c.JSON(http.StatusOK, dto.Success(response))
 }
 
@@ -72,6 +105,8 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 // @Security BearerAuth
 // @Param post body dto.CreatePostRequest true "Post data"
 // @Success 201 {object} dto.APIResponse{data=dto.PostResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Failure 409 {object} dto.APIResponse "Slug already in use"
 // @Router /posts [post]
 func (h *PostHandler) CreatePost(c *gin.Context) {
 	var req dto.CreatePostRequest
@@ -90,7 +125,9 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 	response, err := h.postService.CreatePost(req, authorID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to create post"))
+		if !h.respondSlugError(c, err) {
+			c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to create post"))
+		}
 		return
 	}
 
@@ -104,6 +141,9 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 // @Param id path string true "Post ID"
 // @Param post body dto.UpdatePostRequest true "Post data"
 // @Success 200 {object} dto.APIResponse{data=dto.PostResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Failure 403 {object} dto.APIResponse "Authors may only edit their own posts"
+// @Failure 409 {object} dto.APIResponse "Slug already in use"
 // @Router /posts/{id} [put]
 func (h *PostHandler) UpdatePost(c *gin.Context) {
 	id := c.Param("id")
@@ -114,9 +154,15 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	response, err := h.postService.UpdatePost(id, req)
+	response, err := h.postService.UpdatePost(id, req, middleware.ActorFromContext(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to update post"))
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, dto.Error(403, err.Error()))
+			return
+		}
+		if !h.respondSlugError(c, err) {
+			c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to update post"))
+		}
 		return
 	}
 
@@ -129,11 +175,16 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Post ID"
 // @Success 200 {object} dto.APIResponse
+// @Failure 403 {object} dto.APIResponse "Authors may only delete their own posts"
 // @Router /posts/{id} [delete]
 func (h *PostHandler) DeletePost(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.postService.DeletePost(id); err != nil {
+	if err := h.postService.DeletePost(id, middleware.ActorFromContext(c)); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, dto.Error(403, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to delete post"))
 		return
 	}
@@ -171,3 +222,16 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.Success(response))
 }
+
+// respondSlugError answers invalid or taken slugs and reports whether it did
+func (h *PostHandler) respondSlugError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidSlug):
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+	case errors.Is(err, service.ErrSlugTaken):
+		c.JSON(http.StatusConflict, dto.Error(409, err.Error()))
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReactionHandler struct {
+	reactionService service.ReactionService
+}
+
+func NewReactionHandler(reactionService service.ReactionService) *ReactionHandler {
+	return &ReactionHandler{reactionService: reactionService}
+}
+
+// React godoc
+// @Summary React to a post
+// @Description Record an anonymous reaction ("was this helpful") on a post, deduplicated per visitor
+// @Tags reactions
+// @Param id path string true "Post ID"
+// @Param reaction body dto.ReactRequest true "Reaction data"
+// @Success 200 {object} dto.APIResponse{data=dto.ReactionSummaryResponse}
+// @Router /posts/{id}/reactions [post]
+func (h *ReactionHandler) React(c *gin.Context) {
+	postID := c.Param("id")
+
+	var req dto.ReactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.reactionService.React(postID, req.Reaction, visitorFromRequest(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Unreact godoc
+// @Summary Remove a reaction from a post
+// @Tags reactions
+// @Param id path string true "Post ID"
+// @Param reaction path string true "Reaction type"
+// @Success 200 {object} dto.APIResponse{data=dto.ReactionSummaryResponse}
+// @Router /posts/{id}/reactions/{reaction} [delete]
+func (h *ReactionHandler) Unreact(c *gin.Context) {
+	postID := c.Param("id")
+	reaction := c.Param("reaction")
+
+	response, err := h.reactionService.Unreact(postID, reaction, visitorFromRequest(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
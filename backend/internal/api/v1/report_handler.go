@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService service.ReportService
+}
+
+func NewReportHandler(reportService service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// GetEngagementReport godoc
+// @Summary Get post engagement ranking (Admin)
+// @Description Rank published posts by reactions, comments and views over a time window
+// @Tags reports
+// @Security BearerAuth
+// @Param days query int false "Time window in days" default(30)
+// @Param limit query int false "Number of posts" default(10)
+// @Success 200 {object} dto.APIResponse{data=dto.EngagementReportResponse}
+// @Router /admin/reports/engagement [get]
+func (h *ReportHandler) GetEngagementReport(c *gin.Context) {
+	var query dto.EngagementReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.reportService.GetEngagementReport(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to build engagement report"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SEOHandler struct {
+	seoService service.SEOService
+}
+
+func NewSEOHandler(seoService service.SEOService) *SEOHandler {
+	return &SEOHandler{seoService: seoService}
+}
+
+// GetStatus godoc
+// @Summary Get search engine push status (Admin)
+// @Description Daily quota, URLs pushed today and URLs waiting in the queue per engine
+// @Tags seo
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.SEOStatusResponse}
+// @Router /admin/seo/status [get]
+func (h *SEOHandler) GetStatus(c *gin.Context) {
+	response, err := h.seoService.GetStatus()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch push status"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetHistory godoc
+// @Summary Get search engine push history (Admin)
+// @Description Every push request with its URLs and the engine's response, newest first
+// @Tags seo
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param engine query string false "Filter by engine: baidu, indexnow or google"
+// @Success 200 {object} dto.APIResponse{data=dto.SEOHistoryResponse}
+// @Router /admin/seo/history [get]
+func (h *SEOHandler) GetHistory(c *gin.Context) {
+	var query dto.SEOHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.seoService.GetHistory(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch push history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Repush godoc
+// @Summary Push the URLs of an earlier request again (Admin)
+// @Tags seo
+// @Security BearerAuth
+// @Param id path int true "Push history ID"
+// @Success 200 {object} dto.APIResponse{data=dto.SEOEnqueueResponse}
+// @Failure 404 {object} dto.APIResponse
+// @Router /admin/seo/history/{id}/repush [post]
+func (h *SEOHandler) Repush(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid history ID"))
+		return
+	}
+
+	queued, err := h.seoService.Repush(id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(dto.SEOEnqueueResponse{Queued: queued}))
+}
+
+// PushAll godoc
+// @Summary Queue all public URLs for every search engine (Admin)
+// @Description Queues the homepage, the about page and all published posts
+// @Tags seo
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.SEOEnqueueResponse}
+// @Router /admin/seo/push-all [post]
+func (h *SEOHandler) PushAll(c *gin.Context) {
+	queued, err := h.seoService.EnqueueAll()
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(dto.SEOEnqueueResponse{Queued: queued}))
+}
+
+func (h *SEOHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSEODisabled):
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+	case errors.Is(err, service.ErrSEOHistoryNotFound):
+		c.JSON(http.StatusNotFound, dto.Error(404, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to queue URLs"))
+	}
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validHost matches a Host header that is a host name or an IP address,
+// with an optional port
+var validHost = regexp.MustCompile(`^([A-Za-z0-9.-]+|\[[0-9A-Fa-f:.]+\])(:[0-9]{1,5})?$`)
+
+// siteURLFromRequest returns the configured public site URL, falling back
+// to the scheme and host the request was made to. Those come from the
+// client, so ok is false for a host that is not a plain host name, and
+// output built from them must not be cached, see siteCacheControl.
+func siteURLFromRequest(c *gin.Context, configured string) (siteURL string, ok bool) {
+	if configured != "" {
+		return strings.TrimSuffix(configured, "/"), true
+	}
+	if !validHost.MatchString(c.Request.Host) {
+		return "", false
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	// Proxies in a chain may each append their scheme
+	proto, _, _ := strings.Cut(c.GetHeader("X-Forwarded-Proto"), ",")
+	if proto = strings.ToLower(strings.TrimSpace(proto)); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host, true
+}
+
+// siteCacheControl lets shared caches store output with absolute URLs only
+// when they come from the configured site URL. Built from the request, a
+// single request with a forged Host would poison the cache for everyone.
+func siteCacheControl(configured string, maxAge int) string {
+	if configured == "" {
+		return "no-store"
+	}
+	return fmt.Sprintf("public, max-age=%d", maxAge)
+}
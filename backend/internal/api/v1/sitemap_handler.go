@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"backend/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SitemapHandler serves /sitemap.xml and /robots.txt at the site root
+type SitemapHandler struct {
+	sitemapService service.SitemapService
+	siteURL        string
+}
+
+func NewSitemapHandler(sitemapService service.SitemapService, siteURL string) *SitemapHandler {
+	return &SitemapHandler{
+		sitemapService: sitemapService,
+		siteURL:        siteURL,
+	}
+}
+
+// Sitemap serves /sitemap.xml, a sitemap or a sitemap index
+func (h *SitemapHandler) Sitemap(c *gin.Context) {
+	siteURL, ok := siteURLFromRequest(c, h.siteURL)
+	if !ok {
+		c.String(http.StatusNotFound, "Not found")
+		return
+	}
+
+	body, err := h.sitemapService.GetSitemap(siteURL)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to generate sitemap")
+		return
+	}
+
+	c.Header("Cache-Control", siteCacheControl(h.siteURL, 3600))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// SitemapPage serves /sitemaps/{n}.xml when the sitemap is split
+func (h *SitemapHandler) SitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil || !strings.HasSuffix(c.Param("page"), ".xml") {
+		c.String(http.StatusNotFound, "Not found")
+		return
+	}
+
+	siteURL, ok := siteURLFromRequest(c, h.siteURL)
+	if !ok {
+		c.String(http.StatusNotFound, "Not found")
+		return
+	}
+
+	body, err := h.sitemapService.GetSitemapPage(siteURL, page)
+	if err != nil {
+		if errors.Is(err, service.ErrSitemapNotFound) {
+			c.String(http.StatusNotFound, "Not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Failed to generate sitemap")
+		return
+	}
+
+	c.Header("Cache-Control", siteCacheControl(h.siteURL, 3600))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// Robots serves /robots.txt
+func (h *SitemapHandler) Robots(c *gin.Context) {
+	siteURL, ok := siteURLFromRequest(c, h.siteURL)
+	if !ok {
+		c.String(http.StatusNotFound, "Not found")
+		return
+	}
+
+	c.Header("Cache-Control", siteCacheControl(h.siteURL, 3600))
+	c.String(http.StatusOK, h.sitemapService.GetRobots(siteURL))
+}
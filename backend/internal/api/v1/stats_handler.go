@@ -0,0 +1,242 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StatsHandler struct {
+	statsService service.StatsService
+}
+
+func NewStatsHandler(statsService service.StatsService) *StatsHandler {
+	return &StatsHandler{statsService: statsService}
+}
+
+// GetOverview godoc
+// @Summary Get dashboard overview (Admin)
+// @Description Post counts (published/draft), comments by status and total views
+// @Tags stats
+// @Security BearerAuth
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Success 200 {object} dto.APIResponse{data=dto.StatsOverviewResponse}
+// @Router /admin/stats/overview [get]
+func (h *StatsHandler) GetOverview(c *gin.Context) {
+	var query dto.StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.statsService.GetOverview(query)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetViewSeries godoc
+// @Summary Get views per day or week (Admin)
+// @Tags stats
+// @Security BearerAuth
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Param interval query string false "Bucket size: day or week" default(day)
+// @Success 200 {object} dto.APIResponse{data=dto.ViewSeriesResponse}
+// @Router /admin/stats/views [get]
+func (h *StatsHandler) GetViewSeries(c *gin.Context) {
+	var query dto.ViewSeriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.statsService.GetViewSeries(query)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetTopPosts godoc
+// @Summary Get most viewed posts (Admin)
+// @Tags stats
+// @Security BearerAuth
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {object} dto.APIResponse{data=dto.TopPostsResponse}
+// @Router /admin/stats/top-posts [get]
+func (h *StatsHandler) GetTopPosts(c *gin.Context) {
+	var query dto.StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.statsService.GetTopPosts(query)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetTopReferrers godoc
+// @Summary Get top referrer hosts (Admin)
+// @Tags stats
+// @Security BearerAuth
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {object} dto.APIResponse{data=dto.TopReferrersResponse}
+// @Router /admin/stats/referrers [get]
+func (h *StatsHandler) GetTopReferrers(c *gin.Context) {
+	var query dto.StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.statsService.GetTopReferrers(query)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetTopTags godoc
+// @Summary Get tags ranked by views (Admin)
+// @Tags stats
+// @Security BearerAuth
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {object} dto.APIResponse{data=dto.TopTagsResponse}
+// @Router /admin/stats/tags [get]
+func (h *StatsHandler) GetTopTags(c *gin.Context) {
+	var query dto.StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.statsService.GetTopTags(query)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetAIUsage godoc
+// @Summary Get AI usage by operation (Admin)
+// @Tags stats
+// @Security BearerAuth
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Success 200 {object} dto.APIResponse{data=dto.AIUsageResponse}
+// @Router /admin/stats/ai [get]
+func (h *StatsHandler) GetAIUsage(c *gin.Context) {
+	var query dto.StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.statsService.GetAIUsage(query)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetPostSources godoc
+// @Summary Get top traffic sources of a post (Admin)
+// @Description Referrer hosts and UTM tags that brought visits to the post, aggregated per day
+// @Tags stats
+// @Security BearerAuth
+// @Param id path string true "Post ID"
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {object} dto.APIResponse{data=dto.PostSourcesResponse}
+// @Router /admin/stats/posts/{id}/sources [get]
+func (h *StatsHandler) GetPostSources(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid post ID"))
+		return
+	}
+
+	var query dto.StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.statsService.GetPostSources(id, query)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// GetSearchTerms godoc
+// @Summary Get top internal search queries (Admin)
+// @Tags stats
+// @Security BearerAuth
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {object} dto.APIResponse{data=dto.SearchTermsResponse}
+// @Router /admin/stats/searches [get]
+func (h *StatsHandler) GetSearchTerms(c *gin.Context) {
+	h.searchTerms(c, false)
+}
+
+// GetZeroResultSearches godoc
+// @Summary Get internal searches that returned no results (Admin)
+// @Tags stats
+// @Security BearerAuth
+// @Param range query string false "Time range, e.g. 24h, 7d, 30d or all" default(30d)
+// @Param limit query int false "Number of results" default(10)
+// @Success 200 {object} dto.APIResponse{data=dto.SearchTermsResponse}
+// @Router /admin/stats/searches/zero-results [get]
+func (h *StatsHandler) GetZeroResultSearches(c *gin.Context) {
+	h.searchTerms(c, true)
+}
+
+func (h *StatsHandler) searchTerms(c *gin.Context, zeroResultsOnly bool) {
+	var query dto.StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.statsService.GetSearchTerms(query, zeroResultsOnly)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+func (h *StatsHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidStatsRange) {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to compute statistics"))
+}
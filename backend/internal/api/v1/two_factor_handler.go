@@ -0,0 +1,177 @@
+package v1
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// GetStatus godoc
+// @Summary Get two-factor authentication status of the current user
+// @Tags auth
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.TwoFactorStatusResponse}
+// @Router /auth/2fa [get]
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	response, err := h.twoFactorService.GetStatus(token.AdminID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to get two-factor status"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Setup godoc
+// @Summary Start two-factor enrollment
+// @Description Generates a TOTP secret and its otpauth:// URI for a QR code. Two-factor authentication is off until confirmed at /auth/2fa/enable.
+// @Tags auth
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.TwoFactorSetupResponse}
+// @Failure 409 {object} dto.APIResponse
+// @Router /auth/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	response, err := h.twoFactorService.Setup(token.AdminID)
+	if err != nil {
+		respondTwoFactorError(c, err, "Failed to set up two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Enable godoc
+// @Summary Enable two-factor authentication
+// @Description Confirms the secret from /auth/2fa/setup with a code and returns the recovery codes, shown only this once
+// @Tags auth
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeRequest true "Authenticator code"
+// @Success 200 {object} dto.APIResponse{data=dto.RecoveryCodesResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Failure 429 {object} dto.APIResponse "Too many failed attempts, see Retry-After"
+// @Router /auth/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.twoFactorService.Enable(token.AdminID, req.Code, visitorFromRequest(c))
+	if err != nil {
+		respondTwoFactorError(c, err, "Failed to enable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Disable godoc
+// @Summary Disable two-factor authentication
+// @Description Requires the password and an authenticator or recovery code; deletes the secret and recovery codes
+// @Tags auth
+// @Security BearerAuth
+// @Param request body dto.DisableTwoFactorRequest true "Password and code"
+// @Success 200 {object} dto.APIResponse
+// @Failure 400 {object} dto.APIResponse
+// @Failure 429 {object} dto.APIResponse "Too many failed attempts, see Retry-After"
+// @Router /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	var req dto.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	if err := h.twoFactorService.Disable(token.AdminID, req, visitorFromRequest(c)); err != nil {
+		respondTwoFactorError(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replaces all recovery codes; the old ones stop working
+// @Tags auth
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeRequest true "Authenticator code"
+// @Success 200 {object} dto.APIResponse{data=dto.RecoveryCodesResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Failure 429 {object} dto.APIResponse "Too many failed attempts, see Retry-After"
+// @Router /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	response, err := h.twoFactorService.RegenerateRecoveryCodes(token.AdminID, req.Code, visitorFromRequest(c))
+	if err != nil {
+		respondTwoFactorError(c, err, "Failed to regenerate recovery codes")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// respondTwoFactorError maps the service errors a user can fix to 4xx
+func respondTwoFactorError(c *gin.Context, err error, message string) {
+	if respondThrottled(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrTwoFactorEnabled):
+		c.JSON(http.StatusConflict, dto.Error(409, err.Error()))
+	case errors.Is(err, service.ErrTwoFactorNotSetUp),
+		errors.Is(err, service.ErrTwoFactorNotEnabled),
+		errors.Is(err, service.ErrInvalidTwoFactorCode),
+		errors.Is(err, service.ErrInvalidCredentials):
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Error(500, message))
+	}
+}
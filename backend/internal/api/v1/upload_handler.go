@@ -3,22 +3,33 @@ package v1
 import (
 	"backend/internal/model/dto"
 	"backend/internal/service"
+	"backend/pkg/imaging"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UploadHandler struct {
-	ossService service.OSSService
+	uploadService service.UploadService
 }
 
-func NewUploadHandler(ossService service.OSSService) *UploadHandler {
-	return &UploadHandler{ossService: ossService}
+func NewUploadHandler(uploadService service.UploadService) *UploadHandler {
+	return &UploadHandler{uploadService: uploadService}
 }
 
+// multipartOverhead is allowed on top of the maximum file size for the
+// boundaries and headers of the multipart body
+const multipartOverhead = 1 << 20
+
 // UploadFile godoc
-// @Summary Upload a file to OSS
-// @Description Upload a binary file and return the public URL
+// @Summary Upload a file
+// @Description Upload a file to the configured storage (local disk, S3 compatible or OSS) and return the public URL. The type is detected from the content and must be allowed by UPLOAD_ALLOWED_TYPES and agree with the file extension and Content-Type. SVG images are stored without scripts. JPEG, PNG and WebP images are stored without metadata and with scaled and WebP variants for srcset, their dimensions and placeholders are returned.
 // @Tags upload
 // @Security BearerAuth
 // @Accept multipart/form-data
@@ -26,11 +37,21 @@ func NewUploadHandler(ossService service.OSSService) *UploadHandler {
 // @Param file formData file true "File to upload"
 // @Success 200 {object} dto.APIResponse{data=UploadResponse}
 // @Failure 400 {object} dto.APIResponse
+// @Failure 413 {object} dto.APIResponse
+// @Failure 415 {object} dto.APIResponse
 // @Failure 500 {object} dto.APIResponse
 // @Router /upload [post]
 func (h *UploadHandler) UploadFile(c *gin.Context) {
+	maxSize := h.uploadService.MaxSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.tooLarge(c, maxSize)
+			return
+		}
 		c.JSON(http.StatusBadRequest, dto.Error(400, "No file provided"))
 		return
 	}
@@ -41,21 +62,97 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 		contentType = "application/octet-stream"
 	}
 
-	url, err := h.ossService.UploadFile(file, header.Filename, contentType)
+	uploaded, err := h.uploadService.Upload(c.Request.Context(), file, header.Size, header.Filename, contentType)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to upload file"))
+		switch {
+		case errors.Is(err, service.ErrUploadTooLarge):
+			h.tooLarge(c, maxSize)
+		case errors.Is(err, service.ErrImageTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, dto.Error(413, fmt.Sprintf("Image exceeds %d megapixels", imaging.MaxPixels/1_000_000)))
+		case errors.Is(err, service.ErrUnsupportedMediaType):
+			c.JSON(http.StatusUnsupportedMediaType, dto.ErrorWithDetails(415, "File type is not allowed",
+				"Allowed types: "+strings.Join(h.uploadService.AllowedTypes(), ", ")))
+		case errors.Is(err, service.ErrUploadContentMismatch):
+			c.JSON(http.StatusUnsupportedMediaType, dto.ErrorWithDetails(415, "File content does not match its type",
+				"The file extension and Content-Type must match the detected file type"))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to upload file"))
+		}
 		return
 	}
 
+	variants := make([]UploadVariant, len(uploaded.Variants))
+	for i, variant := range uploaded.Variants {
+		variants[i] = UploadVariant{
+			URL:         variant.URL,
+			ContentType: variant.ContentType,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			Size:        variant.Size,
+		}
+	}
+
 	c.JSON(http.StatusOK, dto.Success(UploadResponse{
-		URL:      url,
-		Filename: header.Filename,
-		Size:     header.Size,
+		URL:         uploaded.URL,
+		Filename:    header.Filename,
+		Size:        uploaded.Size,
+		ContentType: uploaded.ContentType,
+		Width:       uploaded.Width,
+		Height:      uploaded.Height,
+		Blurhash:    uploaded.Blurhash,
+		Placeholder: uploaded.Placeholder,
+		Variants:    variants,
 	}))
 }
 
+func (h *UploadHandler) tooLarge(c *gin.Context, maxSize int64) {
+	c.JSON(http.StatusRequestEntityTooLarge, dto.Error(413, fmt.Sprintf("File exceeds the maximum upload size of %d MB", maxSize>>20)))
+}
+
+// UploadResponse - 上传结果, 图片的尺寸、占位图和各版本仅对 JPEG、PNG、WebP 返回
 type UploadResponse struct {
-	URL      string `json:"url"`
-	Filename string `json:"filename"`
-	Size     int64  `json:"size"`
+	URL         string          `json:"url"`
+	Filename    string          `json:"filename"`
+	Size        int64           `json:"size"`
+	ContentType string          `json:"contentType"`
+	Width       int             `json:"width,omitempty"`
+	Height      int             `json:"height,omitempty"`
+	Blurhash    string          `json:"blurhash,omitempty"`
+	Placeholder string          `json:"placeholder,omitempty"` // 低清晰度占位图, data URI
+	Variants    []UploadVariant `json:"variants,omitempty"`    // 包括原图在内的所有版本, 用于 srcset
+}
+
+// UploadVariant - 图片的一个版本
+type UploadVariant struct {
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Size        int64  `json:"size"`
+}
+
+// LocalFileHandler serves the files of the local storage driver
+type LocalFileHandler struct {
+	dir string
+}
+
+func NewLocalFileHandler(dir string) *LocalFileHandler {
+	return &LocalFileHandler{dir: dir}
+}
+
+// Serve answers /uploads/*filepath. Files are sandboxed, so an uploaded
+// HTML or SVG file cannot run scripts on the site's origin.
+func (h *LocalFileHandler) Serve(c *gin.Context) {
+	name := path.Clean("/" + c.Param("filepath"))
+	filename := filepath.Join(h.dir, filepath.FromSlash(name))
+	info, err := os.Stat(filename)
+	if err != nil || info.IsDir() || strings.HasPrefix(path.Base(name), ".") {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.File(filename)
 }
@@ -0,0 +1,246 @@
+package v1
+
+import (
+	"backend/internal/api/middleware"
+	"backend/internal/model/dto"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UserHandler struct {
+	userService service.UserService
+}
+
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// ListUsers godoc
+// @Summary List admin users (Owner)
+// @Tags users
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=[]dto.UserResponse}
+// @Failure 403 {object} dto.APIResponse
+// @Router /admin/users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	users, err := h.userService.ListUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.Error(500, "Failed to fetch users"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(users))
+}
+
+// GetUser godoc
+// @Summary Get an admin user (Owner)
+// @Tags users
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.APIResponse{data=dto.UserResponse}
+// @Failure 404 {object} dto.APIResponse
+// @Router /admin/users/{id} [get]
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid user ID"))
+		return
+	}
+
+	user, err := h.userService.GetUser(id)
+	if err != nil {
+		respondUserError(c, err, "Failed to fetch user")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(user))
+}
+
+// CreateUser godoc
+// @Summary Create an admin user (Owner)
+// @Description Roles: owner manages everything including users; editor manages all content; author writes and edits own posts; moderator handles comments
+// @Tags users
+// @Security BearerAuth
+// @Param user body dto.CreateUserRequest true "User data"
+// @Success 201 {object} dto.APIResponse{data=dto.UserResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Failure 409 {object} dto.APIResponse "Username or email already in use"
+// @Router /admin/users [post]
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	user, err := h.userService.CreateUser(req)
+	if err != nil {
+		respondUserError(c, err, "Failed to create user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.Created(user))
+}
+
+// UpdateUser godoc
+// @Summary Update an admin user (Owner)
+// @Description Changes email, password or role; a new password ends the user's sessions
+// @Tags users
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param user body dto.UpdateUserRequest true "Fields to change"
+// @Success 200 {object} dto.APIResponse{data=dto.UserResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Failure 409 {object} dto.APIResponse
+// @Router /admin/users/{id} [put]
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid user ID"))
+		return
+	}
+
+	var req dto.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	user, err := h.userService.UpdateUser(id, req)
+	if err != nil {
+		respondUserError(c, err, "Failed to update user")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(user))
+}
+
+// DeleteUser godoc
+// @Summary Delete an admin user (Owner)
+// @Description Deactivates the account and ends its sessions; its posts are kept
+// @Tags users
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.APIResponse
+// @Failure 404 {object} dto.APIResponse
+// @Failure 409 {object} dto.APIResponse
+// @Router /admin/users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid user ID"))
+		return
+	}
+
+	if err := h.userService.DeleteUser(id, middleware.ActorFromContext(c)); err != nil {
+		respondUserError(c, err, "Failed to delete user")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// ResetTwoFactor godoc
+// @Summary Turn off two-factor authentication of an admin user (Owner)
+// @Description For a user who lost both the authenticator and the recovery codes
+// @Tags users
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.APIResponse
+// @Failure 404 {object} dto.APIResponse
+// @Router /admin/users/{id}/reset-2fa [post]
+func (h *UserHandler) ResetTwoFactor(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, "Invalid user ID"))
+		return
+	}
+
+	if err := h.userService.ResetTwoFactor(id); err != nil {
+		respondUserError(c, err, "Failed to reset two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// UpdateProfile godoc
+// @Summary Update own profile
+// @Tags auth
+// @Security BearerAuth
+// @Param profile body dto.UpdateProfileRequest true "Fields to change"
+// @Success 200 {object} dto.APIResponse{data=dto.UserResponse}
+// @Failure 400 {object} dto.APIResponse
+// @Failure 409 {object} dto.APIResponse "Email already in use"
+// @Router /auth/me [put]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(middleware.ActorFromContext(c).ID, req)
+	if err != nil {
+		respondUserError(c, err, "Failed to update profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(user))
+}
+
+// ChangePassword godoc
+// @Summary Change own password
+// @Description Requires the current password; every other session is ended
+// @Tags auth
+// @Security BearerAuth
+// @Param password body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} dto.APIResponse
+// @Failure 400 {object} dto.APIResponse "Wrong current password or weak new password"
+// @Router /auth/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	token, ok := accessTokenFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error(401, "Not authenticated"))
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+		return
+	}
+
+	if err := h.userService.ChangePassword(token, req); err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			c.JSON(http.StatusBadRequest, dto.Error(400, "Current password is incorrect"))
+			return
+		}
+		respondUserError(c, err, "Failed to change password")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(nil))
+}
+
+// respondUserError maps the service errors a user can fix to 4xx
+func respondUserError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, dto.Error(404, err.Error()))
+	case errors.Is(err, service.ErrUsernameTaken),
+		errors.Is(err, service.ErrEmailTaken),
+		errors.Is(err, service.ErrLastOwner),
+		errors.Is(err, service.ErrCannotDeleteMe):
+		c.JSON(http.StatusConflict, dto.Error(409, err.Error()))
+	case errors.Is(err, service.ErrInvalidEmail),
+		errors.Is(err, service.ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, dto.Error(400, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.Error(500, message))
+	}
+}
@@ -0,0 +1,15 @@
+package v1
+
+import (
+	"backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// visitorFromRequest extracts the anonymous visitor identity of a request
+func visitorFromRequest(c *gin.Context) service.Visitor {
+	return service.Visitor{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
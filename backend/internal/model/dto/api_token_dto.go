@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// ========== Request DTOs ==========
+
+// CreateAPITokenRequest - scopes 为权限名，如 posts:write、files:upload；
+// expiresInDays 为空时永不过期
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,required"`
+	ExpiresInDays *int     `json:"expiresInDays" binding:"omitempty,min=1,max=3650"`
+}
+
+// ========== Response DTOs ==========
+
+// APITokenResponse - 令牌本身只在创建时返回一次，之后只显示 prefix
+type APITokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIp,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreatedAPITokenResponse - token 只返回这一次，请立即保存
+type CreatedAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
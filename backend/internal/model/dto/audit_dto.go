@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ========== Request DTOs ==========
+
+// AuditLogQuery - since、until 为日期 (含当天)，action 如 post.update
+type AuditLogQuery struct {
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	PageSize   int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	ActorID    string `form:"actor_id" binding:"omitempty,uuid"`
+	Action     string `form:"action" binding:"max=50"`
+	TargetType string `form:"target_type" binding:"omitempty,oneof=post tag comment user session api_token lockout seo upload"`
+	TargetID   string `form:"target_id" binding:"max=100"`
+	Since      string `form:"since" binding:"omitempty,datetime=2006-01-02"`
+	Until      string `form:"until" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// ========== Response DTOs ==========
+
+// AuditLogResponse - changes 为字段到 {from, to} 的映射，新建时没有 from，删除时没有 to
+type AuditLogResponse struct {
+	ID            int64           `json:"id"`
+	ActorID       string          `json:"actorId"`
+	ActorUsername string          `json:"actorUsername"`
+	APITokenID    string          `json:"apiTokenId,omitempty"`
+	Action        string          `json:"action"`
+	TargetType    string          `json:"targetType,omitempty"`
+	TargetID      string          `json:"targetId,omitempty"`
+	Method        string          `json:"method"`
+	Path          string          `json:"path"`
+	Status        int             `json:"status"`
+	Changes       json.RawMessage `json:"changes" swaggertype:"object"`
+	IP            string          `json:"ip"`
+	UserAgent     string          `json:"userAgent"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+type AuditLogListResponse struct {
+	Items      []AuditLogResponse `json:"items"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"pageSize"`
+	TotalPages int                `json:"totalPages"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // ========== Request DTOs ==========
 
 type LoginRequest struct {
@@ -7,16 +9,147 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// TwoFactorLoginRequest - 登录第二步，code 为验证器中的 6 位数字或恢复码
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// UnlockLoginRequest - scope 为 username 或 ip
+type UnlockLoginRequest struct {
+	Scope string `json:"scope" binding:"required,oneof=username ip"`
+	Value string `json:"value" binding:"required"`
+}
+
+// OIDCCallbackRequest - code 和 state 为身份提供方回调时 URL 中的参数，
+// stateToken 为开始登录时返回的值
+type OIDCCallbackRequest struct {
+	Code       string `json:"code" binding:"required"`
+	State      string `json:"state" binding:"required"`
+	StateToken string `json:"stateToken" binding:"required"`
+}
+
+type LoginHistoryQuery struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
 // ========== Response DTOs ==========
 
+// LoginResponse - 开启两步验证时只返回 challengeToken，
+// 需要提交验证码到 /auth/login/2fa 换取令牌
 type LoginResponse struct {
-	Token     string        `json:"token"`
-	ExpiresAt int64         `json:"expiresAt"`
-	User      AdminResponse `json:"user"`
+	Token              string         `json:"token,omitempty"`
+	ExpiresAt          int64          `json:"expiresAt,omitempty"`
+	RefreshToken       string         `json:"refreshToken,omitempty"`
+	RefreshExpiresAt   int64          `json:"refreshExpiresAt,omitempty"`
+	User               *AdminResponse `json:"user,omitempty"`
+	TwoFactorRequired  bool           `json:"twoFactorRequired,omitempty"`
+	ChallengeToken     string         `json:"challengeToken,omitempty"`
+	ChallengeExpiresAt int64          `json:"challengeExpiresAt,omitempty"`
+}
+
+type LogoutAllResponse struct {
+	Revoked int64 `json:"revoked"` // Sessions that were still active
 }
 
 type AdminResponse struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
+	ID               string `json:"id"`
+	Username         string `json:"username"`
+	Email            string `json:"email,omitempty"`
+	Role             string `json:"role"` // owner, editor, author or moderator
+	TwoFactorEnabled bool   `json:"twoFactorEnabled"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	RecoveryCodesRemaining int64 `json:"recoveryCodesRemaining"`
+}
+
+// TwoFactorSetupResponse - 待确认的密钥，otpauthUri 用于生成二维码
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+}
+
+// RecoveryCodesResponse - 恢复码只在生成时返回一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// OIDCProviderResponse - 可用的单点登录身份提供方
+type OIDCProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// OIDCStartResponse - 跳转到 authorizationUrl 登录，stateToken 需保存到回调时提交
+type OIDCStartResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	StateToken       string `json:"stateToken"`
+	ExpiresAt        int64  `json:"expiresAt"` // Unix seconds
+}
+
+// OIDCIdentityResponse - 已关联到当前管理员的单点登录身份
+type OIDCIdentityResponse struct {
+	Provider string `json:"provider"`
 	Email    string `json:"email,omitempty"`
 }
+
+// CurrentUserResponse - 当前管理员信息与最近登录记录
+type CurrentUserResponse struct {
+	AdminResponse
+	RecentLogins []LoginEventResponse `json:"recentLogins"`
+}
+
+// LoginEventResponse - type 为 login、lockout 或 unlock
+type LoginEventResponse struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type LoginHistoryResponse struct {
+	Items      []LoginEventResponse `json:"items"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	PageSize   int                  `json:"pageSize"`
+	TotalPages int                  `json:"totalPages"`
+}
+
+// SessionResponse - 登录会话，即一个刷新令牌链
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`  // Login time
+	LastUsedAt time.Time `json:"lastUsedAt"` // Last refresh
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"` // Session of the request's access token
+}
+
+// LoginLockoutResponse - 当前被锁定的用户名或 IP
+type LoginLockoutResponse struct {
+	Scope         string    `json:"scope"`
+	Value         string    `json:"value"`
+	Failures      int       `json:"failures"`
+	LastFailureAt time.Time `json:"lastFailureAt"`
+	LockedUntil   time.Time `json:"lockedUntil"`
+}
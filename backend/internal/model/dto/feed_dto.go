@@ -0,0 +1,7 @@
+package dto
+
+// ========== Request DTOs ==========
+
+type FeedQuery struct {
+	Content string `form:"content" binding:"omitempty,oneof=full excerpt"`
+}
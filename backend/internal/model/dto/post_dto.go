@@ -1,6 +1,9 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ========== Request DTOs ==========
 
@@ -10,6 +13,9 @@ type CreatePostRequest struct {
 	Content  string   `json:"content" binding:"required"`
 	Tags     []string `json:"tags" binding:"required,min=1"`
 	ReadTime string   `json:"readTime,omitempty"`
+	Slug     string   `json:"slug,omitempty" binding:"max=200"` // Derived from the title when empty
+
+	PostSEOFields
 }
 
 type UpdatePostRequest struct {
@@ -19,6 +25,22 @@ type UpdatePostRequest struct {
 	Tags        []string `json:"tags,omitempty"`
 	ReadTime    *string  `json:"readTime,omitempty"`
 	IsPublished *bool    `json:"is_published,omitempty"`
+	Slug        *string  `json:"slug,omitempty" binding:"omitempty,max=200"`
+
+	MetaTitle       *string `json:"metaTitle,omitempty" binding:"omitempty,max=500"`
+	MetaDescription *string `json:"metaDescription,omitempty" binding:"omitempty,max=1000"`
+	CanonicalURL    *string `json:"canonicalUrl,omitempty" binding:"omitempty,url"`
+	OGImage         *string `json:"ogImage,omitempty" binding:"omitempty,url"`
+	NoIndex         *bool   `json:"noIndex,omitempty"`
+}
+
+// PostSEOFields - 文章 SEO 设置，留空时使用标题、摘要等默认值
+type PostSEOFields struct {
+	MetaTitle       string `json:"metaTitle,omitempty" binding:"max=500"`
+	MetaDescription string `json:"metaDescription,omitempty" binding:"max=1000"`
+	CanonicalURL    string `json:"canonicalUrl,omitempty" binding:"omitempty,url"`
+	OGImage         string `json:"ogImage,omitempty" binding:"omitempty,url"`
+	NoIndex         bool   `json:"noIndex,omitempty"`
 }
 
 type PostListQuery struct {
@@ -34,6 +56,7 @@ type PostListQuery struct {
 // PostListItem - 列表项，不包含 content
 type PostListItem struct {
 	ID          string    `json:"id"`
+	Slug        string    `json:"slug"`
 	Title       string    `json:"title"`
 	Date        string    `json:"date"`
 	Tags        []string  `json:"tags"`
@@ -47,17 +70,32 @@ type PostListItem struct {
 
 // PostResponse - 完整文章详情，包含 content
 type PostResponse struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Date        string    `json:"date"`
-	Tags        []string  `json:"tags"`
-	Excerpt     string    `json:"excerpt"`
-	Content     string    `json:"content"`
-	ReadTime    string    `json:"readTime"`
-	ViewCount   int       `json:"viewCount"`
-	IsPublished bool      `json:"isPublished"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          string           `json:"id"`
+	Slug        string           `json:"slug"`
+	Title       string           `json:"title"`
+	Date        string           `json:"date"`
+	Tags        []string         `json:"tags"`
+	Excerpt     string           `json:"excerpt"`
+	Content     string           `json:"content"`
+	ReadTime    string           `json:"readTime"`
+	ViewCount   int              `json:"viewCount"`
+	Reactions   map[string]int64 `json:"reactions"`
+	IsPublished bool             `json:"isPublished"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+
+	PostSEOFields
+	SEO PostSEO `json:"seo"`
+}
+
+// PostSEO - 计算后的页面元信息，前端可直接写入 <head>
+type PostSEO struct {
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	CanonicalURL string          `json:"canonicalUrl"`
+	OGImage      string          `json:"ogImage,omitempty"`
+	Robots       string          `json:"robots"`
+	JSONLD       json.RawMessage `json:"jsonLd" swaggertype:"object"`
 }
 
 type PostListResponse struct {
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// ========== Request DTOs ==========
+
+type ReactRequest struct {
+	Reaction string `json:"reaction" binding:"required,oneof=like insightful helpful not_helpful"`
+}
+
+type EngagementReportQuery struct {
+	Days  int `form:"days,default=30" binding:"min=1,max=365"`
+	Limit int `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+// ========== Response DTOs ==========
+
+// ReactionSummaryResponse - 文章的反馈统计，以及当前访客已做出的反馈
+type ReactionSummaryResponse struct {
+	PostID  string           `json:"postId"`
+	Counts  map[string]int64 `json:"counts"`
+	Reacted []string         `json:"reacted"`
+}
+
+type EngagementItem struct {
+	PostID    string `json:"postId"`
+	Title     string `json:"title"`
+	Views     int64  `json:"views"`
+	Reactions int64  `json:"reactions"`
+	Comments  int64  `json:"comments"`
+	Score     int64  `json:"score"`
+}
+
+type EngagementReportResponse struct {
+	Since time.Time        `json:"since"`
+	Days  int              `json:"days"`
+	Posts []EngagementItem `json:"posts"`
+}
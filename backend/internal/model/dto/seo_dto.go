@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// ========== Request DTOs ==========
+
+type SEOHistoryQuery struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Engine   string `form:"engine" binding:"omitempty,oneof=baidu indexnow google"`
+}
+
+// ========== Response DTOs ==========
+
+type SEOPushHistoryItem struct {
+	ID         int64     `json:"id"`
+	Engine     string    `json:"engine"`
+	Action     string    `json:"action"`
+	URLs       []string  `json:"urls"`
+	StatusCode int       `json:"statusCode"`
+	Success    bool      `json:"success"`
+	Response   string    `json:"response"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type SEOHistoryResponse struct {
+	Items      []SEOPushHistoryItem `json:"items"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	PageSize   int                  `json:"pageSize"`
+	TotalPages int                  `json:"totalPages"`
+}
+
+// SEOEngineStatus - 搜索引擎今日配额与待推送数量
+type SEOEngineStatus struct {
+	Engine     string `json:"engine"`
+	DailyQuota int    `json:"dailyQuota"`
+	UsedToday  int    `json:"usedToday"`
+	Pending    int64  `json:"pending"`
+}
+
+type SEOStatusResponse struct {
+	Engines []SEOEngineStatus `json:"engines"`
+}
+
+type SEOEnqueueResponse struct {
+	Queued int `json:"queued"`
+}
+
+type IndexNowKeyResponse struct {
+	Key         string    `json:"key"`
+	KeyLocation string    `json:"keyLocation"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// IndexNowCheckResponse - 通过站点地址获取密钥文件的自检结果
+type IndexNowCheckResponse struct {
+	Key         string   `json:"key"`
+	KeyLocation string   `json:"keyLocation"`
+	StatusCode  int      `json:"statusCode"` // 0 when the request failed
+	ContentType string   `json:"contentType"`
+	KeyMatches  bool     `json:"keyMatches"`
+	OK          bool     `json:"ok"`
+	Problems    []string `json:"problems"`
+}
@@ -0,0 +1,127 @@
+package dto
+
+import "time"
+
+// ========== Request DTOs ==========
+
+// StatsQuery - range 支持 "24h"、"7d"、"30d" 等格式，或 "all"
+type StatsQuery struct {
+	Range string `form:"range,default=30d"`
+	Limit int    `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+type ViewSeriesQuery struct {
+	Range    string `form:"range,default=30d"`
+	Interval string `form:"interval,default=day" binding:"oneof=day week"`
+}
+
+// ========== Response DTOs ==========
+
+type StatsRange struct {
+	Range string     `json:"range"`
+	Since *time.Time `json:"since,omitempty"`
+}
+
+type PostStats struct {
+	Total          int64 `json:"total"`
+	Published      int64 `json:"published"`
+	Draft          int64 `json:"draft"`
+	CreatedInRange int64 `json:"createdInRange"`
+}
+
+type CommentStats struct {
+	Total         int64 `json:"total"`
+	Active        int64 `json:"active"`
+	Deleted       int64 `json:"deleted"`
+	AwaitingReply int64 `json:"awaitingReply"`
+}
+
+type StatsOverviewResponse struct {
+	StatsRange
+	Posts    PostStats    `json:"posts"`
+	Comments CommentStats `json:"comments"`
+	Views    int64        `json:"views"`
+}
+
+type ViewPoint struct {
+	Date  string `json:"date"`
+	Views int64  `json:"views"`
+}
+
+type ViewSeriesResponse struct {
+	StatsRange
+	Interval string      `json:"interval"`
+	Points   []ViewPoint `json:"points"`
+}
+
+type TopPostItem struct {
+	PostID string `json:"postId"`
+	Title  string `json:"title"`
+	Views  int64  `json:"views"`
+}
+
+type TopPostsResponse struct {
+	StatsRange
+	Posts []TopPostItem `json:"posts"`
+}
+
+type ReferrerItem struct {
+	Host  string `json:"host"`
+	Views int64  `json:"views"`
+}
+
+type TopReferrersResponse struct {
+	StatsRange
+	Referrers []ReferrerItem `json:"referrers"`
+}
+
+type TagViewsItem struct {
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Views int64  `json:"views"`
+}
+
+type TopTagsResponse struct {
+	StatsRange
+	Tags []TagViewsItem `json:"tags"`
+}
+
+type AIUsageItem struct {
+	Operation     string  `json:"operation"`
+	Provider      string  `json:"provider"`
+	Calls         int64   `json:"calls"`
+	Failures      int64   `json:"failures"`
+	AvgDurationMs float64 `json:"avgDurationMs"`
+}
+
+type AIUsageResponse struct {
+	StatsRange
+	TotalCalls    int64         `json:"totalCalls"`
+	TotalFailures int64         `json:"totalFailures"`
+	Operations    []AIUsageItem `json:"operations"`
+}
+
+type TrafficSourceItem struct {
+	ReferrerHost string `json:"referrerHost"`
+	UTMSource    string `json:"utmSource,omitempty"`
+	UTMMedium    string `json:"utmMedium,omitempty"`
+	UTMCampaign  string `json:"utmCampaign,omitempty"`
+	Visits       int64  `json:"visits"`
+}
+
+type PostSourcesResponse struct {
+	StatsRange
+	PostID  string              `json:"postId"`
+	Sources []TrafficSourceItem `json:"sources"`
+}
+
+type SearchTermItem struct {
+	Query       string `json:"query"`
+	Searches    int64  `json:"searches"`
+	ZeroResults int64  `json:"zeroResults"`
+}
+
+type SearchTermsResponse struct {
+	StatsRange
+	Searches []SearchTermItem `json:"searches"`
+}
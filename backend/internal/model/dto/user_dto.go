@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+// ========== Request DTOs ==========
+
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Email    string `json:"email" binding:"omitempty,email,max=255"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=owner editor author moderator"`
+}
+
+// UpdateUserRequest - 只更新提供的字段，email 为空字符串时清除
+type UpdateUserRequest struct {
+	Email    *string `json:"email" binding:"omitempty,max=255,eq=|email"`
+	Password *string `json:"password"`
+	Role     *string `json:"role" binding:"omitempty,oneof=owner editor author moderator"`
+}
+
+// UpdateProfileRequest - 修改自己的资料，email 为空字符串时清除
+type UpdateProfileRequest struct {
+	Email *string `json:"email" binding:"omitempty,max=255,eq=|email"`
+}
+
+// ChangePasswordRequest - 修改自己的密码，其他会话会被登出
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
+// ========== Response DTOs ==========
+
+type UserResponse struct {
+	ID               string     `json:"id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email,omitempty"`
+	Role             string     `json:"role"`
+	TwoFactorEnabled bool       `json:"twoFactorEnabled"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	LastLogin        *time.Time `json:"lastLogin,omitempty"`
+}
@@ -6,6 +6,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Admin roles, see service.Permission for what each may do
+const (
+	RoleOwner     = "owner"
+	RoleEditor    = "editor"
+	RoleAuthor    = "author"
+	RoleModerator = "moderator"
+)
+
 type Admin struct {
 	ID           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	Username     string     `gorm:"size:50;not null;unique" json:"username"`
@@ -14,6 +22,13 @@ type Admin struct {
 	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	LastLogin    *time.Time `json:"last_login,omitempty"`
 	IsActive     bool       `gorm:"default:true" json:"is_active"`
+	Role         string     `gorm:"size:20;not null;default:owner" json:"role"`
+	// TOTPSecret is set by 2FA setup and only checked at login once
+	// TOTPEnabled; TOTPLastStep is the last accepted time step, so a code
+	// cannot be used twice
+	TOTPSecret   string `gorm:"column:totp_secret;size:64;not null;default:''" json:"-"`
+	TOTPEnabled  bool   `gorm:"column:totp_enabled;not null;default:false" json:"totp_enabled"`
+	TOTPLastStep int64  `gorm:"column:totp_last_step;not null;default:0" json:"-"`
 }
 
 func (Admin) TableName() string {
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminIdentity links an account at an OpenID Connect provider, identified
+// by issuer and subject, to an admin
+type AdminIdentity struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdminID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"admin_id"`
+	Provider    string     `gorm:"size:32;not null" json:"provider"`
+	Issuer      string     `gorm:"size:255;not null;uniqueIndex:unique_identity_subject" json:"issuer"`
+	Subject     string     `gorm:"size:255;not null;uniqueIndex:unique_identity_subject" json:"subject"`
+	Email       string     `gorm:"size:255;not null;default:''" json:"email"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+func (AdminIdentity) TableName() string {
+	return "admin_identities"
+}
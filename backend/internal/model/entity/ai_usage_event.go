@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// AIUsageEvent records one call to the AI provider
+type AIUsageEvent struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Operation  string    `gorm:"size:30;not null" json:"operation"`
+	Provider   string    `gorm:"size:30;not null" json:"provider"`
+	Success    bool      `gorm:"not null" json:"success"`
+	DurationMs int       `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (AIUsageEvent) TableName() string {
+	return "ai_usage_events"
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIToken is a long-lived personal token for scripts and CI. It acts as
+// its admin, limited to Scopes. Only a SHA-256 hash of the token is stored;
+// Prefix is kept so the admin can tell their tokens apart.
+type APIToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	AdminID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"admin_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"type:text;not null" json:"scopes"` // Space separated permissions
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `gorm:"column:last_used_ip;size:45;not null;default:''" json:"last_used_ip"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
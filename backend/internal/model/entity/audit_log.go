@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog records a mutating admin request: who did what to which target,
+// the changed fields and the response status. ActorID is kept when the
+// admin is deleted, ActorUsername keeps the name it had.
+type AuditLog struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorID       uuid.UUID  `gorm:"type:uuid;not null" json:"actor_id"`
+	ActorUsername string     `gorm:"size:50;not null" json:"actor_username"`
+	APITokenID    *uuid.UUID `gorm:"column:api_token_id;type:uuid" json:"api_token_id,omitempty"` // Set when the request used an API token
+	Action        string     `gorm:"size:50;not null" json:"action"`                              // e.g. "post.update"
+	TargetType    string     `gorm:"size:20;not null;default:''" json:"target_type"`
+	TargetID      string     `gorm:"size:100;not null;default:''" json:"target_id"`
+	Method        string     `gorm:"size:10;not null" json:"method"`
+	Path          string     `gorm:"type:text;not null" json:"path"`
+	Status        int        `gorm:"not null" json:"status"`
+	Changes       string     `gorm:"type:text;not null;default:'{}'" json:"changes"` // JSON object, field to {from, to}
+	IP            string     `gorm:"column:ip;size:45;not null;default:''" json:"ip"`
+	UserAgent     string     `gorm:"type:text;not null;default:''" json:"user_agent"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
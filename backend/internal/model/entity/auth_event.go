@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Auth event types
+const (
+	AuthEventLogin   = "login"
+	AuthEventLockout = "lockout"
+	AuthEventUnlock  = "unlock"
+)
+
+// AuthEvent records a sign-in attempt of an admin account, successful or
+// not, and lockouts of a username or IP. AdminID is nil when the username
+// matched no account.
+type AuthEvent struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdminID   *uuid.UUID `gorm:"type:uuid;index" json:"admin_id,omitempty"`
+	Username  string     `gorm:"size:50;not null" json:"username"`
+	Type      string     `gorm:"size:20;not null" json:"type"`
+	Success   bool       `gorm:"not null" json:"success"`
+	Reason    string     `gorm:"size:50;not null;default:''" json:"reason"`
+	IP        string     `gorm:"column:ip;size:45;not null;default:''" json:"ip"`
+	UserAgent string     `gorm:"type:text;not null;default:''" json:"user_agent"`
+	SessionID *uuid.UUID `gorm:"type:uuid" json:"session_id,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (AuthEvent) TableName() string {
+	return "auth_events"
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is one link of a refresh token chain. Every refresh uses up
+// the presented token and issues the next one in the same family, which is
+// the login session. Presenting a used token again means it was stolen, so
+// the whole family is revoked. Only a SHA-256 hash of the token is stored.
+// IP and UserAgent are those of the client the token was issued to.
+type RefreshToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	AdminID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"admin_id"`
+	FamilyID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"family_id"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	IP        string     `gorm:"column:ip;size:45;not null;default:''" json:"ip"`
+	UserAgent string     `gorm:"type:text;not null;default:''" json:"user_agent"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RevokedToken blocks an access token, by its jti, until it expires
+type RevokedToken struct {
+	JTI       string    `gorm:"column:jti;size:64;primaryKey" json:"jti"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	RevokedAt time.Time `gorm:"autoCreateTime" json:"revoked_at"`
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
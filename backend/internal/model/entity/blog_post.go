@@ -9,6 +9,7 @@ import (
 type BlogPost struct {
 	ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	Title         string     `gorm:"size:500;not null" json:"title"`
+	Slug          string     `gorm:"size:200;not null;uniqueIndex" json:"slug"`
 	Excerpt       string     `gorm:"type:text;not null" json:"excerpt"`
 	Content       string     `gorm:"type:text;not null" json:"content"`
 	ReadTime      string     `gorm:"size:20;not null;default:'1 min'" json:"read_time"`
@@ -19,6 +20,13 @@ type BlogPost struct {
 	ViewCount     int        `gorm:"default:0" json:"view_count"`
 	AuthorID      *uuid.UUID `gorm:"type:uuid" json:"author_id,omitempty"`
 
+	// SEO overrides, empty to fall back to the post's own fields
+	MetaTitle       string `gorm:"size:500;not null;default:''" json:"meta_title"`
+	MetaDescription string `gorm:"type:text;not null;default:''" json:"meta_description"`
+	CanonicalURL    string `gorm:"column:canonical_url;type:text;not null;default:''" json:"canonical_url"`
+	OGImage         string `gorm:"column:og_image;type:text;not null;default:''" json:"og_image"`
+	NoIndex         bool   `gorm:"not null;default:false" json:"no_index"`
+
 	// Relations
 	Author *Admin `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
 	Tags   []Tag  `gorm:"many2many:post_tags;joinForeignKey:post_id;joinReferences:tag_id" json:"tags,omitempty"`
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// IndexNowKey is a key served at /{key}.txt to prove ownership of the site
+// to IndexNow. Only one key is active and used for pushes; retired keys are
+// still served for a while so engines can verify pushes made with them.
+type IndexNowKey struct {
+	Key       string     `gorm:"size:128;primaryKey" json:"key"`
+	IsActive  bool       `gorm:"not null;default:false" json:"is_active"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at"`
+}
+
+func (IndexNowKey) TableName() string {
+	return "indexnow_keys"
+}
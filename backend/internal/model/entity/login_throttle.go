@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Login throttle scopes
+const (
+	ThrottleScopeUsername = "username"
+	ThrottleScopeIP       = "ip"
+)
+
+// LoginThrottle counts recent failed logins of a username or an IP. Logins
+// are refused until LockedUntil, which grows with every failure.
+type LoginThrottle struct {
+	Scope         string     `gorm:"size:20;primaryKey" json:"scope"`
+	Value         string     `gorm:"size:100;primaryKey" json:"value"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time  `gorm:"not null" json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+}
+
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
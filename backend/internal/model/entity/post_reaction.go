@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Supported reader reactions
+const (
+	ReactionLike       = "like"
+	ReactionInsightful = "insightful"
+	ReactionHelpful    = "helpful"
+	ReactionNotHelpful = "not_helpful"
+)
+
+type PostReaction struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	PostID      uuid.UUID `gorm:"type:uuid;not null" json:"post_id"`
+	Reaction    string    `gorm:"size:20;not null" json:"reaction"`
+	VisitorHash string    `gorm:"size:64;not null" json:"-"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (PostReaction) TableName() string {
+	return "post_reactions"
+}
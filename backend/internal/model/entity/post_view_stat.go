@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostViewStat holds the deduplicated view count of a post for one hour
+type PostViewStat struct {
+	PostID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"post_id"`
+	BucketStart time.Time `gorm:"primaryKey" json:"bucket_start"`
+	Views       int       `gorm:"not null;default:0" json:"views"`
+}
+
+func (PostViewStat) TableName() string {
+	return "post_view_stats"
+}
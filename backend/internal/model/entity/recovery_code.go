@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a single-use 2FA backup code; only its SHA-256 hash is
+// stored
+type RecoveryCode struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AdminID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"admin_id"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "admin_recovery_codes"
+}
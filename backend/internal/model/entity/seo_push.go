@@ -0,0 +1,70 @@
+package entity
+
+import "time"
+
+// Search engines URLs are pushed to
+const (
+	SEOEngineBaidu    = "baidu"
+	SEOEngineIndexNow = "indexnow"
+	SEOEngineGoogle   = "google"
+)
+
+// Push actions: a URL was added or changed, or it was removed
+const (
+	SEOActionUpdate = "update"
+	SEOActionDelete = "delete"
+)
+
+// Push task statuses
+const (
+	SEOTaskPending = "pending"
+	SEOTaskSent    = "sent"
+	SEOTaskFailed  = "failed"
+)
+
+// SEOPushTask is a URL waiting to be pushed to a search engine. At most one
+// task per engine and URL is pending at a time.
+type SEOPushTask struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Engine        string    `gorm:"size:20;not null" json:"engine"`
+	URL           string    `gorm:"type:text;not null" json:"url"`
+	Action        string    `gorm:"size:10;not null" json:"action"`
+	Status        string    `gorm:"size:10;not null;default:'pending'" json:"status"`
+	Attempts      int       `gorm:"not null;default:0" json:"attempts"`
+	LastError     string    `gorm:"type:text;not null;default:''" json:"last_error"`
+	NextAttemptAt time.Time `gorm:"not null" json:"next_attempt_at"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (SEOPushTask) TableName() string {
+	return "seo_push_queue"
+}
+
+// SEOPushQuota counts the URLs pushed to an engine on a day
+type SEOPushQuota struct {
+	Engine string    `gorm:"size:20;primaryKey" json:"engine"`
+	Day    time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Used   int       `gorm:"not null;default:0" json:"used"`
+}
+
+func (SEOPushQuota) TableName() string {
+	return "seo_push_quotas"
+}
+
+// SEOPushHistory records a push request and the engine's response
+type SEOPushHistory struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Engine     string    `gorm:"size:20;not null" json:"engine"`
+	Action     string    `gorm:"size:10;not null" json:"action"`
+	URLs       string    `gorm:"column:urls;type:text;not null" json:"urls"` // Newline separated
+	URLCount   int       `gorm:"column:url_count;not null" json:"url_count"`
+	StatusCode int       `gorm:"not null;default:0" json:"status_code"` // 0 when no response was received
+	Success    bool      `gorm:"not null" json:"success"`
+	Response   string    `gorm:"type:text;not null;default:''" json:"response"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (SEOPushHistory) TableName() string {
+	return "seo_push_history"
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostTrafficSource counts daily visits to a post per referrer and UTM tags
+type PostTrafficSource struct {
+	Day          time.Time `gorm:"type:date;primaryKey" json:"day"`
+	PostID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"post_id"`
+	ReferrerHost string    `gorm:"size:255;primaryKey" json:"referrer_host"`
+	UTMSource    string    `gorm:"column:utm_source;size:100;primaryKey" json:"utm_source"`
+	UTMMedium    string    `gorm:"column:utm_medium;size:100;primaryKey" json:"utm_medium"`
+	UTMCampaign  string    `gorm:"column:utm_campaign;size:100;primaryKey" json:"utm_campaign"`
+	Visits       int       `gorm:"not null;default:0" json:"visits"`
+}
+
+func (PostTrafficSource) TableName() string {
+	return "post_traffic_sources"
+}
+
+// SearchQueryStat counts daily internal searches for a normalized query
+type SearchQueryStat struct {
+	Day         time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Query       string    `gorm:"size:100;primaryKey" json:"query"`
+	Searches    int       `gorm:"not null;default:0" json:"searches"`
+	ZeroResults int       `gorm:"not null;default:0" json:"zero_results"`
+}
+
+func (SearchQueryStat) TableName() string {
+	return "search_query_stats"
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AdminIdentityRepository interface {
+	Find(issuer, subject string) (*entity.AdminIdentity, error)
+	Create(identity *entity.AdminIdentity) error
+	MarkLogin(id int64, email string) error
+}
+
+type adminIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminIdentityRepository(db *gorm.DB) AdminIdentityRepository {
+	return &adminIdentityRepository{db: db}
+}
+
+func (r *adminIdentityRepository) Find(issuer, subject string) (*entity.AdminIdentity, error) {
+	var identity entity.AdminIdentity
+	if err := r.db.First(&identity, "issuer = ? AND subject = ?", issuer, subject).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *adminIdentityRepository) Create(identity *entity.AdminIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// MarkLogin records a login through the identity and the email the
+// provider reported
+func (r *adminIdentityRepository) MarkLogin(id int64, email string) error {
+	return r.db.Model(&entity.AdminIdentity{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_login_at": time.Now(), "email": email}).Error
+}
@@ -10,7 +10,18 @@ import (
 type AdminRepository interface {
 	FindByUsername(username string) (*entity.Admin, error)
 	FindByID(id uuid.UUID) (*entity.Admin, error)
+	FindAllByEmail(email string) ([]entity.Admin, error)
+	FindAll() ([]entity.Admin, error)
+	Create(admin *entity.Admin) error
+	Update(admin *entity.Admin) error
+	Deactivate(id uuid.UUID) error
+	UsernameExists(username string) (bool, error)
+	EmailExists(email string, excludeID uuid.UUID) (bool, error)
+	CountByRole(role string) (int64, error)
 	UpdateLastLogin(id uuid.UUID) error
+	SetTOTPSecret(id uuid.UUID, secret string) error
+	SetTOTPEnabled(id uuid.UUID, enabled bool) error
+	AdvanceTOTPStep(id uuid.UUID, step int64) (bool, error)
 }
 
 type adminRepository struct {
@@ -37,7 +48,83 @@ func (r *adminRepository) FindByID(id uuid.UUID) (*entity.Admin, error) {
 	return &admin, nil
 }
 
+// FindAllByEmail finds the active admins with the email, ignoring case.
+// Databases created before emails were unique regardless of case may have
+// several.
+func (r *adminRepository) FindAllByEmail(email string) ([]entity.Admin, error) {
+	var admins []entity.Admin
+	err := r.db.Where("LOWER(email) = LOWER(?) AND is_active = ?", email, true).Limit(2).Find(&admins).Error
+	return admins, err
+}
+
+// FindAll returns the active admins, oldest first
+func (r *adminRepository) FindAll() ([]entity.Admin, error) {
+	var admins []entity.Admin
+	err := r.db.Where("is_active = ?", true).Order("created_at ASC").Find(&admins).Error
+	return admins, err
+}
+
+func (r *adminRepository) Create(admin *entity.Admin) error {
+	return r.db.Create(admin).Error
+}
+
+func (r *adminRepository) Update(admin *entity.Admin) error {
+	return r.db.Model(admin).Select("email", "role", "password_hash").Updates(admin).Error
+}
+
+// Deactivate soft deletes an admin; the username stays taken
+func (r *adminRepository) Deactivate(id uuid.UUID) error {
+	return r.db.Model(&entity.Admin{}).Where("id = ?", id).Update("is_active", false).Error
+}
+
+// UsernameExists also counts deactivated admins, as usernames are unique
+func (r *adminRepository) UsernameExists(username string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Admin{}).Where("username = ?", username).Count(&count).Error
+	return count > 0, err
+}
+
+// EmailExists ignores case, like the unique index on the email
+func (r *adminRepository) EmailExists(email string, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Admin{}).Where("LOWER(email) = LOWER(?) AND id <> ?", email, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// CountByRole counts the active admins with the role
+func (r *adminRepository) CountByRole(role string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Admin{}).Where("role = ? AND is_active = ?", role, true).Count(&count).Error
+	return count, err
+}
+
 func (r *adminRepository) UpdateLastLogin(id uuid.UUID) error {
 	return r.db.Model(&entity.Admin{}).Where("id = ?", id).
 		UpdateColumn("last_login", gorm.Expr("CURRENT_TIMESTAMP")).Error
 }
+
+// SetTOTPSecret stores a new secret and leaves 2FA disabled until it is
+// confirmed
+func (r *adminRepository) SetTOTPSecret(id uuid.UUID, secret string) error {
+	return r.db.Model(&entity.Admin{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_enabled": false, "totp_last_step": 0}).Error
+}
+
+// SetTOTPEnabled turns 2FA on or off; turning it off also clears the secret
+func (r *adminRepository) SetTOTPEnabled(id uuid.UUID, enabled bool) error {
+	updates := map[string]interface{}{"totp_enabled": enabled}
+	if !enabled {
+		updates["totp_secret"] = ""
+		updates["totp_last_step"] = 0
+	}
+	return r.db.Model(&entity.Admin{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// AdvanceTOTPStep records an accepted time step. It reports false when the
+// step is not newer than the last one, meaning the code was already used.
+func (r *adminRepository) AdvanceTOTPStep(id uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&entity.Admin{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		UpdateColumn("totp_last_step", step)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type AIUsageRepository interface {
+	Create(event *entity.AIUsageEvent) error
+}
+
+type aiUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewAIUsageRepository(db *gorm.DB) AIUsageRepository {
+	return &aiUsageRepository{db: db}
+}
+
+func (r *aiUsageRepository) Create(event *entity.AIUsageEvent) error {
+	return r.db.Create(event).Error
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type APITokenRepository interface {
+	Create(token *entity.APIToken) error
+	FindByHash(tokenHash string) (*entity.APIToken, error)
+	FindByAdmin(adminID uuid.UUID) ([]entity.APIToken, error)
+	Revoke(adminID, id uuid.UUID) (bool, error)
+	MarkUsed(id uuid.UUID, ip string, now time.Time, interval time.Duration) error
+}
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(token *entity.APIToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *apiTokenRepository) FindByHash(tokenHash string) (*entity.APIToken, error) {
+	var token entity.APIToken
+	if err := r.db.First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByAdmin returns the admin's tokens that are not revoked, newest first
+func (r *apiTokenRepository) FindByAdmin(adminID uuid.UUID) ([]entity.APIToken, error) {
+	var tokens []entity.APIToken
+	err := r.db.Where("admin_id = ? AND revoked_at IS NULL", adminID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// Revoke revokes one token of the admin and reports whether it existed
+func (r *apiTokenRepository) Revoke(adminID, id uuid.UUID) (bool, error) {
+	result := r.db.Model(&entity.APIToken{}).
+		Where("id = ? AND admin_id = ? AND revoked_at IS NULL", id, adminID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkUsed records the use of a token, at most once per interval so busy
+// scripts don't write on every request
+func (r *apiTokenRepository) MarkUsed(id uuid.UUID, ip string, now time.Time, interval time.Duration) error {
+	return r.db.Model(&entity.APIToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-interval)).
+		Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error
+}
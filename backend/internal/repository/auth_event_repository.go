@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuthEventRepository interface {
+	Create(event *entity.AuthEvent) error
+	FindByAdmin(adminID uuid.UUID, page, pageSize int) ([]entity.AuthEvent, int64, error)
+	PurgeBefore(before time.Time) (int64, error)
+}
+
+type authEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthEventRepository(db *gorm.DB) AuthEventRepository {
+	return &authEventRepository{db: db}
+}
+
+func (r *authEventRepository) Create(event *entity.AuthEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *authEventRepository) FindByAdmin(adminID uuid.UUID, page, pageSize int) ([]entity.AuthEvent, int64, error) {
+	var events []entity.AuthEvent
+	var total int64
+
+	query := r.db.Model(&entity.AuthEvent{}).Where("admin_id = ?", adminID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// PurgeBefore deletes the events older than the retention period
+func (r *authEventRepository) PurgeBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&entity.AuthEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AuthTokenRepository interface {
+	CreateRefreshToken(token *entity.RefreshToken) error
+	FindRefreshToken(tokenHash string) (*entity.RefreshToken, error)
+	MarkRefreshTokenUsed(id uuid.UUID) (bool, error)
+	RevokeFamily(familyID uuid.UUID) error
+	RevokeAdminFamily(adminID, familyID uuid.UUID) (bool, error)
+	RevokeAllFamilies(adminID uuid.UUID) (int64, error)
+	RevokeOtherFamilies(adminID, keepFamilyID uuid.UUID) (int64, error)
+	IsFamilyRevoked(familyID uuid.UUID) (bool, error)
+	FindActiveSessions(adminID uuid.UUID, now time.Time) ([]ActiveSession, error)
+	RevokeAccessToken(jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+	PurgeExpired(before time.Time) (int64, error)
+}
+
+// ActiveSession is a refresh token family that can still be refreshed,
+// with its current token
+type ActiveSession struct {
+	entity.RefreshToken
+	StartedAt time.Time
+}
+
+type authTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthTokenRepository(db *gorm.DB) AuthTokenRepository {
+	return &authTokenRepository{db: db}
+}
+
+func (r *authTokenRepository) CreateRefreshToken(token *entity.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *authTokenRepository) FindRefreshToken(tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	if err := r.db.First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed uses up a token. It reports false when the token was
+// already used, also by a concurrent request.
+func (r *authTokenRepository) MarkRefreshTokenUsed(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&entity.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *authTokenRepository) RevokeFamily(familyID uuid.UUID) error {
+	return r.db.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAdminFamily revokes a session of the admin. It reports false when
+// the admin has no active session with the ID.
+func (r *authTokenRepository) RevokeAdminFamily(adminID, familyID uuid.UUID) (bool, error) {
+	result := r.db.Model(&entity.RefreshToken{}).
+		Where("admin_id = ? AND family_id = ? AND revoked_at IS NULL", adminID, familyID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// RevokeAllFamilies revokes every session of an admin and returns how many
+// were still active
+func (r *authTokenRepository) RevokeAllFamilies(adminID uuid.UUID) (int64, error) {
+	var active int64
+	err := r.db.Model(&entity.RefreshToken{}).
+		Where("admin_id = ? AND revoked_at IS NULL AND used_at IS NULL AND expires_at > ?", adminID, time.Now()).
+		Count(&active).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.Model(&entity.RefreshToken{}).
+		Where("admin_id = ? AND revoked_at IS NULL", adminID).
+		Update("revoked_at", time.Now()).Error
+	return active, err
+}
+
+// RevokeOtherFamilies revokes every session of an admin but one
+func (r *authTokenRepository) RevokeOtherFamilies(adminID, keepFamilyID uuid.UUID) (int64, error) {
+	result := r.db.Model(&entity.RefreshToken{}).
+		Where("admin_id = ? AND family_id <> ? AND revoked_at IS NULL", adminID, keepFamilyID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *authTokenRepository) IsFamilyRevoked(familyID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NOT NULL", familyID).
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindActiveSessions returns the sessions of an admin whose current token
+// is neither used, revoked nor expired, most recently refreshed first
+func (r *authTokenRepository) FindActiveSessions(adminID uuid.UUID, now time.Time) ([]ActiveSession, error) {
+	var sessions []ActiveSession
+	err := r.db.Model(&entity.RefreshToken{}).
+		Select("refresh_tokens.*, (SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = refresh_tokens.family_id) AS started_at").
+		Where("admin_id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", adminID, now).
+		Order("created_at DESC").
+		Scan(&sessions).Error
+	return sessions, err
+}
+
+func (r *authTokenRepository) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+func (r *authTokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// PurgeExpired deletes refresh tokens and revocations that expired before
+// the time, as expired tokens are rejected anyway
+func (r *authTokenRepository) PurgeExpired(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("expires_at < ?", before).Delete(&entity.RefreshToken{})
+		if result.Error != nil {
+			return result.Error
+		}
+		purged += result.RowsAffected
+
+		result = tx.Where("expires_at < ?", before).Delete(&entity.RevokedToken{})
+		purged += result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
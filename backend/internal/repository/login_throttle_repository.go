@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepository interface {
+	Find(scope, value string) (*entity.LoginThrottle, error)
+	RecordFailure(scope, value string, now, windowStart time.Time) (*entity.LoginThrottle, error)
+	Lock(scope, value string, until time.Time) error
+	Reset(scope, value string) (bool, error)
+	FindLocked(now time.Time) ([]entity.LoginThrottle, error)
+	PurgeStale(before time.Time) (int64, error)
+}
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) Find(scope, value string) (*entity.LoginThrottle, error) {
+	var throttle entity.LoginThrottle
+	if err := r.db.First(&throttle, "scope = ? AND value = ?", scope, value).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// RecordFailure counts a failed login and returns the updated counter. The
+// count starts over when the last failure, and the lock after it, ended
+// before windowStart.
+func (r *loginThrottleRepository) RecordFailure(scope, value string, now, windowStart time.Time) (*entity.LoginThrottle, error) {
+	throttle := entity.LoginThrottle{Scope: scope, Value: value, Failures: 1, LastFailureAt: now}
+	err := r.db.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "scope"}, {Name: "value"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures":        gorm.Expr("CASE WHEN GREATEST(login_throttles.last_failure_at, COALESCE(login_throttles.locked_until, login_throttles.last_failure_at)) < ? THEN 1 ELSE login_throttles.failures + 1 END", windowStart),
+				"last_failure_at": now,
+			}),
+		},
+		clause.Returning{},
+	).Create(&throttle).Error
+	return &throttle, err
+}
+
+func (r *loginThrottleRepository) Lock(scope, value string, until time.Time) error {
+	return r.db.Model(&entity.LoginThrottle{}).
+		Where("scope = ? AND value = ?", scope, value).
+		Update("locked_until", until).Error
+}
+
+// Reset clears the counter, e.g. after a successful login. It reports
+// whether there was one.
+func (r *loginThrottleRepository) Reset(scope, value string) (bool, error) {
+	result := r.db.Where("scope = ? AND value = ?", scope, value).Delete(&entity.LoginThrottle{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindLocked returns the usernames and IPs that are refused until a later
+// time, longest lock first
+func (r *loginThrottleRepository) FindLocked(now time.Time) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	err := r.db.Where("locked_until > ?", now).Order("locked_until DESC").Find(&throttles).Error
+	return throttles, err
+}
+
+// PurgeStale deletes counters whose last failure and lock ended before the
+// time
+func (r *loginThrottleRepository) PurgeStale(before time.Time) (int64, error) {
+	result := r.db.Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, before).
+		Delete(&entity.LoginThrottle{})
+	return result.RowsAffected, result.Error
+}
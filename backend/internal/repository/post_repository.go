@@ -2,18 +2,29 @@ package repository
 
 import (
 	"backend/internal/model/entity"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// PostSitemapEntry is the part of a published post listed in the sitemap
+type PostSitemapEntry struct {
+	ID        uuid.UUID
+	Slug      string
+	UpdatedAt time.Time
+}
+
 type PostRepository interface {
 	FindAll(page, pageSize int, tag, search, status string) ([]entity.BlogPost, int64, error)
 	FindByID(id uuid.UUID) (*entity.BlogPost, error)
+	FindBySlug(slug string) (*entity.BlogPost, error)
+	SlugExists(slug string, excludeID uuid.UUID) (bool, error)
 	Create(post *entity.BlogPost) error
 	Update(post *entity.BlogPost) error
 	Delete(id uuid.UUID) error
-	IncrementViewCount(id uuid.UUID) error
+	CountPublished() (int64, error)
+	FindPublishedSitemapEntries(offset, limit int) ([]PostSitemapEntry, error)
 }
 
 type postRepository struct {
@@ -76,6 +87,21 @@ func (r *postRepository) FindByID(id uuid.UUID) (*entity.BlogPost, error) {
 	return &post, nil
 }
 
+func (r *postRepository) FindBySlug(slug string) (*entity.BlogPost, error) {
+	var post entity.BlogPost
+	if err := r.db.Preload("Tags").Preload("Author").First(&post, "slug = ?", slug).Error; err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// SlugExists reports whether another post than excludeID uses the slug
+func (r *postRepository) SlugExists(slug string, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.BlogPost{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
 func (r *postRepository) Create(post *entity.BlogPost) error {
 	return r.db.Create(post).Error
 }
@@ -88,7 +114,25 @@ func (r *postRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&entity.BlogPost{}, "id = ?", id).Error
 }
 
-func (r *postRepository) IncrementViewCount(id uuid.UUID) error {
-	return r.db.Model(&entity.BlogPost{}).Where("id = ?", id).
-		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+// CountPublished counts published posts that search engines may index
+func (r *postRepository) CountPublished() (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.BlogPost{}).Where("is_published = ? AND NOT no_index", true).Count(&count).Error
+	return count, err
+}
+
+// FindPublishedSitemapEntries pages through published posts in a stable
+// order, leaving out posts marked noindex
+func (r *postRepository) FindPublishedSitemapEntries(offset, limit int) ([]PostSitemapEntry, error) {
+	var entries []PostSitemapEntry
+	err := r.db.Model(&entity.BlogPost{}).
+		Select("id, slug, updated_at").
+		Where("is_published = ? AND NOT no_index", true).
+		Order("published_date DESC, id").
+		Offset(offset).Limit(limit).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
 }
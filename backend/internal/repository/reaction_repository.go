@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository interface {
+	Add(reaction *entity.PostReaction) (bool, error)
+	Remove(postID uuid.UUID, visitorHash, reaction string) error
+	CountByPostID(postID uuid.UUID) (map[string]int64, error)
+	FindVisitorReactions(postID uuid.UUID, visitorHash string) ([]string, error)
+}
+
+type reactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+// Add inserts a reaction, returning false if the visitor already cast it
+func (r *reactionRepository) Add(reaction *entity.PostReaction) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *reactionRepository) Remove(postID uuid.UUID, visitorHash, reaction string) error {
+	return r.db.Where("post_id = ? AND visitor_hash = ? AND reaction = ?", postID, visitorHash, reaction).
+		Delete(&entity.PostReaction{}).Error
+}
+
+func (r *reactionRepository) CountByPostID(postID uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		Reaction string
+		Count    int64
+	}
+	if err := r.db.Model(&entity.PostReaction{}).
+		Select("reaction, COUNT(*) AS count").
+		Where("post_id = ?", postID).
+		Group("reaction").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Reaction] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *reactionRepository) FindVisitorReactions(postID uuid.UUID, visitorHash string) ([]string, error) {
+	var reactions []string
+	if err := r.db.Model(&entity.PostReaction{}).
+		Where("post_id = ? AND visitor_hash = ?", postID, visitorHash).
+		Order("reaction ASC").
+		Pluck("reaction", &reactions).Error; err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
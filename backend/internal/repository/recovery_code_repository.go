@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository interface {
+	Replace(adminID uuid.UUID, codeHashes []string) error
+	Use(adminID uuid.UUID, codeHash string) (bool, error)
+	CountUnused(adminID uuid.UUID) (int64, error)
+	DeleteByAdmin(adminID uuid.UUID) error
+}
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+// Replace deletes the admin's codes and stores new ones
+func (r *recoveryCodeRepository) Replace(adminID uuid.UUID, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("admin_id = ?", adminID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]entity.RecoveryCode, len(codeHashes))
+		for i, hash := range codeHashes {
+			codes[i] = entity.RecoveryCode{AdminID: adminID, CodeHash: hash}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Use marks an unused code as used. It reports false when the admin has no
+// such unused code.
+func (r *recoveryCodeRepository) Use(adminID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.Model(&entity.RecoveryCode{}).
+		Where("admin_id = ? AND code_hash = ? AND used_at IS NULL", adminID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *recoveryCodeRepository) CountUnused(adminID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.RecoveryCode{}).
+		Where("admin_id = ? AND used_at IS NULL", adminID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *recoveryCodeRepository) DeleteByAdmin(adminID uuid.UUID) error {
+	return r.db.Where("admin_id = ?", adminID).Delete(&entity.RecoveryCode{}).Error
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EngagementRow is one post's engagement within a report window
+type EngagementRow struct {
+	PostID    uuid.UUID
+	Title     string
+	Views     int64
+	Reactions int64
+	Comments  int64
+	Score     int64
+}
+
+type ReportRepository interface {
+	EngagementRanking(since time.Time, limit int) ([]EngagementRow, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// EngagementRanking ranks published posts by the views, reactions and
+// comments they received since the given time.
+func (r *reportRepository) EngagementRanking(since time.Time, limit int) ([]EngagementRow, error) {
+	var rows []EngagementRow
+	err := r.db.Raw(`
+		SELECT p.id AS post_id, p.title,
+			COALESCE(v.views, 0) AS views,
+			COALESCE(r.reactions, 0) AS reactions,
+			COALESCE(c.comments, 0) AS comments,
+			COALESCE(v.views, 0) + COALESCE(r.reactions, 0) * 3 + COALESCE(c.comments, 0) * 5 AS score
+		FROM blog_posts p
+		LEFT JOIN (
+			SELECT post_id, SUM(views) AS views FROM post_view_stats
+			WHERE bucket_start >= ? GROUP BY post_id
+		) v ON v.post_id = p.id
+		LEFT JOIN (
+			SELECT post_id, COUNT(*) AS reactions FROM post_reactions
+			WHERE created_at >= ? GROUP BY post_id
+		) r ON r.post_id = p.id
+		LEFT JOIN (
+			SELECT post_id, COUNT(*) AS comments FROM comments
+			WHERE created_at >= ? AND is_deleted = FALSE GROUP BY post_id
+		) c ON c.post_id = p.id
+		WHERE p.is_published = TRUE
+		ORDER BY score DESC, p.published_date DESC
+		LIMIT ?`, since, since, since, limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SearchStatRepository interface {
+	AddSearches(stats []entity.SearchQueryStat) error
+	PurgeBefore(day time.Time) (int64, error)
+}
+
+type searchStatRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchStatRepository(db *gorm.DB) SearchStatRepository {
+	return &searchStatRepository{db: db}
+}
+
+// AddSearches adds a batch of daily search counts
+func (r *searchStatRepository) AddSearches(stats []entity.SearchQueryStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "query"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"searches":     gorm.Expr("search_query_stats.searches + EXCLUDED.searches"),
+			"zero_results": gorm.Expr("search_query_stats.zero_results + EXCLUDED.zero_results"),
+		}),
+	}).Create(&stats).Error
+}
+
+// PurgeBefore deletes the counts of days before the retention period
+func (r *searchStatRepository) PurgeBefore(day time.Time) (int64, error) {
+	result := r.db.Where("day < ?::date", day).Delete(&entity.SearchQueryStat{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SEORepository interface {
+	Enqueue(tasks []entity.SEOPushTask) error
+	FindDueTasks(engine string, now time.Time, limit int) ([]entity.SEOPushTask, error)
+	MarkTasksSent(ids []int64) error
+	MarkTasksFailed(ids []int64, lastError string, retryAt time.Time, maxAttempts int) error
+	CountPendingByEngine() (map[string]int64, error)
+	QuotaUsed(engine string, day time.Time) (int, error)
+	AddQuotaUsed(engine string, day time.Time, n int) error
+	RaiseQuotaUsed(engine string, day time.Time, used int) error
+	CreateHistory(history *entity.SEOPushHistory) error
+	FindHistory(page, pageSize int, engine string) ([]entity.SEOPushHistory, int64, error)
+	FindHistoryByID(id int64) (*entity.SEOPushHistory, error)
+	FindActiveIndexNowKey() (*entity.IndexNowKey, error)
+	FindIndexNowKey(key string) (*entity.IndexNowKey, error)
+	RotateIndexNowKey(key *entity.IndexNowKey) error
+}
+
+type seoRepository struct {
+	db *gorm.DB
+}
+
+func NewSEORepository(db *gorm.DB) SEORepository {
+	return &seoRepository{db: db}
+}
+
+// Enqueue adds push tasks. A URL that is already pending for the engine
+// keeps its place in the queue and takes the newer action.
+func (r *seoRepository) Enqueue(tasks []entity.SEOPushTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "engine"}, {Name: "url"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'pending'"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"action", "updated_at"}),
+	}).CreateInBatches(&tasks, 500).Error
+}
+
+func (r *seoRepository) FindDueTasks(engine string, now time.Time, limit int) ([]entity.SEOPushTask, error) {
+	var tasks []entity.SEOPushTask
+	err := r.db.Where("engine = ? AND status = ? AND next_attempt_at <= ?", engine, entity.SEOTaskPending, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *seoRepository) MarkTasksSent(ids []int64) error {
+	return r.db.Model(&entity.SEOPushTask{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":     entity.SEOTaskSent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "",
+		}).Error
+}
+
+// MarkTasksFailed schedules a retry, or gives up once maxAttempts is reached
+func (r *seoRepository) MarkTasksFailed(ids []int64, lastError string, retryAt time.Time, maxAttempts int) error {
+	return r.db.Model(&entity.SEOPushTask{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":          gorm.Expr("CASE WHEN attempts + 1 >= ? THEN ? ELSE status END", maxAttempts, entity.SEOTaskFailed),
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+			"next_attempt_at": retryAt,
+		}).Error
+}
+
+func (r *seoRepository) CountPendingByEngine() (map[string]int64, error) {
+	var rows []struct {
+		Engine string
+		Count  int64
+	}
+	err := r.db.Model(&entity.SEOPushTask{}).
+		Select("engine, COUNT(*) AS count").
+		Where("status = ?", entity.SEOTaskPending).
+		Group("engine").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Engine] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *seoRepository) QuotaUsed(engine string, day time.Time) (int, error) {
+	var used int
+	err := r.db.Raw(`SELECT COALESCE(SUM(used), 0) FROM seo_push_quotas WHERE engine = ? AND day = ?::date`,
+		engine, day.Format("2006-01-02")).Scan(&used).Error
+	return used, err
+}
+
+func (r *seoRepository) AddQuotaUsed(engine string, day time.Time, n int) error {
+	return r.upsertQuota(engine, day, n, gorm.Expr("seo_push_quotas.used + EXCLUDED.used"))
+}
+
+// RaiseQuotaUsed sets the used quota to at least used, e.g. when an engine
+// reports less remaining quota than we counted
+func (r *seoRepository) RaiseQuotaUsed(engine string, day time.Time, used int) error {
+	return r.upsertQuota(engine, day, used, gorm.Expr("GREATEST(seo_push_quotas.used, EXCLUDED.used)"))
+}
+
+func (r *seoRepository) upsertQuota(engine string, day time.Time, used int, onConflict clause.Expr) error {
+	quota := entity.SEOPushQuota{
+		Engine: engine,
+		Day:    time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC),
+		Used:   used,
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "engine"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"used": onConflict}),
+	}).Create(&quota).Error
+}
+
+func (r *seoRepository) CreateHistory(history *entity.SEOPushHistory) error {
+	return r.db.Create(history).Error
+}
+
+func (r *seoRepository) FindHistory(page, pageSize int, engine string) ([]entity.SEOPushHistory, int64, error) {
+	var history []entity.SEOPushHistory
+	var total int64
+
+	query := r.db.Model(&entity.SEOPushHistory{})
+	if engine != "" {
+		query = query.Where("engine = ?", engine)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&history).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return history, total, nil
+}
+
+func (r *seoRepository) FindHistoryByID(id int64) (*entity.SEOPushHistory, error) {
+	var history entity.SEOPushHistory
+	if err := r.db.First(&history, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+func (r *seoRepository) FindActiveIndexNowKey() (*entity.IndexNowKey, error) {
+	var key entity.IndexNowKey
+	if err := r.db.First(&key, "is_active = ?", true).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *seoRepository) FindIndexNowKey(key string) (*entity.IndexNowKey, error) {
+	var k entity.IndexNowKey
+	if err := r.db.First(&k, "key = ?", key).Error; err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// RotateIndexNowKey retires the active key and makes key the active one
+func (r *seoRepository) RotateIndexNowKey(key *entity.IndexNowKey) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.IndexNowKey{}).Where("is_active = ?", true).
+			Updates(map[string]interface{}{
+				"is_active":  false,
+				"retired_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		key.IsActive = true
+		return tx.Create(key).Error
+	})
+}
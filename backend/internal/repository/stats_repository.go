@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PostCounts struct {
+	Total          int64
+	Published      int64
+	Draft          int64
+	CreatedInRange int64
+}
+
+type CommentCounts struct {
+	Total         int64
+	Active        int64
+	Deleted       int64
+	AwaitingReply int64
+}
+
+type ViewPoint struct {
+	Period time.Time
+	Views  int64
+}
+
+type PostViews struct {
+	PostID uuid.UUID
+	Title  string
+	Views  int64
+}
+
+type ReferrerViews struct {
+	Host  string
+	Views int64
+}
+
+type TagViews struct {
+	Name  string
+	Slug  string
+	Views int64
+}
+
+type SourceVisits struct {
+	ReferrerHost string
+	UTMSource    string
+	UTMMedium    string
+	UTMCampaign  string
+	Visits       int64
+}
+
+type SearchTermRow struct {
+	Query       string
+	Searches    int64
+	ZeroResults int64
+}
+
+type AIUsageRow struct {
+	Operation     string
+	Provider      string
+	Calls         int64
+	Failures      int64
+	AvgDurationMs float64
+}
+
+// StatsRepository runs the aggregate queries behind the admin dashboard.
+// All methods only consider data at or after since.
+type StatsRepository interface {
+	CountPosts(since time.Time) (*PostCounts, error)
+	CountComments(since time.Time) (*CommentCounts, error)
+	TotalViews(since time.Time) (int64, error)
+	ViewSeries(since time.Time, interval string) ([]ViewPoint, error)
+	TopPosts(since time.Time, limit int) ([]PostViews, error)
+	TopReferrers(since time.Time, limit int) ([]ReferrerViews, error)
+	TopTags(since time.Time, limit int) ([]TagViews, error)
+	AIUsage(since time.Time) ([]AIUsageRow, error)
+	TopSources(postID uuid.UUID, since time.Time, limit int) ([]SourceVisits, error)
+	TopSearches(since time.Time, limit int, zeroResultsOnly bool) ([]SearchTermRow, error)
+}
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+func (r *statsRepository) CountPosts(since time.Time) (*PostCounts, error) {
+	var counts PostCounts
+	err := r.db.Raw(`
+		SELECT COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE is_published) AS published,
+			COUNT(*) FILTER (WHERE NOT is_published) AS draft,
+			COUNT(*) FILTER (WHERE created_at >= ?) AS created_in_range
+		FROM blog_posts`, since).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// CountComments groups comments created in range by status. A top-level guest
+// comment is awaiting reply until an admin has replied to it.
+func (r *statsRepository) CountComments(since time.Time) (*CommentCounts, error) {
+	var counts CommentCounts
+	err := r.db.Raw(`
+		SELECT COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE NOT c.is_deleted) AS active,
+			COUNT(*) FILTER (WHERE c.is_deleted) AS deleted,
+			COUNT(*) FILTER (WHERE NOT c.is_deleted AND c.parent_id IS NULL AND c.role = 'guest'
+				AND NOT EXISTS (
+					SELECT 1 FROM comments r
+					WHERE r.parent_id = c.id AND r.role = 'admin' AND NOT r.is_deleted
+				)) AS awaiting_reply
+		FROM comments c
+		WHERE c.created_at >= ?`, since).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+func (r *statsRepository) TotalViews(since time.Time) (int64, error) {
+	var total int64
+	err := r.db.Raw(`SELECT COALESCE(SUM(views), 0) FROM post_view_stats WHERE bucket_start >= ?`, since).
+		Scan(&total).Error
+	return total, err
+}
+
+// ViewSeries sums views per interval ("day" or "week", in UTC)
+func (r *statsRepository) ViewSeries(since time.Time, interval string) ([]ViewPoint, error) {
+	var points []ViewPoint
+	err := r.db.Raw(`
+		SELECT date_trunc(?, bucket_start AT TIME ZONE 'UTC') AS period, SUM(views) AS views
+		FROM post_view_stats
+		WHERE bucket_start >= ?
+		GROUP BY period
+		ORDER BY period ASC`, interval, since).Scan(&points).Error
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func (r *statsRepository) TopPosts(since time.Time, limit int) ([]PostViews, error) {
+	var rows []PostViews
+	err := r.db.Raw(`
+		SELECT p.id AS post_id, p.title, SUM(s.views) AS views
+		FROM post_view_stats s
+		JOIN blog_posts p ON p.id = s.post_id
+		WHERE s.bucket_start >= ?
+		GROUP BY p.id, p.title
+		ORDER BY views DESC
+		LIMIT ?`, since, limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) TopReferrers(since time.Time, limit int) ([]ReferrerViews, error) {
+	var rows []ReferrerViews
+	err := r.db.Raw(`
+		SELECT referrer_host AS host, SUM(visits) AS views
+		FROM post_traffic_sources
+		WHERE day >= ?::date AND referrer_host <> ''
+		GROUP BY referrer_host
+		ORDER BY views DESC
+		LIMIT ?`, since, limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) TopTags(since time.Time, limit int) ([]TagViews, error) {
+	var rows []TagViews
+	err := r.db.Raw(`
+		SELECT t.name, t.slug, SUM(s.views) AS views
+		FROM post_view_stats s
+		JOIN post_tags pt ON pt.post_id = s.post_id
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE s.bucket_start >= ?
+		GROUP BY t.id, t.name, t.slug
+		ORDER BY views DESC
+		LIMIT ?`, since, limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) AIUsage(since time.Time) ([]AIUsageRow, error) {
+	var rows []AIUsageRow
+	err := r.db.Raw(`
+		SELECT operation, provider,
+			COUNT(*) AS calls,
+			COUNT(*) FILTER (WHERE NOT success) AS failures,
+			AVG(duration_ms) AS avg_duration_ms
+		FROM ai_usage_events
+		WHERE created_at >= ?
+		GROUP BY operation, provider
+		ORDER BY calls DESC`, since).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// TopSources ranks the referrer and UTM combinations that brought visits to a post
+func (r *statsRepository) TopSources(postID uuid.UUID, since time.Time, limit int) ([]SourceVisits, error) {
+	var rows []SourceVisits
+	err := r.db.Raw(`
+		SELECT referrer_host, utm_source, utm_medium, utm_campaign, SUM(visits) AS visits
+		FROM post_traffic_sources
+		WHERE post_id = ? AND day >= ?::date
+		GROUP BY referrer_host, utm_source, utm_medium, utm_campaign
+		ORDER BY visits DESC
+		LIMIT ?`, postID, since, limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// TopSearches ranks internal search queries, optionally only those that
+// returned no results at least once.
+func (r *statsRepository) TopSearches(since time.Time, limit int, zeroResultsOnly bool) ([]SearchTermRow, error) {
+	query := r.db.Table("search_query_stats").
+		Select("query, SUM(searches) AS searches, SUM(zero_results) AS zero_results").
+		Where("day >= ?::date", since).
+		Group("query")
+
+	if zeroResultsOnly {
+		query = query.Having("SUM(zero_results) > 0").Order("zero_results DESC")
+	} else {
+		query = query.Order("searches DESC")
+	}
+
+	var rows []SearchTermRow
+	if err := query.Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -2,11 +2,19 @@ package repository
 
 import (
 	"backend/internal/model/entity"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// TagSitemapEntry is a tag page listed in the sitemap, last modified when
+// its most recently updated published post was
+type TagSitemapEntry struct {
+	Slug      string
+	UpdatedAt time.Time
+}
+
 type TagRepository interface {
 	FindAll() ([]entity.Tag, error)
 	FindByID(id uuid.UUID) (*entity.Tag, error)
@@ -16,6 +24,7 @@ type TagRepository interface {
 	FindOrCreateBySlug(tag *entity.Tag) error
 	Create(tag *entity.Tag) error
 	Delete(id uuid.UUID) error
+	FindSitemapEntries() ([]TagSitemapEntry, error)
 }
 
 type tagRepository struct {
@@ -77,3 +86,20 @@ func (r *tagRepository) FindOrCreateBySlug(tag *entity.Tag) error {
 func (r *tagRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&entity.Tag{}, "id = ?", id).Error
 }
+
+// FindSitemapEntries returns the tags that have at least one published post
+func (r *tagRepository) FindSitemapEntries() ([]TagSitemapEntry, error) {
+	var entries []TagSitemapEntry
+	err := r.db.Raw(`
+		SELECT t.slug, MAX(p.updated_at) AS updated_at
+		FROM tags t
+		JOIN post_tags pt ON pt.tag_id = t.id
+		JOIN blog_posts p ON p.id = pt.post_id
+		WHERE p.is_published
+		GROUP BY t.id, t.slug
+		ORDER BY t.slug`).Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"backend/internal/model/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ViewBatch is a set of buffered views flushed together
+type ViewBatch struct {
+	Stats   []entity.PostViewStat
+	Sources []entity.PostTrafficSource
+}
+
+type ViewRepository interface {
+	AddViews(batch ViewBatch) error
+	PurgeSourcesBefore(day time.Time) (int64, error)
+}
+
+type viewRepository struct {
+	db *gorm.DB
+}
+
+func NewViewRepository(db *gorm.DB) ViewRepository {
+	return &viewRepository{db: db}
+}
+
+// AddViews adds a batch of view counts to the hourly stats table, each
+// post's view_count and the daily traffic sources, in a single
+// transaction. Views of posts deleted since they
+// were buffered are dropped, as their rows would violate the foreign keys.
+func (r *viewRepository) AddViews(batch ViewBatch) error {
+	if len(batch.Stats) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		batch, err := existingPostViews(tx, batch)
+		if err != nil || len(batch.Stats) == 0 {
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "post_id"}, {Name: "bucket_start"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("post_view_stats.views + EXCLUDED.views")}),
+		}).Create(&batch.Stats).Error; err != nil {
+			return err
+		}
+
+		for _, stat := range batch.Stats {
+			if err := tx.Model(&entity.BlogPost{}).Where("id = ?", stat.PostID).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", stat.Views)).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(batch.Sources) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{
+				{Name: "day"}, {Name: "post_id"}, {Name: "referrer_host"},
+				{Name: "utm_source"}, {Name: "utm_medium"}, {Name: "utm_campaign"},
+			},
+			DoUpdates: clause.Assignments(map[string]interface{}{"visits": gorm.Expr("post_traffic_sources.visits + EXCLUDED.visits")}),
+		}).Create(&batch.Sources).Error
+	})
+}
+
+// PurgeSourcesBefore deletes the traffic sources of days before the
+// retention period. Hourly view counts are kept for the view charts.
+func (r *viewRepository) PurgeSourcesBefore(day time.Time) (int64, error) {
+	result := r.db.Where("day < ?::date", day).Delete(&entity.PostTrafficSource{})
+	return result.RowsAffected, result.Error
+}
+
+// existingPostViews filters the batch to posts that still exist. The rows
+// are share locked, so the posts cannot be deleted before the transaction
+// ends.
+func existingPostViews(tx *gorm.DB, batch ViewBatch) (ViewBatch, error) {
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, stat := range batch.Stats {
+		if !seen[stat.PostID] {
+			seen[stat.PostID] = true
+			ids = append(ids, stat.PostID)
+		}
+	}
+
+	var existing []uuid.UUID
+	if err := tx.Model(&entity.BlogPost{}).Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+		return batch, err
+	}
+	if len(existing) == len(ids) {
+		return batch, nil
+	}
+
+	exists := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		exists[id] = true
+	}
+	filtered := ViewBatch{}
+	for _, stat := range batch.Stats {
+		if exists[stat.PostID] {
+			filtered.Stats = append(filtered.Stats, stat)
+		}
+	}
+	for _, source := range batch.Sources {
+		if exists[source.PostID] {
+			filtered.Sources = append(filtered.Sources, source)
+		}
+	}
+	return filtered, nil
+}
@@ -43,6 +43,7 @@ type AIService interface {
 	Chat(ctx context.Context, message string) (string, error)
 	ChatStream(ctx context.Context, message string, onChunk func(chunk string)) error
 	SummarizePost(ctx context.Context, title, content string) (string, error)
+	Provider() string
 }
 
 type aiService struct {
@@ -146,6 +147,11 @@ func NewAIServiceFromEnv() (AIService, error) {
 	})
 }
 
+// Provider returns the configured AI provider name
+func (s *aiService) Provider() string {
+	return s.provider
+}
+
 func ctx() context.Context {
 	return context.Background()
 }
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"context"
+	"log"
+	"time"
+)
+
+// aiUsageRecorder wraps an AIService and records every call for the
+// admin statistics.
+type aiUsageRecorder struct {
+	AIService
+	usageRepo repository.AIUsageRepository
+}
+
+// NewAIUsageRecorder returns an AIService that records the usage of next
+func NewAIUsageRecorder(next AIService, usageRepo repository.AIUsageRepository) AIService {
+	return &aiUsageRecorder{
+		AIService: next,
+		usageRepo: usageRepo,
+	}
+}
+
+func (s *aiUsageRecorder) GenerateExcerpt(ctx context.Context, content string) (string, error) {
+	start := time.Now()
+	result, err := s.AIService.GenerateExcerpt(ctx, content)
+	s.record("excerpt", start, err)
+	return result, err
+}
+
+func (s *aiUsageRecorder) GenerateReadTime(ctx context.Context, content string) (string, error) {
+	start := time.Now()
+	result, err := s.AIService.GenerateReadTime(ctx, content)
+	s.record("readtime", start, err)
+	return result, err
+}
+
+func (s *aiUsageRecorder) GenerateTags(ctx context.Context, content string) ([]string, error) {
+	start := time.Now()
+	tags, err := s.AIService.GenerateTags(ctx, content)
+	s.record("tags", start, err)
+	return tags, err
+}
+
+func (s *aiUsageRecorder) Chat(ctx context.Context, message string) (string, error) {
+	start := time.Now()
+	result, err := s.AIService.Chat(ctx, message)
+	s.record("chat", start, err)
+	return result, err
+}
+
+func (s *aiUsageRecorder) ChatStream(ctx context.Context, message string, onChunk func(chunk string)) error {
+	start := time.Now()
+	err := s.AIService.ChatStream(ctx, message, onChunk)
+	s.record("chat_stream", start, err)
+	return err
+}
+
+func (s *aiUsageRecorder) SummarizePost(ctx context.Context, title, content string) (string, error) {
+	start := time.Now()
+	result, err := s.AIService.SummarizePost(ctx, title, content)
+	s.record("summarize", start, err)
+	return result, err
+}
+
+func (s *aiUsageRecorder) record(operation string, start time.Time, callErr error) {
+	event := &entity.AIUsageEvent{
+		Operation:  operation,
+		Provider:   s.Provider(),
+		Success:    callErr == nil,
+		DurationMs: int(time.Since(start).Milliseconds()),
+	}
+	if err := s.usageRepo.Create(event); err != nil {
+		log.Printf("Failed to record AI usage: %v", err)
+	}
+}
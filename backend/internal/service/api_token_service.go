@@ -0,0 +1,223 @@
+package service
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAPITokenNotFound = errors.New("API token not found")
+	ErrInvalidAPIToken  = errors.New("invalid or expired API token")
+	ErrInvalidScope     = errors.New("invalid token scope")
+)
+
+const (
+	// APITokenPrefix starts every API token, telling them apart from JWTs
+	// and making leaked tokens easy to search for
+	APITokenPrefix = "dlp_"
+	// apiTokenDisplayLength is how much of the token is kept to show in lists
+	apiTokenDisplayLength = len(APITokenPrefix) + 8
+	// apiTokenUseInterval limits how often last use is written
+	apiTokenUseInterval = time.Minute
+)
+
+// tokenScopes are the permissions an API token may carry. Managing users,
+// security and the admin's own account, and reading the audit log, needs a
+// login session.
+var tokenScopes = []Permission{
+	PermWritePosts, PermEditAnyPost, PermManageTags, PermModerateComment,
+	PermViewStats, PermManageSEO, PermUseAI, PermUpload,
+}
+
+type APITokenService interface {
+	ListTokens(adminID uuid.UUID) ([]dto.APITokenResponse, error)
+	CreateToken(actor Actor, req dto.CreateAPITokenRequest) (*dto.CreatedAPITokenResponse, error)
+	RevokeToken(adminID, id uuid.UUID) error
+	Authenticate(token string, visitor Visitor) (*ValidatedAPIToken, error)
+}
+
+// ValidatedAPIToken is an API token accepted by Authenticate
+type ValidatedAPIToken struct {
+	ID      uuid.UUID
+	AdminID uuid.UUID
+	Scopes  []Permission // never nil, which would make the token a session
+}
+
+type apiTokenService struct {
+	tokenRepo repository.APITokenRepository
+}
+
+func NewAPITokenService(tokenRepo repository.APITokenRepository) APITokenService {
+	return &apiTokenService{tokenRepo: tokenRepo}
+}
+
+// ListTokens returns the admin's tokens that are not revoked
+func (s *apiTokenService) ListTokens(adminID uuid.UUID) ([]dto.APITokenResponse, error) {
+	tokens, err := s.tokenRepo.FindByAdmin(adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.APITokenResponse, len(tokens))
+	for i := range tokens {
+		responses[i] = toAPITokenResponse(&tokens[i])
+	}
+	return responses, nil
+}
+
+// CreateToken issues a token for the actor. Scopes must be ones tokens may
+// carry and that the actor's role grants.
+func (s *apiTokenService) CreateToken(actor Actor, req dto.CreateAPITokenRequest) (*dto.CreatedAPITokenResponse, error) {
+	scopes, err := parseScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		if !actor.Can(scope) {
+			return nil, ErrForbidden
+		}
+	}
+
+	secret, err := newAPIToken()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &entity.APIToken{
+		AdminID:   actor.ID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    secret[:apiTokenDisplayLength],
+		TokenHash: hashToken(secret),
+		Scopes:    joinScopes(scopes),
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+
+	return &dto.CreatedAPITokenResponse{
+		APITokenResponse: toAPITokenResponse(token),
+		Token:            secret,
+	}, nil
+}
+
+func (s *apiTokenService) RevokeToken(adminID, id uuid.UUID) error {
+	revoked, err := s.tokenRepo.Revoke(adminID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// Authenticate looks up a token presented as a bearer token and records its
+// use
+func (s *apiTokenService) Authenticate(secret string, visitor Visitor) (*ValidatedAPIToken, error) {
+	if !strings.HasPrefix(secret, APITokenPrefix) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	token, err := s.tokenRepo.FindByHash(hashToken(secret))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil || (token.ExpiresAt != nil && now.After(*token.ExpiresAt)) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	if err := s.tokenRepo.MarkUsed(token.ID, visitor.IP, now, apiTokenUseInterval); err != nil {
+		log.Printf("Failed to record use of API token %s: %v", token.ID, err)
+	}
+
+	// A token whose scopes cannot be read must not fall back to the
+	// permissions of a login session
+	scopes, err := parseScopes(strings.Fields(token.Scopes))
+	if err != nil {
+		log.Printf("Refusing API token %s with invalid scopes: %v", token.ID, err)
+		return nil, ErrInvalidAPIToken
+	}
+	if scopes == nil {
+		scopes = []Permission{}
+	}
+	return &ValidatedAPIToken{
+		ID:      token.ID,
+		AdminID: token.AdminID,
+		Scopes:  scopes,
+	}, nil
+}
+
+// parseScopes checks and deduplicates requested scopes
+func parseScopes(names []string) ([]Permission, error) {
+	var scopes []Permission
+	seen := make(map[Permission]bool)
+	for _, name := range names {
+		scope := Permission(strings.TrimSpace(name))
+		if !isTokenScope(scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidScope, name)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func isTokenScope(scope Permission) bool {
+	for _, s := range tokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func joinScopes(scopes []Permission) string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+	return strings.Join(names, " ")
+}
+
+func newAPIToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return APITokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func toAPITokenResponse(token *entity.APIToken) dto.APITokenResponse {
+	return dto.APITokenResponse{
+		ID:         token.ID.String(),
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     strings.Fields(token.Scopes),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		LastUsedIP: token.LastUsedIP,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Audit target types
+const (
+	AuditTargetPost     = "post"
+	AuditTargetTag      = "tag"
+	AuditTargetComment  = "comment"
+	AuditTargetUser     = "user"
+	AuditTargetSession  = "session"
+	AuditTargetAPIToken = "api_token"
+	AuditTargetLockout  = "lockout"
+	AuditTargetSEO      = "seo"
+	AuditTargetUpload   = "upload"
+)
+
+// ErrInvalidAuditFilter means a filter of the audit log query is malformed
+var ErrInvalidAuditFilter = errors.New("invalid audit log filter")
+
+// auditPurgeInterval is how often entries past the retention are deleted
+const auditPurgeInterval = time.Hour
+
+// auditMaxValueLength is the number of characters of a changed value kept
+// in the log, longer values like post content are cut
+const auditMaxValueLength = 200
+
+// auditSensitiveKeys are request fields never written to the log, matched
+// case-insensitively anywhere in the field name
+var auditSensitiveKeys = []string{"password", "secret", "token", "code"}
+
+// AuditSnapshot is the audited fields of a target at one point in time
+type AuditSnapshot map[string]interface{}
+
+// AuditChange is a changed field, From is nil for created targets and To is
+// nil for deleted ones
+type AuditChange struct {
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// AuditEntry is a finished mutating admin request. Before and After are
+// snapshots of targets Snapshot supports; for other targets the fields of
+// the JSON request body are logged instead.
+type AuditEntry struct {
+	ActorID       uuid.UUID
+	ActorUsername string
+	APITokenID    *uuid.UUID
+	Action        string
+	TargetType    string
+	TargetID      string
+	Method        string
+	Path          string
+	Status        int
+	Before        AuditSnapshot
+	After         AuditSnapshot
+	Request       map[string]interface{}
+	Visitor       Visitor
+}
+
+type AuditService interface {
+	// Snapshot returns the current state of a post, tag, comment or user,
+	// nil when it does not exist or the type has no snapshots
+	Snapshot(targetType, targetID string) AuditSnapshot
+	Record(entry AuditEntry)
+	List(query dto.AuditLogQuery) (*dto.AuditLogListResponse, error)
+	// Start deletes entries older than the retention until ctx is cancelled
+	Start(ctx context.Context)
+}
+
+type auditService struct {
+	auditRepo   repository.AuditLogRepository
+	postRepo    repository.PostRepository
+	tagRepo     repository.TagRepository
+	commentRepo repository.CommentRepository
+	adminRepo   repository.AdminRepository
+	retention   time.Duration // 0 keeps entries forever
+}
+
+func NewAuditService(auditRepo repository.AuditLogRepository, postRepo repository.PostRepository, tagRepo repository.TagRepository, commentRepo repository.CommentRepository, adminRepo repository.AdminRepository, cfg config.AuditConfig) AuditService {
+	retention, err := time.ParseDuration(cfg.Retention)
+	if err != nil || retention < 0 {
+		retention = 90 * 24 * time.Hour
+	}
+
+	return &auditService{
+		auditRepo:   auditRepo,
+		postRepo:    postRepo,
+		tagRepo:     tagRepo,
+		commentRepo: commentRepo,
+		adminRepo:   adminRepo,
+		retention:   retention,
+	}
+}
+
+func (s *auditService) Start(ctx context.Context) {
+	if s.retention == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(auditPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.auditRepo.PurgeBefore(time.Now().Add(-s.retention)); err != nil {
+				log.Printf("Failed to purge audit log: %v", err)
+			}
+		}
+	}
+}
+
+func (s *auditService) Snapshot(targetType, targetID string) AuditSnapshot {
+	id, err := uuid.Parse(targetID)
+	if err != nil {
+		return nil
+	}
+
+	switch targetType {
+	case AuditTargetPost:
+		if post, err := s.postRepo.FindByID(id); err == nil {
+			return postSnapshot(post)
+		}
+	case AuditTargetTag:
+		if tag, err := s.tagRepo.FindByID(id); err == nil {
+			return AuditSnapshot{"name": tag.Name, "slug": tag.Slug}
+		}
+	case AuditTargetComment:
+		if comment, err := s.commentRepo.FindByID(id); err == nil {
+			return AuditSnapshot{
+				"post_id":   uuidValue(comment.PostID),
+				"parent_id": uuidValue(comment.ParentID),
+				"author":    comment.Author,
+				"content":   comment.Content,
+				"role":      comment.Role,
+			}
+		}
+	case AuditTargetUser:
+		if admin, err := s.adminRepo.FindByID(id); err == nil {
+			email := ""
+			if admin.Email != nil {
+				email = *admin.Email
+			}
+			return AuditSnapshot{
+				"username":     admin.Username,
+				"email":        email,
+				"role":         admin.Role,
+				"totp_enabled": admin.TOTPEnabled,
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotTargets are the target types Snapshot supports
+var snapshotTargets = map[string]bool{
+	AuditTargetPost:    true,
+	AuditTargetTag:     true,
+	AuditTargetComment: true,
+	AuditTargetUser:    true,
+}
+
+func postSnapshot(post *entity.BlogPost) AuditSnapshot {
+	tags := make([]string, len(post.Tags))
+	for i, tag := range post.Tags {
+		tags[i] = tag.Name
+	}
+	sort.Strings(tags)
+
+	return AuditSnapshot{
+		"title":            post.Title,
+		"slug":             post.Slug,
+		"excerpt":          post.Excerpt,
+		"content":          post.Content,
+		"read_time":        post.ReadTime,
+		"published_date":   post.PublishedDate.Format("2006-01-02"),
+		"is_published":     post.IsPublished,
+		"author_id":        uuidValue(post.AuthorID),
+		"tags":             strings.Join(tags, ", "),
+		"meta_title":       post.MetaTitle,
+		"meta_description": post.MetaDescription,
+		"canonical_url":    post.CanonicalURL,
+		"og_image":         post.OGImage,
+		"no_index":         post.NoIndex,
+	}
+}
+
+func uuidValue(id *uuid.UUID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+// Record saves the entry. Failures are only logged, the request itself has
+// already been answered.
+func (s *auditService) Record(entry AuditEntry) {
+	var changes map[string]AuditChange
+	if snapshotTargets[entry.TargetType] {
+		changes = diffSnapshots(entry.Before, entry.After)
+	} else {
+		changes = requestChanges(entry.Request)
+	}
+
+	action := entry.Action
+	if action == "post.update" {
+		if change, ok := changes["is_published"]; ok {
+			if change.To == true {
+				action = "post.publish"
+			} else {
+				action = "post.unpublish"
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		log.Printf("Failed to encode audit changes: %v", err)
+		encoded = []byte("{}")
+	}
+
+	auditLog := &entity.AuditLog{
+		ActorID:       entry.ActorID,
+		ActorUsername: entry.ActorUsername,
+		APITokenID:    entry.APITokenID,
+		Action:        action,
+		TargetType:    entry.TargetType,
+		TargetID:      entry.TargetID,
+		Method:        entry.Method,
+		Path:          entry.Path,
+		Status:        entry.Status,
+		Changes:       string(encoded),
+		IP:            entry.Visitor.IP,
+		UserAgent:     entry.Visitor.UserAgent,
+	}
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Printf("Failed to record audit log %s by %s: %v", action, entry.ActorUsername, err)
+	}
+}
+
+// diffSnapshots returns the fields whose values differ, with long values cut
+func diffSnapshots(before, after AuditSnapshot) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+	for field, from := range before {
+		if to, ok := after[field]; !ok || to != from {
+			changes[field] = AuditChange{From: auditValue(from), To: auditValue(after[field])}
+		}
+	}
+	for field, to := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = AuditChange{To: auditValue(to)}
+		}
+	}
+	return changes
+}
+
+// requestChanges logs the fields of a request body as new values, with
+// passwords, secrets and codes redacted
+func requestChanges(request map[string]interface{}) map[string]AuditChange {
+	changes := make(map[string]AuditChange, len(request))
+	for field, value := range request {
+		if sensitiveAuditKey(field) {
+			value = "[redacted]"
+		}
+		changes[field] = AuditChange{To: auditValue(value)}
+	}
+	return changes
+}
+
+func sensitiveAuditKey(field string) bool {
+	field = strings.ToLower(field)
+	for _, key := range auditSensitiveKeys {
+		if strings.Contains(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditValue cuts long strings, so the log keeps a summary of post content
+// rather than a copy
+func auditValue(value interface{}) interface{} {
+	text, ok := value.(string)
+	if !ok || utf8.RuneCountInString(text) <= auditMaxValueLength {
+		return value
+	}
+	runes := []rune(text)
+	return string(runes[:auditMaxValueLength]) + "…"
+}
+
+func (s *auditService) List(query dto.AuditLogQuery) (*dto.AuditLogListResponse, error) {
+	filter := repository.AuditLogFilter{
+		Action:     query.Action,
+		TargetType: query.TargetType,
+		TargetID:   query.TargetID,
+	}
+	if query.ActorID != "" {
+		actorID, err := uuid.Parse(query.ActorID)
+		if err != nil {
+			return nil, ErrInvalidAuditFilter
+		}
+		filter.ActorID = &actorID
+	}
+	if query.Since != "" {
+		since, err := time.ParseInLocation("2006-01-02", query.Since, time.Local)
+		if err != nil {
+			return nil, ErrInvalidAuditFilter
+		}
+		filter.Since = &since
+	}
+	if query.Until != "" {
+		until, err := time.ParseInLocation("2006-01-02", query.Until, time.Local)
+		if err != nil {
+			return nil, ErrInvalidAuditFilter
+		}
+		until = until.AddDate(0, 0, 1)
+		filter.Until = &until
+	}
+
+	logs, total, err := s.auditRepo.FindAll(filter, query.Page, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize > 0 {
+		totalPages++
+	}
+
+	items := make([]dto.AuditLogResponse, len(logs))
+	for i, entry := range logs {
+		items[i] = dto.AuditLogResponse{
+			ID:            entry.ID,
+			ActorID:       entry.ActorID.String(),
+			ActorUsername: entry.ActorUsername,
+			Action:        entry.Action,
+			TargetType:    entry.TargetType,
+			TargetID:      entry.TargetID,
+			Method:        entry.Method,
+			Path:          entry.Path,
+			Status:        entry.Status,
+			Changes:       json.RawMessage(entry.Changes),
+			IP:            entry.IP,
+			UserAgent:     entry.UserAgent,
+			CreatedAt:     entry.CreatedAt,
+		}
+		if entry.APITokenID != nil {
+			items[i].APITokenID = entry.APITokenID.String()
+		}
+	}
+
+	return &dto.AuditLogListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
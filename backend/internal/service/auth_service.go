@@ -1,101 +1,509 @@
 package service
 
 import (
+	"backend/config"
 	"backend/internal/model/dto"
+	"backend/internal/model/entity"
 	"backend/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
-var jwtSecret = []byte("your-secret-key-change-in-production") // TODO: Move to config
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, the session has been revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInvalidChallenge    = errors.New("invalid or expired login challenge")
+)
+
+const (
+	// challengeTTL is how long the second login step may take
+	challengeTTL = 5 * time.Minute
+	// challengeAudience keeps challenge tokens apart from access tokens
+	challengeAudience = "devlog-2fa"
+)
+
+// dummyPasswordHash is compared against when the username is unknown, so
+// that takes as long as a wrong password
+const dummyPasswordHash = "$2a$10$XmLPdPneRBqLckQrGqGKReIBkZkRcQePkDHYxsWldCr/zZvcnZuO2"
+
+// recentLoginCount is how many login attempts GET /auth/me includes
+const recentLoginCount = 10
+
+// tokenPurgeInterval is how often expired refresh tokens and revocations
+// are deleted
+const tokenPurgeInterval = time.Hour
 
 type AuthService interface {
-	Login(req dto.LoginRequest) (*dto.LoginResponse, error)
-	ValidateToken(tokenString string) (*uuid.UUID, error)
+	Start(ctx context.Context)
+	Login(req dto.LoginRequest, visitor Visitor) (*dto.LoginResponse, error)
+	VerifyLogin(req dto.TwoFactorLoginRequest, visitor Visitor) (*dto.LoginResponse, error)
+	Refresh(refreshToken string, visitor Visitor) (*dto.LoginResponse, error)
+	Logout(token *AccessToken) error
+	LoginExternal(admin *entity.Admin, visitor Visitor) (*dto.LoginResponse, error)
+	LogoutAll(token *AccessToken) (int64, error)
+	ListSessions(token *AccessToken) ([]dto.SessionResponse, error)
+	RevokeSession(adminID, sessionID uuid.UUID) error
+	GetLoginHistory(adminID uuid.UUID, query dto.LoginHistoryQuery) (*dto.LoginHistoryResponse, error)
+	ValidateToken(tokenString string) (*AccessToken, error)
 	GetAdminByID(id uuid.UUID) (*dto.AdminResponse, error)
+	GetCurrentUser(id uuid.UUID) (*dto.CurrentUserResponse, error)
+}
+
+// AccessToken is a validated access token
+type AccessToken struct {
+	ID        string // jti
+	AdminID   uuid.UUID
+	SessionID uuid.UUID // Refresh token family the token was issued for
+	ExpiresAt time.Time
 }
 
 type authService struct {
-	adminRepo repository.AdminRepository
+	adminRepo  repository.AdminRepository
+	tokenRepo  repository.AuthTokenRepository
+	eventRepo  repository.AuthEventRepository
+	twoFactor  TwoFactorService
+	throttle   LoginThrottleService
+	keys       *jwtKeyring
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	retention  time.Duration // how long login history is kept, 0 forever
 }
 
-func NewAuthService(adminRepo repository.AdminRepository) AuthService {
-	return &authService{adminRepo: adminRepo}
+func NewAuthService(adminRepo repository.AdminRepository, tokenRepo repository.AuthTokenRepository, eventRepo repository.AuthEventRepository, twoFactor TwoFactorService, throttle LoginThrottleService, jwtCfg config.JWTConfig, loginCfg config.LoginConfig) AuthService {
+	accessTTL, err := time.ParseDuration(jwtCfg.AccessTTL)
+	if err != nil || accessTTL <= 0 {
+		accessTTL = 15 * time.Minute
+	}
+
+	refreshTTL, err := time.ParseDuration(jwtCfg.RefreshTTL)
+	if err != nil || refreshTTL <= 0 {
+		refreshTTL = 30 * 24 * time.Hour
+	}
+
+	retention, err := time.ParseDuration(loginCfg.HistoryRetention)
+	if err != nil || retention < 0 {
+		retention = 90 * 24 * time.Hour
+	}
+
+	return &authService{
+		adminRepo:  adminRepo,
+		tokenRepo:  tokenRepo,
+		eventRepo:  eventRepo,
+		twoFactor:  twoFactor,
+		throttle:   throttle,
+		keys:       newJWTKeyring(jwtCfg),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		retention:  retention,
+	}
 }
 
 type Claims struct {
-	AdminID string `json:"admin_id"`
+	AdminID   string `json:"admin_id"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
+// challengeClaims prove the password of an admin with 2FA was correct. They
+// carry the admin in Subject and no admin_id, so ValidateToken rejects them.
+type challengeClaims struct {
+	jwt.RegisteredClaims
+}
+
+// Start purges expired tokens, stale login throttles and login history past
+// its retention periodically until ctx is cancelled
+func (s *authService) Start(ctx context.Context) {
+	ticker := time.NewTicker(tokenPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.tokenRepo.PurgeExpired(time.Now()); err != nil {
+				log.Printf("Failed to purge expired tokens: %v", err)
+			}
+			if _, err := s.throttle.PurgeStale(); err != nil {
+				log.Printf("Failed to purge login throttles: %v", err)
+			}
+			if s.retention > 0 {
+				if _, err := s.eventRepo.PurgeBefore(time.Now().Add(-s.retention)); err != nil {
+					log.Printf("Failed to purge login history: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Login checks the credentials and starts a new session. Every attempt is
+// recorded in the login history, and failures are throttled per username
+// and IP.
+func (s *authService) Login(req dto.LoginRequest, visitor Visitor) (*dto.LoginResponse, error) {
+	if err := s.throttle.Check(req.Username, visitor.IP); err != nil {
+		return nil, err
+	}
+
 	admin, err := s.adminRepo.FindByUsername(req.Username)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(req.Password))
+		s.throttle.RecordFailure(req.Username, visitor)
+		s.recordLogin(nil, req.Username, nil, "unknown_user", visitor)
+		return nil, ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(req.Password)); err != nil {
-		return nil, errors.New("invalid credentials")
+		s.throttle.RecordFailure(req.Username, visitor)
+		s.recordLogin(&admin.ID, req.Username, nil, "invalid_password", visitor)
+		return nil, ErrInvalidCredentials
+	}
+
+	if admin.TOTPEnabled {
+		return s.issueChallenge(admin)
+	}
+
+	return s.completeLogin(admin, visitor)
+}
+
+// VerifyLogin is the second login step of an admin with 2FA: it exchanges
+// the challenge token and a code for access and refresh tokens
+func (s *authService) VerifyLogin(req dto.TwoFactorLoginRequest, visitor Visitor) (*dto.LoginResponse, error) {
+	token, err := s.keys.parse(req.ChallengeToken, &challengeClaims{}, jwt.WithAudience(challengeAudience))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidChallenge
+	}
+	claims, ok := token.Claims.(*challengeClaims)
+	if !ok {
+		return nil, ErrInvalidChallenge
+	}
+	adminID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	admin, err := s.adminRepo.FindByID(adminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidChallenge
+		}
+		return nil, err
+	}
+	if !admin.TOTPEnabled {
+		return nil, ErrInvalidChallenge
+	}
+	if err := s.throttle.Check(admin.Username, visitor.IP); err != nil {
+		return nil, err
 	}
 
+	valid, err := s.twoFactor.Verify(admin, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		s.throttle.RecordFailure(admin.Username, visitor)
+		s.recordLogin(&admin.ID, admin.Username, nil, "invalid_2fa_code", visitor)
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	return s.completeLogin(admin, visitor)
+}
+
+// LoginExternal starts a session for an admin an identity provider has
+// authenticated, skipping the password and local two-factor check
+func (s *authService) LoginExternal(admin *entity.Admin, visitor Visitor) (*dto.LoginResponse, error) {
+	return s.completeLogin(admin, visitor)
+}
+
+func (s *authService) issueChallenge(admin *entity.Admin) (*dto.LoginResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(challengeTTL)
+	challenge, err := s.keys.sign(&challengeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   admin.ID.String(),
+			Audience:  jwt.ClaimStrings{challengeAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "devlog",
+		},
+	})
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &dto.LoginResponse{
+		TwoFactorRequired:  true,
+		ChallengeToken:     challenge,
+		ChallengeExpiresAt: expiresAt.Unix(),
+	}, nil
+}
+
+// completeLogin starts a new session once all factors are checked
+func (s *authService) completeLogin(admin *entity.Admin, visitor Visitor) (*dto.LoginResponse, error) {
 	// Update last login
 	_ = s.adminRepo.UpdateLastLogin(admin.ID)
+	s.throttle.RecordSuccess(admin.Username)
+
+	sessionID := uuid.New()
+	response, err := s.issueTokens(admin, sessionID, visitor)
+	if err != nil {
+		return nil, err
+	}
+	s.recordLogin(&admin.ID, admin.Username, &sessionID, "", visitor)
+	return response, nil
+}
+
+// recordLogin stores a login attempt; an empty reason means it succeeded.
+// Failing to record does not fail the login.
+func (s *authService) recordLogin(adminID *uuid.UUID, username string, sessionID *uuid.UUID, reason string, visitor Visitor) {
+	err := s.eventRepo.Create(&entity.AuthEvent{
+		AdminID:   adminID,
+		Username:  truncateRunes(username, 50),
+		Type:      entity.AuthEventLogin,
+		Success:   reason == "",
+		Reason:    reason,
+		IP:        visitor.IP,
+		UserAgent: visitor.UserAgent,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		log.Printf("Failed to record login of %q: %v", username, err)
+	}
+}
 
-	// Generate JWT
-	expiresAt := time.Now().Add(24 * time.Hour)
+// Refresh exchanges a refresh token for a new access and refresh token.
+// A refresh token works once; using it again revokes its whole family.
+func (s *authService) Refresh(refreshToken string, visitor Visitor) (*dto.LoginResponse, error) {
+	token, err := s.tokenRepo.FindRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if token.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if token.UsedAt != nil {
+		return nil, s.revokeReusedFamily(token)
+	}
+
+	fresh, err := s.tokenRepo.MarkRefreshTokenUsed(token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, s.revokeReusedFamily(token)
+	}
+
+	admin, err := s.adminRepo.FindByID(token.AdminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return s.issueTokens(admin, token.FamilyID, visitor)
+}
+
+func (s *authService) revokeReusedFamily(token *entity.RefreshToken) error {
+	log.Printf("Refresh token reuse detected for admin %s, revoking session %s", token.AdminID, token.FamilyID)
+	if err := s.tokenRepo.RevokeFamily(token.FamilyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// Logout ends the session of the access token
+func (s *authService) Logout(token *AccessToken) error {
+	if err := s.tokenRepo.RevokeFamily(token.SessionID); err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeAccessToken(token.ID, token.ExpiresAt)
+}
+
+// LogoutAll ends every session of the admin and returns how many there were
+func (s *authService) LogoutAll(token *AccessToken) (int64, error) {
+	revoked, err := s.tokenRepo.RevokeAllFamilies(token.AdminID)
+	if err != nil {
+		return 0, err
+	}
+	return revoked, s.tokenRepo.RevokeAccessToken(token.ID, token.ExpiresAt)
+}
+
+// ListSessions returns the active sessions of the token's admin, marking the
+// one the token belongs to
+func (s *authService) ListSessions(token *AccessToken) ([]dto.SessionResponse, error) {
+	sessions, err := s.tokenRepo.FindActiveSessions(token.AdminID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = dto.SessionResponse{
+			ID:         session.FamilyID.String(),
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.StartedAt,
+			LastUsedAt: session.CreatedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.FamilyID == token.SessionID,
+		}
+	}
+	return response, nil
+}
+
+// RevokeSession ends a session of the admin. Its refresh token stops
+// working at once and its access tokens are rejected by ValidateToken.
+func (s *authService) RevokeSession(adminID, sessionID uuid.UUID) error {
+	revoked, err := s.tokenRepo.RevokeAdminFamily(adminID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s *authService) GetLoginHistory(adminID uuid.UUID, query dto.LoginHistoryQuery) (*dto.LoginHistoryResponse, error) {
+	events, total, err := s.eventRepo.FindByAdmin(adminID, query.Page, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize > 0 {
+		totalPages++
+	}
+
+	return &dto.LoginHistoryResponse{
+		Items:      loginEventResponses(events),
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func loginEventResponses(events []entity.AuthEvent) []dto.LoginEventResponse {
+	items := make([]dto.LoginEventResponse, len(events))
+	for i, e := range events {
+		items[i] = dto.LoginEventResponse{
+			ID:        e.ID,
+			Type:      e.Type,
+			Success:   e.Success,
+			Reason:    e.Reason,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			CreatedAt: e.CreatedAt,
+		}
+	}
+	return items
+}
+
+// issueTokens signs an access token and creates the next refresh token of
+// the session
+func (s *authService) issueTokens(admin *entity.Admin, sessionID uuid.UUID, visitor Visitor) (*dto.LoginResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.accessTTL)
 	claims := &Claims{
-		AdminID: admin.ID.String(),
+		AdminID:   admin.ID.String(),
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "devlog",
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := s.keys.sign(claims)
 	if err != nil {
 		return nil, errors.New("failed to generate token")
 	}
 
-	email := ""
-	if admin.Email != nil {
-		email = *admin.Email
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	refreshExpiresAt := now.Add(s.refreshTTL)
+	err = s.tokenRepo.CreateRefreshToken(&entity.RefreshToken{
+		AdminID:   admin.ID,
+		FamilyID:  sessionID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: refreshExpiresAt,
+		IP:        visitor.IP,
+		UserAgent: visitor.UserAgent,
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	user := adminResponse(admin)
 	return &dto.LoginResponse{
-		Token:     tokenString,
-		ExpiresAt: expiresAt.Unix(),
-		User: dto.AdminResponse{
-			ID:       admin.ID.String(),
-			Username: admin.Username,
-			Email:    email,
-		},
+		Token:            tokenString,
+		ExpiresAt:        expiresAt.Unix(),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt.Unix(),
+		User:             &user,
 	}, nil
 }
 
-func (s *authService) ValidateToken(tokenString string) (*uuid.UUID, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+// ValidateToken checks the signature and expiry of an access token, and
+// that neither the token nor its session has been revoked
+func (s *authService) ValidateToken(tokenString string) (*AccessToken, error) {
+	token, err := s.keys.parse(tokenString, &Claims{})
 
 	if err != nil {
 		return nil, errors.New("invalid token")
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		adminID, err := uuid.Parse(claims.AdminID)
-		if err != nil {
-			return nil, errors.New("invalid token claims")
-		}
-		return &adminID, nil
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, errors.New("invalid token")
+	}
+
+	adminID, err := uuid.Parse(claims.AdminID)
+	if err != nil {
+		return nil, errors.New("invalid token claims")
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil, errors.New("invalid token claims")
+	}
+
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(claims.ID)
+	if err != nil || revoked {
+		return nil, errors.New("token has been revoked")
+	}
+	revoked, err = s.tokenRepo.IsFamilyRevoked(sessionID)
+	if err != nil || revoked {
+		return nil, errors.New("token has been revoked")
 	}
 
-	return nil, errors.New("invalid token")
+	return &AccessToken{
+		ID:        claims.ID,
+		AdminID:   adminID,
+		SessionID: sessionID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
 }
 
 func (s *authService) GetAdminByID(id uuid.UUID) (*dto.AdminResponse, error) {
@@ -104,19 +512,54 @@ func (s *authService) GetAdminByID(id uuid.UUID) (*dto.AdminResponse, error) {
 		return nil, err
 	}
 
+	response := adminResponse(admin)
+	return &response, nil
+}
+
+func adminResponse(admin *entity.Admin) dto.AdminResponse {
 	email := ""
 	if admin.Email != nil {
 		email = *admin.Email
 	}
 
-	return &dto.AdminResponse{
-		ID:       admin.ID.String(),
-		Username: admin.Username,
-		Email:    email,
+	return dto.AdminResponse{
+		ID:               admin.ID.String(),
+		Username:         admin.Username,
+		Email:            email,
+		Role:             admin.Role,
+		TwoFactorEnabled: admin.TOTPEnabled,
+	}
+}
+
+// GetCurrentUser returns the admin with their most recent login attempts
+func (s *authService) GetCurrentUser(id uuid.UUID) (*dto.CurrentUserResponse, error) {
+	admin, err := s.GetAdminByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	events, _, err := s.eventRepo.FindByAdmin(id, 1, recentLoginCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CurrentUserResponse{
+		AdminResponse: *admin,
+		RecentLogins:  loginEventResponses(events),
 	}, nil
 }
 
-// SetJWTSecret allows setting the JWT secret from config
-func SetJWTSecret(secret string) {
-	jwtSecret = []byte(secret)
+// newRefreshToken returns 32 random bytes, base64url encoded
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is how refresh tokens are stored and looked up
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"backend/pkg/feed"
+	"backend/pkg/markdown"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Feed formats
+const (
+	FeedRSS  = "rss"
+	FeedAtom = "atom"
+	FeedJSON = "json"
+)
+
+// maxCachedFeeds bounds the render cache, which has an entry per format,
+// variant and tag
+const maxCachedFeeds = 256
+
+var ErrFeedTagNotFound = errors.New("tag not found")
+
+// FeedRequest selects the feed to render
+type FeedRequest struct {
+	Format      string
+	Tag         string // Tag slug, empty for all posts
+	ExcerptOnly bool
+	SiteURL     string
+	FeedPath    string // Path of the feed itself, e.g. "/feed.xml"
+	Uncached    bool   // Skip the render cache, for a SiteURL taken from the request
+}
+
+// FeedDocument is a rendered feed with its validators for conditional GETs
+type FeedDocument struct {
+	Body         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// FeedService renders the latest published posts as RSS, Atom or JSON Feed
+type FeedService interface {
+	GetFeed(req FeedRequest) (*FeedDocument, error)
+}
+
+type feedService struct {
+	cfg      config.FeedConfig
+	postRepo repository.PostRepository
+	tagRepo  repository.TagRepository
+
+	// Rendered feeds by request, reused while the posts are unchanged
+	mu    sync.Mutex
+	cache map[FeedRequest]*FeedDocument
+}
+
+func NewFeedService(cfg config.FeedConfig, postRepo repository.PostRepository, tagRepo repository.TagRepository) FeedService {
+	if cfg.Limit <= 0 {
+		cfg.Limit = 20
+	}
+
+	return &feedService{
+		cfg:      cfg,
+		postRepo: postRepo,
+		tagRepo:  tagRepo,
+		cache:    make(map[FeedRequest]*FeedDocument),
+	}
+}
+
+func (s *feedService) GetFeed(req FeedRequest) (*FeedDocument, error) {
+	title := s.cfg.Title
+	if req.Tag != "" {
+		tag, err := s.tagRepo.FindBySlug(req.Tag)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrFeedTagNotFound
+			}
+			return nil, err
+		}
+		title = fmt.Sprintf("%s - %s", s.cfg.Title, tag.Name)
+	}
+
+	posts, _, err := s.postRepo.FindAll(1, s.cfg.Limit, req.Tag, "", "published")
+	if err != nil {
+		return nil, err
+	}
+
+	etag, lastModified := feedValidators(req, title, posts)
+
+	if !req.Uncached {
+		s.mu.Lock()
+		cached, ok := s.cache[req]
+		s.mu.Unlock()
+		if ok && cached.ETag == etag {
+			return cached, nil
+		}
+	}
+
+	body, contentType, err := s.render(req, title, lastModified, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &FeedDocument{
+		Body:         body,
+		ContentType:  contentType,
+		ETag:         etag,
+		LastModified: lastModified,
+	}
+
+	if !req.Uncached {
+		s.mu.Lock()
+		if len(s.cache) >= maxCachedFeeds {
+			s.cache = make(map[FeedRequest]*FeedDocument)
+		}
+		s.cache[req] = doc
+		s.mu.Unlock()
+	}
+
+	return doc, nil
+}
+
+func (s *feedService) render(req FeedRequest, title string, updated time.Time, posts []entity.BlogPost) ([]byte, string, error) {
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	f := &feed.Feed{
+		Title:       title,
+		Link:        homeURL(req.SiteURL),
+		FeedURL:     strings.TrimSuffix(req.SiteURL, "/") + req.FeedPath,
+		Description: s.cfg.Description,
+		Language:    s.cfg.Language,
+		Updated:     updated,
+		Items:       make([]feed.Item, len(posts)),
+	}
+
+	for i, post := range posts {
+		item := feed.Item{
+			ID:        post.ID.String(),
+			Title:     post.Title,
+			Link:      postURL(req.SiteURL, post.Slug),
+			Summary:   post.Excerpt,
+			Published: post.PublishedDate,
+			Updated:   post.UpdatedAt,
+		}
+		if item.Published.IsZero() {
+			item.Published = post.CreatedAt
+		}
+		for _, tag := range post.Tags {
+			item.Categories = append(item.Categories, tag.Name)
+		}
+
+		if !req.ExcerptOnly {
+			html, err := markdown.ToHTML(post.Content)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to render post %s: %w", post.ID, err)
+			}
+			item.ContentHTML = html
+		}
+
+		f.Items[i] = item
+	}
+
+	switch req.Format {
+	case FeedAtom:
+		body, err := f.Atom()
+		return body, "application/atom+xml; charset=utf-8", err
+	case FeedJSON:
+		body, err := f.JSON()
+		return body, "application/feed+json; charset=utf-8", err
+	default:
+		body, err := f.RSS()
+		return body, "application/rss+xml; charset=utf-8", err
+	}
+}
+
+// feedValidators derives the ETag and Last-Modified time of a feed from the
+// posts it contains, so unchanged feeds can be answered without rendering.
+func feedValidators(req FeedRequest, title string, posts []entity.BlogPost) (string, time.Time) {
+	var lastModified time.Time
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%+v|%s\n", req, title)
+	for _, post := range posts {
+		fmt.Fprintf(h, "%s|%d", post.ID, post.UpdatedAt.UnixNano())
+		for _, tag := range post.Tags {
+			fmt.Fprintf(h, "|%s", tag.Name)
+		}
+		h.Write([]byte("\n"))
+
+		if post.UpdatedAt.After(lastModified) {
+			lastModified = post.UpdatedAt
+		}
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`, lastModified
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// retiredKeyGracePeriod is how long a rotated key is still served, so
+// engines can verify URLs that were pushed with it
+const retiredKeyGracePeriod = 7 * 24 * time.Hour
+
+var (
+	ErrIndexNowDisabled    = errors.New("IndexNow is not configured")
+	ErrIndexNowKeyNotFound = errors.New("IndexNow key not found")
+
+	// IndexNow keys are 8 to 128 characters of a-z, A-Z, 0-9 and dashes
+	indexNowKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9-]{8,128}$`)
+)
+
+// IndexNowService manages the key that proves site ownership to IndexNow.
+// Keys are stored in the database and served by the backend at /{key}.txt.
+// The first key is SEO_BING_API_KEY, or a generated one when it is not set.
+type IndexNowService interface {
+	Enabled() bool
+	ActiveKey() (string, error)
+	KeyLocation(key string) string
+	GetKeyFile(key string) (string, error)
+	RotateKey() (*dto.IndexNowKeyResponse, error)
+	Check() (*dto.IndexNowCheckResponse, error)
+}
+
+type indexNowService struct {
+	cfg     config.SEOConfig
+	seoRepo repository.SEORepository
+	client  *http.Client
+}
+
+func NewIndexNowService(cfg config.SEOConfig, seoRepo repository.SEORepository) IndexNowService {
+	return &indexNowService{
+		cfg:     cfg,
+		seoRepo: seoRepo,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (s *indexNowService) Enabled() bool {
+	return s.cfg.SiteURL != "" && (s.cfg.IndexNow.Key != "" || s.cfg.IndexNow.Enabled)
+}
+
+// ActiveKey returns the key used for pushes, creating the first one if needed
+func (s *indexNowService) ActiveKey() (string, error) {
+	if !s.Enabled() {
+		return "", ErrIndexNowDisabled
+	}
+
+	key, err := s.seoRepo.FindActiveIndexNowKey()
+	if err == nil {
+		return key.Key, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+
+	initial := s.cfg.IndexNow.Key
+	if initial == "" || !indexNowKeyPattern.MatchString(initial) {
+		if initial, err = generateIndexNowKey(); err != nil {
+			return "", err
+		}
+	}
+	if err := s.seoRepo.RotateIndexNowKey(&entity.IndexNowKey{Key: initial}); err != nil {
+		// Another request may have created the first key meanwhile
+		if key, ferr := s.seoRepo.FindActiveIndexNowKey(); ferr == nil {
+			return key.Key, nil
+		}
+		return "", err
+	}
+	return initial, nil
+}
+
+func (s *indexNowService) KeyLocation(key string) string {
+	return fmt.Sprintf("%s/%s.txt", strings.TrimSuffix(s.cfg.SiteURL, "/"), key)
+}
+
+// GetKeyFile returns the content of /{key}.txt for the active key and for
+// keys retired within the grace period
+func (s *indexNowService) GetKeyFile(key string) (string, error) {
+	if !s.Enabled() || !indexNowKeyPattern.MatchString(key) {
+		return "", ErrIndexNowKeyNotFound
+	}
+
+	k, err := s.seoRepo.FindIndexNowKey(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrIndexNowKeyNotFound
+		}
+		return "", err
+	}
+
+	if !k.IsActive && (k.RetiredAt == nil || time.Since(*k.RetiredAt) > retiredKeyGracePeriod) {
+		return "", ErrIndexNowKeyNotFound
+	}
+	return k.Key, nil
+}
+
+// RotateKey replaces the active key with a newly generated one
+func (s *indexNowService) RotateKey() (*dto.IndexNowKeyResponse, error) {
+	if !s.Enabled() {
+		return nil, ErrIndexNowDisabled
+	}
+
+	value, err := generateIndexNowKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &entity.IndexNowKey{Key: value}
+	if err := s.seoRepo.RotateIndexNowKey(key); err != nil {
+		return nil, err
+	}
+
+	return &dto.IndexNowKeyResponse{
+		Key:         key.Key,
+		KeyLocation: s.KeyLocation(key.Key),
+		CreatedAt:   key.CreatedAt,
+	}, nil
+}
+
+// Check fetches the key file through the public site URL, the way search
+// engines will, and reports anything that would make verification fail
+func (s *indexNowService) Check() (*dto.IndexNowCheckResponse, error) {
+	key, err := s.ActiveKey()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.IndexNowCheckResponse{
+		Key:         key,
+		KeyLocation: s.KeyLocation(key),
+		Problems:    []string{},
+	}
+
+	site, err := url.Parse(s.cfg.SiteURL)
+	if err != nil || site.Host == "" {
+		response.Problems = append(response.Problems, "SEO_SITE_URL is not a valid URL")
+		return response, nil
+	}
+
+	resp, err := s.client.Get(response.KeyLocation)
+	if err != nil {
+		response.Problems = append(response.Problems, fmt.Sprintf("Key file could not be fetched: %v", err))
+		return response, nil
+	}
+	defer resp.Body.Close()
+
+	response.StatusCode = resp.StatusCode
+	response.ContentType = resp.Header.Get("Content-Type")
+
+	if resp.StatusCode != http.StatusOK {
+		response.Problems = append(response.Problems, fmt.Sprintf("Key file returned HTTP %d", resp.StatusCode))
+	}
+	if resp.Request.URL.Host != site.Host {
+		response.Problems = append(response.Problems, fmt.Sprintf("Key file was redirected to another host: %s", resp.Request.URL.Host))
+	}
+	if !strings.HasPrefix(response.ContentType, "text/plain") {
+		response.Problems = append(response.Problems, "Key file is not served as text/plain")
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	response.KeyMatches = strings.TrimSpace(string(body)) == key
+	if !response.KeyMatches {
+		response.Problems = append(response.Problems, "Key file content does not match the active key")
+	}
+
+	response.OK = len(response.Problems) == 0
+	return response, nil
+}
+
+func generateIndexNowKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
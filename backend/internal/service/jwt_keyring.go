@@ -0,0 +1,44 @@
+package service
+
+import (
+	"backend/config"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtKeyring signs tokens with the newest key and validates them with any
+// configured key, found by the kid header. Rotating means adding a key;
+// tokens of a key stop validating once it is removed from the config.
+type jwtKeyring struct {
+	keys    map[string][]byte
+	current string
+}
+
+// newJWTKeyring expects at least one key, config.Load guarantees it
+func newJWTKeyring(cfg config.JWTConfig) *jwtKeyring {
+	k := &jwtKeyring{keys: make(map[string][]byte, len(cfg.Keys))}
+	for _, key := range cfg.Keys {
+		k.keys[key.ID] = []byte(key.Secret)
+	}
+	k.current = cfg.Keys[len(cfg.Keys)-1].ID
+	return k
+}
+
+func (k *jwtKeyring) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = k.current
+	return token.SignedString(k.keys[k.current])
+}
+
+func (k *jwtKeyring) parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	opts = append(opts, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := k.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
+	}, opts...)
+}
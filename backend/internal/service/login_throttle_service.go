@@ -0,0 +1,263 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTooManyAttempts  = errors.New("too many failed login attempts")
+	ErrLockoutNotFound  = errors.New("no lockout for this username or IP")
+	ErrInvalidLockScope = errors.New("scope must be username or ip")
+)
+
+// maxLockout caps the lockout, which doubles with every failure past the
+// threshold
+const maxLockout = 24 * time.Hour
+
+// LoginThrottledError is returned while a username or IP has to wait before
+// the next login attempt. It matches ErrTooManyAttempts with errors.Is.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+type LoginThrottleService interface {
+	Check(username, ip string) error
+	RecordFailure(username string, visitor Visitor)
+	RecordSuccess(username string)
+	ListLocked() ([]dto.LoginLockoutResponse, error)
+	Unlock(req dto.UnlockLoginRequest, token *AccessToken, visitor Visitor) error
+	PurgeStale() (int64, error)
+}
+
+type loginThrottleService struct {
+	throttleRepo  repository.LoginThrottleRepository
+	adminRepo     repository.AdminRepository
+	eventRepo     repository.AuthEventRepository
+	maxFailures   int
+	ipMaxFailures int
+	lockout       time.Duration
+	window        time.Duration
+}
+
+func NewLoginThrottleService(throttleRepo repository.LoginThrottleRepository, adminRepo repository.AdminRepository, eventRepo repository.AuthEventRepository, cfg config.LoginConfig) LoginThrottleService {
+	lockout, err := time.ParseDuration(cfg.LockoutDuration)
+	if err != nil || lockout <= 0 {
+		lockout = 15 * time.Minute
+	}
+
+	window, err := time.ParseDuration(cfg.FailureWindow)
+	if err != nil || window <= 0 {
+		window = time.Hour
+	}
+
+	maxFailures := cfg.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = 5
+	}
+	ipMaxFailures := cfg.IPMaxFailures
+	if ipMaxFailures <= 0 {
+		ipMaxFailures = 20
+	}
+
+	return &loginThrottleService{
+		throttleRepo:  throttleRepo,
+		adminRepo:     adminRepo,
+		eventRepo:     eventRepo,
+		maxFailures:   maxFailures,
+		ipMaxFailures: ipMaxFailures,
+		lockout:       lockout,
+		window:        window,
+	}
+}
+
+// Check refuses a login attempt while the username or the IP waits out a
+// backoff or a lockout. Unknown usernames are throttled the same way, so
+// the response does not tell whether a username exists.
+func (s *loginThrottleService) Check(username, ip string) error {
+	now := time.Now()
+	var wait time.Duration
+	for _, key := range throttleKeys(username, ip) {
+		throttle, err := s.throttleRepo.Find(key.scope, key.value)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			wait = max(wait, throttle.LockedUntil.Sub(now))
+		}
+	}
+
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt against the username and the IP.
+// Each failure doubles the wait before the next attempt, starting at one
+// second; from the threshold on it is a lockout, which is logged.
+func (s *loginThrottleService) RecordFailure(username string, visitor Visitor) {
+	now := time.Now()
+	for _, key := range throttleKeys(username, visitor.IP) {
+		throttle, err := s.throttleRepo.RecordFailure(key.scope, key.value, now, now.Add(-s.window))
+		if err != nil {
+			log.Printf("Failed to record login failure of %s %q: %v", key.scope, key.value, err)
+			continue
+		}
+
+		threshold := s.maxFailures
+		if key.scope == entity.ThrottleScopeIP {
+			threshold = s.ipMaxFailures
+		}
+
+		wait := backoff(throttle.Failures, threshold, s.lockout)
+		if err := s.throttleRepo.Lock(key.scope, key.value, now.Add(wait)); err != nil {
+			log.Printf("Failed to lock %s %q: %v", key.scope, key.value, err)
+			continue
+		}
+
+		if throttle.Failures >= threshold {
+			log.Printf("Login locked for %s %q after %d failures, for %s", key.scope, key.value, throttle.Failures, wait)
+			s.recordEvent(entity.AuthEventLockout, s.adminID(username), username, key.scope, visitor)
+		}
+	}
+}
+
+// RecordSuccess clears the failures of the username. The IP keeps its
+// count, so one valid account cannot be used to reset it.
+func (s *loginThrottleService) RecordSuccess(username string) {
+	if _, err := s.throttleRepo.Reset(entity.ThrottleScopeUsername, throttleUsername(username)); err != nil {
+		log.Printf("Failed to reset login failures of %q: %v", username, err)
+	}
+}
+
+func (s *loginThrottleService) ListLocked() ([]dto.LoginLockoutResponse, error) {
+	throttles, err := s.throttleRepo.FindLocked(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.LoginLockoutResponse, len(throttles))
+	for i, t := range throttles {
+		response[i] = dto.LoginLockoutResponse{
+			Scope:         t.Scope,
+			Value:         t.Value,
+			Failures:      t.Failures,
+			LastFailureAt: t.LastFailureAt,
+			LockedUntil:   *t.LockedUntil,
+		}
+	}
+	return response, nil
+}
+
+// Unlock lifts the lockout of a username or IP and resets its failures
+func (s *loginThrottleService) Unlock(req dto.UnlockLoginRequest, token *AccessToken, visitor Visitor) error {
+	value := strings.TrimSpace(req.Value)
+	switch req.Scope {
+	case entity.ThrottleScopeUsername:
+		value = throttleUsername(value)
+	case entity.ThrottleScopeIP:
+	default:
+		return ErrInvalidLockScope
+	}
+
+	found, err := s.throttleRepo.Reset(req.Scope, value)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrLockoutNotFound
+	}
+
+	log.Printf("Login unlocked for %s %q by admin %s", req.Scope, value, token.AdminID)
+	// The event names what was unlocked; AdminID is who unlocked it
+	username := ""
+	if req.Scope == entity.ThrottleScopeUsername {
+		username = value
+	} else {
+		visitor.IP = value
+	}
+	s.recordEvent(entity.AuthEventUnlock, &token.AdminID, username, req.Scope, visitor)
+	return nil
+}
+
+// PurgeStale deletes counters whose failures and lockouts are over
+func (s *loginThrottleService) PurgeStale() (int64, error) {
+	return s.throttleRepo.PurgeStale(time.Now().Add(-s.window))
+}
+
+func (s *loginThrottleService) adminID(username string) *uuid.UUID {
+	admin, err := s.adminRepo.FindByUsername(username)
+	if err != nil {
+		return nil
+	}
+	return &admin.ID
+}
+
+func (s *loginThrottleService) recordEvent(eventType string, adminID *uuid.UUID, username, scope string, visitor Visitor) {
+	err := s.eventRepo.Create(&entity.AuthEvent{
+		AdminID:   adminID,
+		Username:  truncateRunes(username, 50),
+		Type:      eventType,
+		Reason:    scope,
+		IP:        visitor.IP,
+		UserAgent: visitor.UserAgent,
+	})
+	if err != nil {
+		log.Printf("Failed to record %s event: %v", eventType, err)
+	}
+}
+
+type throttleKey struct {
+	scope string
+	value string
+}
+
+// throttleKeys are the counters an attempt is checked and counted against
+func throttleKeys(username, ip string) []throttleKey {
+	keys := []throttleKey{{entity.ThrottleScopeUsername, throttleUsername(username)}}
+	if ip != "" {
+		keys = append(keys, throttleKey{entity.ThrottleScopeIP, ip})
+	}
+	return keys
+}
+
+// throttleUsername ignores case, so variants of a name share a counter
+func throttleUsername(username string) string {
+	return truncateRunes(strings.ToLower(strings.TrimSpace(username)), 100)
+}
+
+// backoff is the wait after the given number of failures: 1s, 2s, 4s, ...
+// below the threshold, then the lockout, doubling up to maxLockout
+func backoff(failures, threshold int, lockout time.Duration) time.Duration {
+	base, exp := time.Second, failures-1
+	if failures >= threshold {
+		base, exp = lockout, failures-threshold
+	}
+
+	wait := base
+	for i := 0; i < exp && wait < maxLockout; i++ {
+		wait *= 2
+	}
+	return min(wait, maxLockout)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"backend/pkg/ogimage"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxCachedOGImages bounds the image cache, one entry per post
+const maxCachedOGImages = 256
+
+var ErrOGImageNotFound = errors.New("post not found")
+
+// OGImage is a rendered share image with its validators for conditional GETs
+type OGImage struct {
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+}
+
+// OGImageService draws the Open Graph share images of published posts
+type OGImageService interface {
+	GetPostImage(postID uuid.UUID) (*OGImage, error)
+}
+
+type ogImageService struct {
+	cfg      config.FeedConfig
+	postRepo repository.PostRepository
+
+	// Rendered images by post, redrawn when the post changes
+	mu    sync.Mutex
+	cache map[uuid.UUID]*OGImage
+}
+
+func NewOGImageService(cfg config.FeedConfig, postRepo repository.PostRepository) OGImageService {
+	return &ogImageService{
+		cfg:      cfg,
+		postRepo: postRepo,
+		cache:    make(map[uuid.UUID]*OGImage),
+	}
+}
+
+func (s *ogImageService) GetPostImage(postID uuid.UUID) (*OGImage, error) {
+	post, err := s.postRepo.FindByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOGImageNotFound
+		}
+		return nil, err
+	}
+	if !post.IsPublished {
+		return nil, ErrOGImageNotFound
+	}
+
+	card := s.card(post)
+	etag := ogImageETag(card)
+
+	s.mu.Lock()
+	cached, ok := s.cache[post.ID]
+	s.mu.Unlock()
+	if ok && cached.ETag == etag {
+		return cached, nil
+	}
+
+	body, err := ogimage.Render(card)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render share image: %w", err)
+	}
+
+	image := &OGImage{
+		Body:         body,
+		ETag:         etag,
+		LastModified: post.UpdatedAt,
+	}
+
+	s.mu.Lock()
+	if len(s.cache) >= maxCachedOGImages {
+		s.cache = make(map[uuid.UUID]*OGImage)
+	}
+	s.cache[post.ID] = image
+	s.mu.Unlock()
+
+	return image, nil
+}
+
+func (s *ogImageService) card(post *entity.BlogPost) ogimage.Card {
+	tags := make([]string, len(post.Tags))
+	for i, tag := range post.Tags {
+		tags[i] = tag.Name
+	}
+
+	return ogimage.Card{
+		Title:    post.Title,
+		Tags:     tags,
+		ReadTime: post.ReadTime,
+		Date:     post.PublishedDate.Format("2006-01-02"),
+		SiteName: s.cfg.Title,
+		Command:  fmt.Sprintf("cat posts/%s.md", post.Slug),
+	}
+}
+
+// ogImageETag identifies an image by everything drawn on it
+func ogImageETag(card ogimage.Card) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q", card)
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
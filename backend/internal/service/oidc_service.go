@@ -0,0 +1,508 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOIDCProviderNotFound    = errors.New("unknown sign-in provider")
+	ErrOIDCProviderUnavailable = errors.New("sign-in provider is unavailable")
+	ErrInvalidOIDCState        = errors.New("sign-in expired or was started elsewhere, please try again")
+	ErrOIDCLoginFailed         = errors.New("sign-in with the provider failed")
+	ErrOIDCNoAccount           = errors.New("no admin account is linked to this identity")
+	ErrOIDCLinkRequired        = errors.New("sign in with your password and link this identity from your account first")
+	ErrOIDCIdentityLinked      = errors.New("this identity is already linked to an admin account")
+)
+
+const (
+	// oidcStateTTL is how long the user may take at the provider
+	oidcStateTTL = 10 * time.Minute
+	// oidcStateAudience keeps state tokens apart from access tokens
+	oidcStateAudience = "devlog-oidc"
+	// oidcHTTPTimeout bounds discovery, key and token requests
+	oidcHTTPTimeout = 10 * time.Second
+	// oidcRetryInterval is how long a failed discovery is remembered
+	oidcRetryInterval = time.Minute
+)
+
+type OIDCService interface {
+	Providers() []dto.OIDCProviderResponse
+	Start(provider string) (*dto.OIDCStartResponse, error)
+	Callback(req dto.OIDCCallbackRequest, visitor Visitor) (*dto.LoginResponse, error)
+	StartLink(provider string, adminID uuid.UUID) (*dto.OIDCStartResponse, error)
+	Link(req dto.OIDCCallbackRequest, adminID uuid.UUID) (*dto.OIDCIdentityResponse, error)
+}
+
+// oidcStateClaims travel through the browser between Start and Callback.
+// State is also sent to the provider, which returns it with the code, so a
+// code only completes the login in the browser that started it. The PKCE
+// verifier is needed to redeem the code. LinkAdmin is set when a signed-in
+// admin links the identity to their account instead of logging in.
+type oidcStateClaims struct {
+	Provider  string `json:"provider"`
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"`
+	LinkAdmin string `json:"link_admin,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// oidcProvider is a configured provider, discovered on first use so the
+// server starts while the provider is down
+type oidcProvider struct {
+	cfg config.OIDCProviderConfig
+
+	mu          sync.Mutex
+	oauth       *oauth2.Config
+	verifier    *oidc.IDTokenVerifier
+	failedAt    time.Time
+	discoverErr error
+}
+
+type oidcService struct {
+	adminRepo    repository.AdminRepository
+	identityRepo repository.AdminIdentityRepository
+	authService  AuthService
+	keys         *jwtKeyring
+	redirectURL  string
+	providers    map[string]*oidcProvider
+	order        []string
+	httpClient   *http.Client
+}
+
+func NewOIDCService(adminRepo repository.AdminRepository, identityRepo repository.AdminIdentityRepository, authService AuthService, oidcCfg config.OIDCConfig, jwtCfg config.JWTConfig) OIDCService {
+	s := &oidcService{
+		adminRepo:    adminRepo,
+		identityRepo: identityRepo,
+		authService:  authService,
+		keys:         newJWTKeyring(jwtCfg),
+		redirectURL:  oidcCfg.RedirectURL,
+		providers:    make(map[string]*oidcProvider, len(oidcCfg.Providers)),
+		httpClient:   &http.Client{Timeout: oidcHTTPTimeout},
+	}
+	for _, cfg := range oidcCfg.Providers {
+		if _, ok := rolePermissions[cfg.DefaultRole]; !ok {
+			log.Printf("OIDC provider %q: unknown default role %q, using %q", cfg.Name, cfg.DefaultRole, entity.RoleAuthor)
+			cfg.DefaultRole = entity.RoleAuthor
+		}
+		s.providers[cfg.Name] = &oidcProvider{cfg: cfg}
+		s.order = append(s.order, cfg.Name)
+	}
+	return s
+}
+
+// Providers lists the configured providers for the login page
+func (s *oidcService) Providers() []dto.OIDCProviderResponse {
+	providers := make([]dto.OIDCProviderResponse, 0, len(s.order))
+	for _, name := range s.order {
+		providers = append(providers, dto.OIDCProviderResponse{
+			Name:        name,
+			DisplayName: s.providers[name].cfg.DisplayName,
+		})
+	}
+	return providers
+}
+
+// Start returns the provider's authorization URL, using the authorization
+// code flow with PKCE, and the state token to present on return
+func (s *oidcService) Start(name string) (*dto.OIDCStartResponse, error) {
+	return s.start(name, "")
+}
+
+// StartLink starts the same flow to link an identity to the signed-in admin
+func (s *oidcService) StartLink(name string, adminID uuid.UUID) (*dto.OIDCStartResponse, error) {
+	return s.start(name, adminID.String())
+}
+
+func (s *oidcService) start(name, linkAdmin string) (*dto.OIDCStartResponse, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+	oauthCfg, _, err := s.discover(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	now := time.Now()
+	expiresAt := now.Add(oidcStateTTL)
+	stateToken, err := s.keys.sign(&oidcStateClaims{
+		Provider:  name,
+		State:     state,
+		Nonce:     nonce,
+		Verifier:  verifier,
+		LinkAdmin: linkAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{oidcStateAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "devlog",
+		},
+	})
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &dto.OIDCStartResponse{
+		AuthorizationURL: oauthCfg.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)),
+		StateToken:       stateToken,
+		ExpiresAt:        expiresAt.Unix(),
+	}, nil
+}
+
+// Callback redeems the authorization code, verifies the ID token and starts
+// a session for the linked admin. Two-factor authentication is left to the
+// provider.
+func (s *oidcService) Callback(req dto.OIDCCallbackRequest, visitor Visitor) (*dto.LoginResponse, error) {
+	result, err := s.verify(req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	admin, err := s.resolveAdmin(result.provider.cfg, result.issuer, result.subject, &result.profile)
+	if err != nil {
+		return nil, err
+	}
+	return s.authService.LoginExternal(admin, visitor)
+}
+
+// Link completes StartLink, linking the identity to the admin who started
+// it. This is how admins with two-factor authentication or the owner role,
+// who are never linked by email, add an identity.
+func (s *oidcService) Link(req dto.OIDCCallbackRequest, adminID uuid.UUID) (*dto.OIDCIdentityResponse, error) {
+	result, err := s.verify(req, adminID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.identityRepo.Find(result.issuer, result.subject)
+	if err == nil {
+		if identity.AdminID != adminID {
+			return nil, ErrOIDCIdentityLinked
+		}
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		now := time.Now()
+		identity = &entity.AdminIdentity{
+			AdminID:     adminID,
+			Provider:    result.provider.cfg.Name,
+			Issuer:      result.issuer,
+			Subject:     result.subject,
+			Email:       result.profile.verifiedEmail(),
+			LastLoginAt: &now,
+		}
+		if err := s.identityRepo.Create(identity); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, err
+	}
+
+	return &dto.OIDCIdentityResponse{
+		Provider: identity.Provider,
+		Email:    identity.Email,
+	}, nil
+}
+
+// oidcResult is a verified identity returned by a provider
+type oidcResult struct {
+	provider *oidcProvider
+	issuer   string
+	subject  string
+	profile  oidcProfile
+}
+
+// verify checks the state of a callback, which must have been started for
+// linkAdmin or, when empty, for a login, redeems the code and verifies the
+// ID token
+func (s *oidcService) verify(req dto.OIDCCallbackRequest, linkAdmin string) (*oidcResult, error) {
+	token, err := s.keys.parse(req.StateToken, &oidcStateClaims{}, jwt.WithAudience(oidcStateAudience))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidOIDCState
+	}
+	claims, ok := token.Claims.(*oidcStateClaims)
+	if !ok || subtle.ConstantTimeCompare([]byte(claims.State), []byte(req.State)) != 1 || claims.LinkAdmin != linkAdmin {
+		return nil, ErrInvalidOIDCState
+	}
+	provider, ok := s.providers[claims.Provider]
+	if !ok {
+		return nil, ErrInvalidOIDCState
+	}
+	oauthCfg, verifier, err := s.discover(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(oidc.ClientContext(context.Background(), s.httpClient), oidcHTTPTimeout)
+	defer cancel()
+
+	oauthToken, err := oauthCfg.Exchange(ctx, req.Code, oauth2.VerifierOption(claims.Verifier))
+	if err != nil {
+		log.Printf("OIDC provider %q: code exchange failed: %v", provider.cfg.Name, err)
+		return nil, ErrOIDCLoginFailed
+	}
+	rawIDToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok {
+		log.Printf("OIDC provider %q: token response has no id_token", provider.cfg.Name)
+		return nil, ErrOIDCLoginFailed
+	}
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		log.Printf("OIDC provider %q: invalid ID token: %v", provider.cfg.Name, err)
+		return nil, ErrOIDCLoginFailed
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(claims.Nonce)) != 1 {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	result := &oidcResult{provider: provider, issuer: idToken.Issuer, subject: idToken.Subject}
+	if err := idToken.Claims(&result.profile); err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+	return result, nil
+}
+
+// oidcProfile holds the standard claims used to find or create an admin
+type oidcProfile struct {
+	Email             string      `json:"email"`
+	EmailVerified     interface{} `json:"email_verified"` // Some providers send "true"
+	PreferredUsername string      `json:"preferred_username"`
+	Name              string      `json:"name"`
+}
+
+func (p *oidcProfile) verifiedEmail() string {
+	if p.EmailVerified == true || p.EmailVerified == "true" {
+		return strings.TrimSpace(p.Email)
+	}
+	return ""
+}
+
+// resolveAdmin finds the admin linked to the subject. Without a link it
+// links the admin with the same verified email, or creates an admin, when
+// the provider allows it.
+func (s *oidcService) resolveAdmin(cfg config.OIDCProviderConfig, issuer, subject string, profile *oidcProfile) (*entity.Admin, error) {
+	email := profile.verifiedEmail()
+
+	identity, err := s.identityRepo.Find(issuer, subject)
+	if err == nil {
+		admin, err := s.adminRepo.FindByID(identity.AdminID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrOIDCNoAccount
+			}
+			return nil, err
+		}
+		if err := s.identityRepo.MarkLogin(identity.ID, email); err != nil {
+			log.Printf("Failed to update identity %d: %v", identity.ID, err)
+		}
+		return admin, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Admins protected by two-factor authentication, and the owner, are not
+	// linked by email, as that would let the provider bypass their second
+	// factor. They link the identity from their account instead.
+	var admin *entity.Admin
+	if cfg.LinkByEmail && email != "" && emailDomainAllowed(email, cfg.AllowedDomains) {
+		admins, err := s.adminRepo.FindAllByEmail(email)
+		if err != nil {
+			return nil, err
+		}
+		if len(admins) > 1 {
+			log.Printf("OIDC provider %q: not linking subject %q, several admins have the email %s", cfg.Name, subject, email)
+			return nil, ErrOIDCLinkRequired
+		}
+		if len(admins) == 1 {
+			admin = &admins[0]
+		}
+		if admin != nil && (admin.TOTPEnabled || admin.Role == entity.RoleOwner) {
+			log.Printf("OIDC provider %q: not linking subject %q to %s by email", cfg.Name, subject, admin.Username)
+			return nil, ErrOIDCLinkRequired
+		}
+	}
+	if admin == nil {
+		if !cfg.AutoProvision || !emailDomainAllowed(email, cfg.AllowedDomains) {
+			log.Printf("OIDC provider %q: no admin for subject %q (%s)", cfg.Name, subject, profile.Email)
+			return nil, ErrOIDCNoAccount
+		}
+		if admin, err = s.provisionAdmin(cfg, subject, email, profile); err != nil {
+			return nil, err
+		}
+		log.Printf("OIDC provider %q: created %s %s for subject %q", cfg.Name, admin.Role, admin.Username, subject)
+	}
+
+	now := time.Now()
+	err = s.identityRepo.Create(&entity.AdminIdentity{
+		AdminID:     admin.ID,
+		Provider:    cfg.Name,
+		Issuer:      issuer,
+		Subject:     subject,
+		Email:       email,
+		LastLoginAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+// provisionAdmin creates an admin for a first login. It gets a random
+// password nobody knows, so it signs in through the provider only.
+func (s *oidcService) provisionAdmin(cfg config.OIDCProviderConfig, subject, email string, profile *oidcProfile) (*entity.Admin, error) {
+	username, err := s.availableUsername(profile.PreferredUsername, email, subject)
+	if err != nil {
+		return nil, err
+	}
+	password, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	admin := &entity.Admin{
+		Username: username,
+		Role:     cfg.DefaultRole,
+		IsActive: true,
+	}
+	if email, err := normalizeEmail(email); err == nil {
+		taken, err := s.adminRepo.EmailExists(email, uuid.Nil)
+		if err != nil {
+			return nil, err
+		}
+		if !taken {
+			admin.Email = &email
+		}
+	}
+	if err := setPassword(admin, password); err != nil {
+		return nil, err
+	}
+	if err := s.adminRepo.Create(admin); err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// availableUsername derives a free username from the preferred username,
+// the email or the subject, adding a number when it is taken
+func (s *oidcService) availableUsername(candidates ...string) (string, error) {
+	base := ""
+	for _, candidate := range candidates {
+		candidate, _, _ = strings.Cut(strings.ToLower(candidate), "@")
+		candidate = strings.Trim(usernameInvalidChars.ReplaceAllString(candidate, "-"), "-.")
+		if len(candidate) >= 3 {
+			base = truncateRunes(candidate, 40)
+			break
+		}
+	}
+	if base == "" {
+		base = "sso-user"
+	}
+
+	for i := 1; i <= 100; i++ {
+		username := base
+		if i > 1 {
+			username = fmt.Sprintf("%s-%d", base, i)
+		}
+		exists, err := s.adminRepo.UsernameExists(username)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return username, nil
+		}
+	}
+	return "", ErrUsernameTaken
+}
+
+func emailDomainAllowed(email string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	_, domain, ok := strings.Cut(strings.ToLower(email), "@")
+	if !ok {
+		return false
+	}
+	for _, allowed := range domains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// discover fetches the provider's discovery document once; after a failure
+// it is retried at most every oidcRetryInterval
+func (s *oidcService) discover(p *oidcProvider) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+	if time.Since(p.failedAt) < oidcRetryInterval {
+		return nil, nil, p.discoverErr
+	}
+
+	// The key set keeps using this context to refresh keys, so it has no
+	// deadline; the client bounds every request
+	ctx := oidc.ClientContext(context.Background(), s.httpClient)
+	provider, err := oidc.NewProvider(ctx, p.cfg.Issuer)
+	if err != nil {
+		log.Printf("OIDC provider %q: discovery failed: %v", p.cfg.Name, err)
+		p.failedAt = time.Now()
+		p.discoverErr = fmt.Errorf("%w: %s", ErrOIDCProviderUnavailable, p.cfg.Name)
+		return nil, nil, p.discoverErr
+	}
+
+	p.oauth = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  s.redirectURL,
+		Scopes:       append([]string{oidc.ScopeOpenID}, p.cfg.Scopes...),
+	}
+	p.verifier = provider.VerifierContext(ctx, &oidc.Config{ClientID: p.cfg.ClientID})
+	return p.oauth, p.verifier, nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
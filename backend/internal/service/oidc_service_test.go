@@ -0,0 +1,569 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// mockOIDCProvider serves discovery, JWKS and token endpoints. Codes are
+// handed out by authorize, which stands in for the user signing in at the
+// provider.
+type mockOIDCProvider struct {
+	t      *testing.T
+	key    *rsa.PrivateKey
+	server *httptest.Server
+
+	mu            sync.Mutex
+	codes         map[string]mockAuthorization
+	tokenRequests int
+	verifiers     []string
+}
+
+// mockAuthorization is what the provider remembers about a code
+type mockAuthorization struct {
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+const mockOIDCClientID = "devlog"
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &mockOIDCProvider{t: t, key: key, codes: make(map[string]mockAuthorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.handleDiscovery)
+	mux.HandleFunc("/jwks", p.handleJWKS)
+	mux.HandleFunc("/token", p.handleToken)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *mockOIDCProvider) issuer() string {
+	return p.server.URL
+}
+
+func (p *mockOIDCProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"issuer":                                p.issuer(),
+		"authorization_endpoint":                p.issuer() + "/authorize",
+		"token_endpoint":                        p.issuer() + "/token",
+		"jwks_uri":                              p.issuer() + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *mockOIDCProvider) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "mock-key",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+// handleToken redeems a code once, when the PKCE verifier matches the
+// challenge sent with the authorization request
+func (p *mockOIDCProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientID, _, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.FormValue("client_id")
+	}
+	verifier := r.FormValue("code_verifier")
+
+	p.mu.Lock()
+	p.tokenRequests++
+	p.verifiers = append(p.verifiers, verifier)
+	auth, found := p.codes[r.FormValue("code")]
+	delete(p.codes, r.FormValue("code"))
+	p.mu.Unlock()
+
+	if r.FormValue("grant_type") != "authorization_code" || clientID != mockOIDCClientID || !found || auth.challenge != s256(verifier) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   p.issuer(),
+		"aud":   mockOIDCClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": auth.nonce,
+	}
+	for k, v := range auth.claims {
+		claims[k] = v
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = "mock-key"
+	signed, err := idToken.SignedString(p.key)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"access_token": "provider-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+// authorize signs the user in at the provider with the given claims and
+// returns the callback parameters. A "nonce" claim overrides the nonce of
+// the authorization request.
+func (p *mockOIDCProvider) authorize(authorizationURL string, claims jwt.MapClaims) (code, state string) {
+	u, err := url.Parse(authorizationURL)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	query := u.Query()
+	if u.Path != "/authorize" || query.Get("client_id") != mockOIDCClientID || query.Get("response_type") != "code" {
+		p.t.Fatalf("unexpected authorization URL %s", authorizationURL)
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		p.t.Fatalf("authorization URL %s has no PKCE challenge", authorizationURL)
+	}
+
+	auth := mockAuthorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce"), claims: claims}
+	if nonce, ok := claims["nonce"].(string); ok {
+		auth.nonce = nonce
+	}
+	code = uuid.NewString()
+	p.mu.Lock()
+	p.codes[code] = auth
+	p.mu.Unlock()
+	return code, query.Get("state")
+}
+
+func s256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// fakeAdminRepository keeps admins in memory
+type fakeAdminRepository struct {
+	repository.AdminRepository
+	admins []*entity.Admin
+}
+
+func (r *fakeAdminRepository) add(username, email, role string, totp bool) *entity.Admin {
+	admin := &entity.Admin{ID: uuid.New(), Username: username, Role: role, IsActive: true, TOTPEnabled: totp}
+	if email != "" {
+		admin.Email = &email
+	}
+	r.admins = append(r.admins, admin)
+	return admin
+}
+
+func (r *fakeAdminRepository) FindByID(id uuid.UUID) (*entity.Admin, error) {
+	for _, admin := range r.admins {
+		if admin.ID == id {
+			return admin, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAdminRepository) FindAllByEmail(email string) ([]entity.Admin, error) {
+	var admins []entity.Admin
+	for _, admin := range r.admins {
+		if admin.Email != nil && strings.EqualFold(*admin.Email, email) {
+			admins = append(admins, *admin)
+		}
+	}
+	return admins, nil
+}
+
+func (r *fakeAdminRepository) UsernameExists(username string) (bool, error) {
+	for _, admin := range r.admins {
+		if admin.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeAdminRepository) EmailExists(email string, excludeID uuid.UUID) (bool, error) {
+	admins, _ := r.FindAllByEmail(email)
+	for _, admin := range admins {
+		if admin.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeAdminRepository) Create(admin *entity.Admin) error {
+	admin.ID = uuid.New()
+	r.admins = append(r.admins, admin)
+	return nil
+}
+
+// fakeAdminIdentityRepository keeps identities in memory
+type fakeAdminIdentityRepository struct {
+	identities []*entity.AdminIdentity
+	logins     int
+}
+
+func (r *fakeAdminIdentityRepository) Find(issuer, subject string) (*entity.AdminIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAdminIdentityRepository) Create(identity *entity.AdminIdentity) error {
+	identity.ID = int64(len(r.identities) + 1)
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeAdminIdentityRepository) MarkLogin(id int64, email string) error {
+	r.logins++
+	return nil
+}
+
+// fakeExternalAuth records who was logged in
+type fakeExternalAuth struct {
+	AuthService
+	loggedIn []uuid.UUID
+}
+
+func (a *fakeExternalAuth) LoginExternal(admin *entity.Admin, visitor Visitor) (*dto.LoginResponse, error) {
+	a.loggedIn = append(a.loggedIn, admin.ID)
+	return &dto.LoginResponse{Token: "access-token", User: &dto.AdminResponse{Username: admin.Username}}, nil
+}
+
+type oidcTestEnv struct {
+	provider   *mockOIDCProvider
+	admins     *fakeAdminRepository
+	identities *fakeAdminIdentityRepository
+	auth       *fakeExternalAuth
+	service    OIDCService
+}
+
+func newOIDCTestEnv(t *testing.T, configure func(cfg *config.OIDCProviderConfig)) *oidcTestEnv {
+	env := &oidcTestEnv{
+		provider:   newMockOIDCProvider(t),
+		admins:     &fakeAdminRepository{},
+		identities: &fakeAdminIdentityRepository{},
+		auth:       &fakeExternalAuth{},
+	}
+	cfg := config.OIDCProviderConfig{
+		Name:         "mock",
+		DisplayName:  "Mock",
+		Issuer:       env.provider.issuer(),
+		ClientID:     mockOIDCClientID,
+		ClientSecret: "secret",
+		Scopes:       []string{"email", "profile"},
+		DefaultRole:  entity.RoleAuthor,
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+	env.service = NewOIDCService(env.admins, env.identities, env.auth,
+		config.OIDCConfig{RedirectURL: "https://blog.example.com/?view=sso", Providers: []config.OIDCProviderConfig{cfg}},
+		config.JWTConfig{Keys: []config.JWTKey{{ID: "k1", Secret: "test-secret"}}})
+	return env
+}
+
+// login runs a whole sign-in: start, the user at the provider, callback
+func (e *oidcTestEnv) login(t *testing.T, claims jwt.MapClaims) (*dto.LoginResponse, error) {
+	t.Helper()
+	start, err := e.service.Start("mock")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	code, state := e.provider.authorize(start.AuthorizationURL, claims)
+	return e.service.Callback(dto.OIDCCallbackRequest{Code: code, State: state, StateToken: start.StateToken}, Visitor{IP: "203.0.113.1"})
+}
+
+func identityClaims(subject, email string) jwt.MapClaims {
+	return jwt.MapClaims{"sub": subject, "email": email, "email_verified": true, "preferred_username": "jane"}
+}
+
+func TestOIDCLoginLinkedIdentity(t *testing.T) {
+	env := newOIDCTestEnv(t, nil)
+	admin := env.admins.add("jane", "", entity.RoleEditor, true)
+	env.identities.Create(&entity.AdminIdentity{AdminID: admin.ID, Provider: "mock", Issuer: env.provider.issuer(), Subject: "user-1"})
+
+	resp, err := env.login(t, identityClaims("user-1", "jane@example.com"))
+	if err != nil {
+		t.Fatalf("Callback() error = %v", err)
+	}
+	if resp.Token == "" || len(env.auth.loggedIn) != 1 || env.auth.loggedIn[0] != admin.ID {
+		t.Errorf("logged in %v, want %s", env.auth.loggedIn, admin.ID)
+	}
+	if env.identities.logins != 1 {
+		t.Errorf("identity logins = %d, want 1", env.identities.logins)
+	}
+}
+
+func TestOIDCStartUsesPKCE(t *testing.T) {
+	env := newOIDCTestEnv(t, nil)
+
+	start, err := env.service.Start("mock")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	u, _ := url.Parse(start.AuthorizationURL)
+	query := u.Query()
+	if query.Get("redirect_uri") != "https://blog.example.com/?view=sso" || query.Get("nonce") == "" || query.Get("state") == "" {
+		t.Errorf("authorization URL %s", start.AuthorizationURL)
+	}
+	if !strings.Contains(query.Get("scope"), "openid") {
+		t.Errorf("scope = %q, want openid", query.Get("scope"))
+	}
+
+	// The verifier travels in the state token only, and redeems the code
+	code, state := env.provider.authorize(start.AuthorizationURL, identityClaims("user-1", ""))
+	_, err = env.service.Callback(dto.OIDCCallbackRequest{Code: code, State: state, StateToken: start.StateToken}, Visitor{})
+	if !errors.Is(err, ErrOIDCNoAccount) {
+		t.Fatalf("Callback() error = %v, want ErrOIDCNoAccount", err)
+	}
+	if len(env.provider.verifiers) != 1 || s256(env.provider.verifiers[0]) != query.Get("code_challenge") {
+		t.Errorf("token request verifiers %v do not match the challenge", env.provider.verifiers)
+	}
+	if strings.Contains(start.AuthorizationURL, env.provider.verifiers[0]) {
+		t.Error("authorization URL contains the PKCE verifier")
+	}
+}
+
+func TestOIDCCallbackRequiresMatchingVerifier(t *testing.T) {
+	env := newOIDCTestEnv(t, nil)
+	admin := env.admins.add("jane", "", entity.RoleEditor, false)
+	env.identities.Create(&entity.AdminIdentity{AdminID: admin.ID, Provider: "mock", Issuer: env.provider.issuer(), Subject: "user-1"})
+
+	// A code authorized for one sign-in is presented with the state of
+	// another, whose verifier doesn't match the code's challenge
+	first, _ := env.service.Start("mock")
+	second, _ := env.service.Start("mock")
+	code, _ := env.provider.authorize(first.AuthorizationURL, identityClaims("user-1", ""))
+	u, _ := url.Parse(second.AuthorizationURL)
+
+	_, err := env.service.Callback(dto.OIDCCallbackRequest{Code: code, State: u.Query().Get("state"), StateToken: second.StateToken}, Visitor{})
+	if !errors.Is(err, ErrOIDCLoginFailed) {
+		t.Errorf("Callback() error = %v, want ErrOIDCLoginFailed", err)
+	}
+	if env.provider.tokenRequests == 0 || len(env.auth.loggedIn) != 0 {
+		t.Errorf("token requests = %d, logged in %v", env.provider.tokenRequests, env.auth.loggedIn)
+	}
+	challenge, _ := url.Parse(first.AuthorizationURL)
+	for _, verifier := range env.provider.verifiers {
+		if s256(verifier) == challenge.Query().Get("code_challenge") {
+			t.Errorf("code redeemed with the verifier of its own sign-in")
+		}
+	}
+}
+
+func TestOIDCCallbackStateMismatch(t *testing.T) {
+	env := newOIDCTestEnv(t, nil)
+	admin := env.admins.add("jane", "", entity.RoleEditor, false)
+	env.identities.Create(&entity.AdminIdentity{AdminID: admin.ID, Provider: "mock", Issuer: env.provider.issuer(), Subject: "user-1"})
+
+	start, _ := env.service.Start("mock")
+	code, state := env.provider.authorize(start.AuthorizationURL, identityClaims("user-1", ""))
+	other, _ := env.service.Start("mock")
+	link, _ := env.service.StartLink("mock", admin.ID)
+
+	tests := []struct {
+		name string
+		req  dto.OIDCCallbackRequest
+	}{
+		{"state from another sign-in", dto.OIDCCallbackRequest{Code: code, State: state, StateToken: other.StateToken}},
+		{"state not returned by the provider", dto.OIDCCallbackRequest{Code: code, State: "forged", StateToken: start.StateToken}},
+		{"tampered state token", dto.OIDCCallbackRequest{Code: code, State: state, StateToken: start.StateToken + "x"}},
+		{"state token of a link", dto.OIDCCallbackRequest{Code: code, State: state, StateToken: link.StateToken}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := env.service.Callback(tt.req, Visitor{}); !errors.Is(err, ErrInvalidOIDCState) {
+				t.Errorf("Callback() error = %v, want ErrInvalidOIDCState", err)
+			}
+		})
+	}
+	if env.provider.tokenRequests != 0 || len(env.auth.loggedIn) != 0 {
+		t.Errorf("token requests = %d, logged in %v, want the code never redeemed", env.provider.tokenRequests, env.auth.loggedIn)
+	}
+}
+
+func TestOIDCCallbackNonceMismatch(t *testing.T) {
+	env := newOIDCTestEnv(t, nil)
+	admin := env.admins.add("jane", "", entity.RoleEditor, false)
+	env.identities.Create(&entity.AdminIdentity{AdminID: admin.ID, Provider: "mock", Issuer: env.provider.issuer(), Subject: "user-1"})
+
+	claims := identityClaims("user-1", "")
+	claims["nonce"] = "replayed"
+	if _, err := env.login(t, claims); !errors.Is(err, ErrOIDCLoginFailed) {
+		t.Errorf("Callback() error = %v, want ErrOIDCLoginFailed", err)
+	}
+	if len(env.auth.loggedIn) != 0 {
+		t.Errorf("logged in %v", env.auth.loggedIn)
+	}
+}
+
+func TestOIDCLinkByEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		totp     bool
+		verified bool
+		want     error
+	}{
+		{"editor", entity.RoleEditor, false, true, nil},
+		{"unverified email", entity.RoleEditor, false, false, ErrOIDCNoAccount},
+		{"owner", entity.RoleOwner, false, true, ErrOIDCLinkRequired},
+		{"two-factor admin", entity.RoleEditor, true, true, ErrOIDCLinkRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t, func(cfg *config.OIDCProviderConfig) { cfg.LinkByEmail = true })
+			admin := env.admins.add("jane", "Jane@Example.com", tt.role, tt.totp)
+
+			claims := identityClaims("user-1", "jane@example.com")
+			claims["email_verified"] = tt.verified
+			_, err := env.login(t, claims)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Callback() error = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if len(env.identities.identities) != 0 || len(env.auth.loggedIn) != 0 {
+					t.Errorf("identities %v, logged in %v, want no link", env.identities.identities, env.auth.loggedIn)
+				}
+				return
+			}
+			if len(env.identities.identities) != 1 || env.identities.identities[0].AdminID != admin.ID {
+				t.Errorf("identities %v, want a link to %s", env.identities.identities, admin.ID)
+			}
+			if len(env.auth.loggedIn) != 1 || env.auth.loggedIn[0] != admin.ID {
+				t.Errorf("logged in %v, want %s", env.auth.loggedIn, admin.ID)
+			}
+		})
+	}
+}
+
+func TestOIDCLinkByEmailAmbiguous(t *testing.T) {
+	env := newOIDCTestEnv(t, func(cfg *config.OIDCProviderConfig) { cfg.LinkByEmail = true })
+	env.admins.add("jane", "jane@example.com", entity.RoleEditor, false)
+	env.admins.add("jane2", "JANE@example.com", entity.RoleAuthor, false)
+
+	if _, err := env.login(t, identityClaims("user-1", "jane@example.com")); !errors.Is(err, ErrOIDCLinkRequired) {
+		t.Errorf("Callback() error = %v, want ErrOIDCLinkRequired", err)
+	}
+}
+
+// TestOIDCLinkFromAccount checks that an owner, never linked by email,
+// links the identity while signed in and can then sign in with it
+func TestOIDCLinkFromAccount(t *testing.T) {
+	env := newOIDCTestEnv(t, func(cfg *config.OIDCProviderConfig) { cfg.LinkByEmail = true })
+	owner := env.admins.add("owner", "owner@example.com", entity.RoleOwner, true)
+
+	start, err := env.service.StartLink("mock", owner.ID)
+	if err != nil {
+		t.Fatalf("StartLink() error = %v", err)
+	}
+	code, state := env.provider.authorize(start.AuthorizationURL, identityClaims("user-1", "owner@example.com"))
+	req := dto.OIDCCallbackRequest{Code: code, State: state, StateToken: start.StateToken}
+
+	if _, err := env.service.Link(req, uuid.New()); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("Link() by another admin error = %v, want ErrInvalidOIDCState", err)
+	}
+	if _, err := env.service.Link(req, owner.ID); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+
+	if _, err := env.login(t, identityClaims("user-1", "owner@example.com")); err != nil {
+		t.Fatalf("Callback() error = %v", err)
+	}
+	if len(env.auth.loggedIn) != 1 || env.auth.loggedIn[0] != owner.ID {
+		t.Errorf("logged in %v, want %s", env.auth.loggedIn, owner.ID)
+	}
+}
+
+func TestOIDCAllowedDomains(t *testing.T) {
+	tests := []struct {
+		name        string
+		linkByEmail bool
+		provision   bool
+		email       string
+		want        error
+		created     bool
+	}{
+		{"link in an allowed domain", true, false, "jane@example.com", nil, false},
+		{"link outside the allowed domains", true, false, "jane@evil.example", ErrOIDCNoAccount, false},
+		{"provision in an allowed domain", false, true, "new@Example.com", nil, true},
+		{"provision outside the allowed domains", false, true, "new@evil.example", ErrOIDCNoAccount, false},
+		{"provision without an email", false, true, "", ErrOIDCNoAccount, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t, func(cfg *config.OIDCProviderConfig) {
+				cfg.LinkByEmail = tt.linkByEmail
+				cfg.AutoProvision = tt.provision
+				cfg.AllowedDomains = []string{"example.com"}
+			})
+			env.admins.add("jane", "jane@example.com", entity.RoleEditor, false)
+			env.admins.add("evil", "jane@evil.example", entity.RoleEditor, false)
+
+			_, err := env.login(t, identityClaims("user-1", tt.email))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Callback() error = %v, want %v", err, tt.want)
+			}
+			if created := len(env.admins.admins) == 3; created != tt.created {
+				t.Errorf("admin created = %v, want %v", created, tt.created)
+			}
+			if tt.created {
+				admin := env.admins.admins[2]
+				if admin.Role != entity.RoleAuthor || admin.Email == nil || *admin.Email != tt.email {
+					t.Errorf("created %s with role %s and email %v", admin.Username, admin.Role, admin.Email)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/dto"
+	"backend/internal/repository"
+	"backend/pkg/markdown"
+	"bytes"
+	"errors"
+	"html/template"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrPageNotFound = errors.New("page not found")
+
+// PostPage is the server-rendered HTML of a post
+type PostPage struct {
+	PostID uuid.UUID
+	Slug   string
+	HTML   []byte
+}
+
+// PageService renders posts as plain HTML pages for crawlers and link
+// previews, which do not run the SPA's JavaScript
+type PageService interface {
+	// RenderPost renders the published post with the slug, or with the ID
+	// for links from before posts had slugs
+	RenderPost(siteURL, slug string) (*PostPage, error)
+}
+
+type pageService struct {
+	cfg      config.FeedConfig
+	postRepo repository.PostRepository
+}
+
+func NewPageService(cfg config.FeedConfig, postRepo repository.PostRepository) PageService {
+	return &pageService{
+		cfg:      cfg,
+		postRepo: postRepo,
+	}
+}
+
+func (s *pageService) RenderPost(siteURL, slug string) (*PostPage, error) {
+	post, err := s.postRepo.FindBySlug(slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if id, parseErr := uuid.Parse(slug); parseErr == nil {
+			post, err = s.postRepo.FindByID(id)
+		}
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !post.IsPublished {
+		return nil, ErrPageNotFound
+	}
+
+	content, err := markdown.ToHTML(post.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	seo := postSEO(siteURL, post)
+	tags := make([]string, len(post.Tags))
+	for i, tag := range post.Tags {
+		tags[i] = tag.Name
+	}
+
+	data := postPageData{
+		Language:      s.cfg.Language,
+		SiteName:      s.cfg.Title,
+		SEO:           seo,
+		JSONLD:        template.JS(seo.JSONLD),
+		URL:           postURL(siteURL, post.Slug),
+		SPAURL:        spaPostURL(siteURL, post.ID),
+		HomeURL:       homeURL(siteURL),
+		Title:         post.Title,
+		Excerpt:       post.Excerpt,
+		PublishedDate: post.PublishedDate,
+		ModifiedTime:  post.UpdatedAt.UTC().Format(time.RFC3339),
+		ReadTime:      post.ReadTime,
+		Tags:          tags,
+		Content:       template.HTML(content),
+	}
+	if post.Author != nil {
+		data.Author = post.Author.Username
+	}
+
+	var buf bytes.Buffer
+	if err := postPageTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return &PostPage{
+		PostID: post.ID,
+		Slug:   post.Slug,
+		HTML:   buf.Bytes(),
+	}, nil
+}
+
+type postPageData struct {
+	Language      string
+	SiteName      string
+	SEO           dto.PostSEO
+	JSONLD        template.JS
+	URL           string
+	SPAURL        string
+	HomeURL       string
+	Title         string
+	Excerpt       string
+	Author        string
+	PublishedDate time.Time
+	ModifiedTime  string
+	ReadTime      string
+	Tags          []string
+	Content       template.HTML
+}
+
+// postPageTemplate is a bare page: crawlers only need the metadata and the
+// text, and people who land on it follow the link to the SPA
+var postPageTemplate = template.Must(template.New("post").Parse(`<!DOCTYPE html>
+<html lang="{{.Language}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.SEO.Title}} - {{.SiteName}}</title>
+<meta name="description" content="{{.SEO.Description}}">
+<meta name="robots" content="{{.SEO.Robots}}">
+<link rel="canonical" href="{{.SEO.CanonicalURL}}">
+<meta property="og:type" content="article">
+<meta property="og:site_name" content="{{.SiteName}}">
+<meta property="og:title" content="{{.SEO.Title}}">
+<meta property="og:description" content="{{.SEO.Description}}">
+<meta property="og:url" content="{{.SEO.CanonicalURL}}">
+{{- if .SEO.OGImage}}
+<meta property="og:image" content="{{.SEO.OGImage}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="{{.SEO.OGImage}}">
+{{- else}}
+<meta name="twitter:card" content="summary">
+{{- end}}
+<meta name="twitter:title" content="{{.SEO.Title}}">
+<meta name="twitter:description" content="{{.SEO.Description}}">
+<meta property="article:published_time" content="{{.PublishedDate.Format "2006-01-02"}}">
+<meta property="article:modified_time" content="{{.ModifiedTime}}">
+{{- range .Tags}}
+<meta property="article:tag" content="{{.}}">
+{{- end}}
+<link rel="alternate" type="application/rss+xml" title="{{.SiteName}}" href="{{.HomeURL}}feed.xml">
+<script type="application/ld+json">{{.JSONLD}}</script>
+</head>
+<body>
+<header><a href="{{.HomeURL}}">{{.SiteName}}</a></header>
+<main>
+<article>
+<h1>{{.Title}}</h1>
+<p>
+<time datetime="{{.PublishedDate.Format "2006-01-02"}}">{{.PublishedDate.Format "2006-01-02"}}</time>
+{{- if .Author}} · {{.Author}}{{end}}
+{{- if .ReadTime}} · {{.ReadTime}}{{end}}
+</p>
+{{- if .Tags}}
+<ul>{{range .Tags}}<li>{{.}}</li>{{end}}</ul>
+{{- end}}
+{{- if .Excerpt}}
+<p><em>{{.Excerpt}}</em></p>
+{{- end}}
+{{.Content}}
+</article>
+</main>
+<footer><a href="{{.SPAURL}}">{{.URL}}</a></footer>
+</body>
+</html>
+`))
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrWeakPassword is wrapped with the reason a password was rejected
+var ErrWeakPassword = errors.New("password is too weak")
+
+const (
+	minPasswordLength = 10
+	// passphraseLength is long enough to skip the character class rule
+	passphraseLength = 16
+	// maxPasswordBytes is where bcrypt stops reading
+	maxPasswordBytes = 72
+)
+
+// commonPasswords are rejected regardless of the other rules
+var commonPasswords = map[string]bool{
+	"password123": true, "password1234": true, "qwerty123456": true, "1234567890": true,
+	"12345678910": true, "iloveyou123": true, "administrator": true, "admin123456": true,
+	"root123456": true, "changeme123": true, "letmein12345": true, "welcome12345": true,
+}
+
+// ValidatePassword enforces the password policy: at least 10 characters,
+// three of lowercase, uppercase, digits and symbols unless it is a 16+
+// character passphrase, not the username and not a common password
+func ValidatePassword(password, username string) error {
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("%w: it must be at most %d bytes", ErrWeakPassword, maxPasswordBytes)
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < minPasswordLength {
+		return fmt.Errorf("%w: it must be at least %d characters", ErrWeakPassword, minPasswordLength)
+	}
+
+	lower := strings.ToLower(password)
+	if commonPasswords[lower] {
+		return fmt.Errorf("%w: it is too common", ErrWeakPassword)
+	}
+	if username != "" && strings.Contains(lower, strings.ToLower(username)) {
+		return fmt.Errorf("%w: it must not contain the username", ErrWeakPassword)
+	}
+
+	if length < passphraseLength && characterClasses(password) < 3 {
+		return fmt.Errorf("%w: use three of lowercase, uppercase, digits and symbols, or at least %d characters", ErrWeakPassword, passphraseLength)
+	}
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	return classes
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"backend/internal/model/entity"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrForbidden means the admin's role does not allow the action
+var ErrForbidden = errors.New("your role does not allow this action")
+
+// Permission is an action guarded by role
+type Permission string
+
+const (
+	PermWritePosts      Permission = "posts:write"       // Create posts, edit and delete own posts
+	PermEditAnyPost     Permission = "posts:edit_any"    // Edit and delete posts of others
+	PermManageTags      Permission = "tags:manage"       // Create and delete tags
+	PermModerateComment Permission = "comments:moderate" // List, reply to and delete comments
+	PermViewStats       Permission = "stats:view"        // Dashboard statistics and reports
+	PermManageSEO       Permission = "seo:manage"        // Search engine pushes and IndexNow keys
+	PermUseAI           Permission = "ai:use"            // AI writing assistance
+	PermUpload          Permission = "files:upload"      // File uploads
+	PermManageSecurity  Permission = "security:manage"   // Login lockouts
+	PermManageUsers     Permission = "users:manage"      // Admin accounts and roles
+	PermViewAudit       Permission = "audit:view"        // Audit log of admin actions
+)
+
+// rolePermissions is the permission matrix. Every role may manage its own
+// account: profile, sessions and two-factor authentication.
+var rolePermissions = map[string][]Permission{
+	entity.RoleOwner: {
+		PermWritePosts, PermEditAnyPost, PermManageTags, PermModerateComment, PermViewStats,
+		PermManageSEO, PermUseAI, PermUpload, PermManageSecurity, PermManageUsers, PermViewAudit,
+	},
+	entity.RoleEditor: {
+		PermWritePosts, PermEditAnyPost, PermManageTags, PermModerateComment, PermViewStats,
+		PermManageSEO, PermUseAI, PermUpload,
+	},
+	entity.RoleAuthor: {
+		PermWritePosts, PermUseAI, PermUpload,
+	},
+	entity.RoleModerator: {
+		PermModerateComment,
+	},
+}
+
+// RoleHas reports whether the role grants the permission
+func RoleHas(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor is the admin making a request, for checks that depend on the
+// resource, like authors editing only their own posts. Scopes limit a
+// request made with an API token; nil means a login session.
+type Actor struct {
+	ID     uuid.UUID
+	Role   string
+	Scopes []Permission
+}
+
+func (a Actor) Can(perm Permission) bool {
+	if !RoleHas(a.Role, perm) {
+		return false
+	}
+	if a.Scopes == nil {
+		return true
+	}
+	for _, scope := range a.Scopes {
+		if scope == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type PostEventType string
+
+// Post lifecycle events, only raised for posts visible to the public
+// before or after the change
+const (
+	PostPublished   PostEventType = "published"
+	PostUpdated     PostEventType = "updated"
+	PostUnpublished PostEventType = "unpublished"
+	PostDeleted     PostEventType = "deleted"
+)
+
+type PostEvent struct {
+	Type            PostEventType
+	PostID          uuid.UUID
+	Slug            string
+	PreviousSlug    string // Slug before an update, when it changed
+	NoIndex         bool   // The post asks search engines not to index it
+	PreviousNoIndex bool   // NoIndex before an update
+}
+
+// PostEventBus fans post events out to subscribers. Subscribers are called
+// synchronously on the request path and must not block.
+type PostEventBus interface {
+	Subscribe(fn func(PostEvent))
+	Publish(event PostEvent)
+}
+
+type postEventBus struct {
+	mu          sync.RWMutex
+	subscribers []func(PostEvent)
+}
+
+func NewPostEventBus() PostEventBus {
+	return &postEventBus{}
+}
+
+func (b *postEventBus) Subscribe(fn func(PostEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, fn)
+}
+
+func (b *postEventBus) Publish(event PostEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, fn := range b.subscribers {
+		fn(event)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxDescriptionLength is where search engines cut off descriptions
+const maxDescriptionLength = 160
+
+var (
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^\s)>]+)>?`)
+	htmlImagePattern     = regexp.MustCompile(`(?i)<img[^>]+src\s*=\s*["']([^"']+)["']`)
+)
+
+// postSEO computes the meta tags and schema.org BlogPosting JSON-LD of a
+// post. Empty overrides fall back to the title, the excerpt, the post URL
+// and the generated share image.
+func postSEO(siteURL string, post *entity.BlogPost) dto.PostSEO {
+	seo := dto.PostSEO{
+		Title:        post.MetaTitle,
+		Description:  post.MetaDescription,
+		CanonicalURL: post.CanonicalURL,
+		OGImage:      post.OGImage,
+		Robots:       "index, follow",
+	}
+	if seo.Title == "" {
+		seo.Title = post.Title
+	}
+	if seo.Description == "" {
+		seo.Description = truncateRunes(strings.Join(strings.Fields(post.Excerpt), " "), maxDescriptionLength)
+	}
+	if seo.CanonicalURL == "" {
+		seo.CanonicalURL = postURL(siteURL, post.Slug)
+	}
+	if seo.OGImage == "" {
+		seo.OGImage = ogImageURL(siteURL, post.ID)
+	}
+	if post.NoIndex || !post.IsPublished {
+		seo.Robots = "noindex, nofollow"
+	}
+
+	seo.JSONLD = blogPostingJSONLD(siteURL, post, seo)
+	return seo
+}
+
+func blogPostingJSONLD(siteURL string, post *entity.BlogPost, seo dto.PostSEO) json.RawMessage {
+	ld := map[string]interface{}{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         truncateRunes(post.Title, 110),
+		"description":      seo.Description,
+		"url":              seo.CanonicalURL,
+		"mainEntityOfPage": map[string]string{"@type": "WebPage", "@id": seo.CanonicalURL},
+		"datePublished":    post.PublishedDate.Format("2006-01-02"),
+		"dateModified":     post.UpdatedAt.UTC().Format(time.RFC3339),
+		"wordCount":        len(strings.Fields(post.Content)),
+	}
+	images := []string{seo.OGImage}
+	if image := absoluteURL(siteURL, firstImage(post.Content)); image != "" && image != seo.OGImage {
+		images = append(images, image)
+	}
+	ld["image"] = images
+	if len(post.Tags) > 0 {
+		keywords := make([]string, len(post.Tags))
+		for i, tag := range post.Tags {
+			keywords[i] = tag.Name
+		}
+		ld["keywords"] = strings.Join(keywords, ", ")
+	}
+	if post.Author != nil {
+		ld["author"] = map[string]string{"@type": "Person", "name": post.Author.Username}
+	}
+
+	data, err := json.Marshal(ld)
+	if err != nil {
+		return json.RawMessage("{}")
+	}
+	return data
+}
+
+// firstImage returns the source of the first Markdown or HTML image
+func firstImage(content string) string {
+	var src string
+	index := -1
+	if m := markdownImagePattern.FindStringSubmatchIndex(content); m != nil {
+		src, index = content[m[2]:m[3]], m[0]
+	}
+	if m := htmlImagePattern.FindStringSubmatchIndex(content); m != nil && (index < 0 || m[0] < index) {
+		src = content[m[2]:m[3]]
+	}
+	return src
+}
+
+// absoluteURL resolves a possibly relative URL against the site URL
+func absoluteURL(siteURL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if u.IsAbs() || siteURL == "" {
+		return ref
+	}
+	base, err := url.Parse(homeURL(siteURL))
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
@@ -13,23 +13,36 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidSlug = errors.New("slug may only contain lowercase letters, digits and dashes")
+	ErrSlugTaken   = errors.New("slug is already used by another post")
+
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
 type PostService interface {
 	GetPosts(query dto.PostListQuery) (*dto.PostListResponse, error)
 	GetPostByID(id string) (*dto.PostResponse, error)
 	CreatePost(req dto.CreatePostRequest, authorID *uuid.UUID) (*dto.PostResponse, error)
-	UpdatePost(id string, req dto.UpdatePostRequest) (*dto.PostResponse, error)
-	DeletePost(id string) error
+	UpdatePost(id string, req dto.UpdatePostRequest, actor Actor) (*dto.PostResponse, error)
+	DeletePost(id string, actor Actor) error
 }
 
 type postService struct {
-	postRepo repository.PostRepository
-	tagRepo  repository.TagRepository
+	postRepo     repository.PostRepository
+	tagRepo      repository.TagRepository
+	reactionRepo repository.ReactionRepository
+	events       PostEventBus
+	siteURL      string // Public site URL for canonical URLs and JSON-LD
 }
 
-func NewPostService(postRepo repository.PostRepository, tagRepo repository.TagRepository) PostService {
+func NewPostService(postRepo repository.PostRepository, tagRepo repository.TagRepository, reactionRepo repository.ReactionRepository, events PostEventBus, siteURL string) PostService {
 	return &postService{
-		postRepo: postRepo,
-		tagRepo:  tagRepo,
+		postRepo:     postRepo,
+		tagRepo:      tagRepo,
+		reactionRepo: reactionRepo,
+		events:       events,
+		siteURL:      siteURL,
 	}
 }
 
@@ -69,10 +82,10 @@ func (s *postService) GetPostByID(id string) (*dto.PostResponse, error) {
 		return nil, err
 	}
 
-	// Increment view count asynchronously
-	go s.postRepo.IncrementViewCount(postID)
-
 	response := s.toPostResponse(post)
+	if err := s.attachReactions(&response, postID); err != nil {
+		return nil, err
+	}
 	return &response, nil
 }
 
@@ -89,8 +102,14 @@ func (s *postService) CreatePost(req dto.CreatePostRequest, authorID *uuid.UUID)
 		readTime = s.calculateReadTime(req.Content)
 	}
 
+	slug, err := s.uniqueSlug(req.Slug, req.Title, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
 	post := &entity.BlogPost{
 		Title:         req.Title,
+		Slug:          slug,
 		Excerpt:       req.Excerpt,
 		Content:       req.Content,
 		ReadTime:      readTime,
@@ -98,6 +117,12 @@ func (s *postService) CreatePost(req dto.CreatePostRequest, authorID *uuid.UUID)
 		IsPublished:   false,
 		AuthorID:      authorID,
 		Tags:          tags,
+
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+		CanonicalURL:    req.CanonicalURL,
+		OGImage:         req.OGImage,
+		NoIndex:         req.NoIndex,
 	}
 
 	if err := s.postRepo.Create(post); err != nil {
@@ -108,7 +133,7 @@ func (s *postService) CreatePost(req dto.CreatePostRequest, authorID *uuid.UUID)
 	return &response, nil
 }
 
-func (s *postService) UpdatePost(id string, req dto.UpdatePostRequest) (*dto.PostResponse, error) {
+func (s *postService) UpdatePost(id string, req dto.UpdatePostRequest, actor Actor) (*dto.PostResponse, error) {
 	postID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, errors.New("invalid post ID")
@@ -118,6 +143,12 @@ func (s *postService) UpdatePost(id string, req dto.UpdatePostRequest) (*dto.Pos
 	if err != nil {
 		return nil, err
 	}
+	if !canEditPost(actor, post) {
+		return nil, ErrForbidden
+	}
+	wasPublished := post.IsPublished
+	wasNoIndex := post.NoIndex
+	previousSlug := post.Slug
 
 	// Update fields
 	if req.Title != nil {
@@ -139,6 +170,28 @@ func (s *postService) UpdatePost(id string, req dto.UpdatePostRequest) (*dto.Pos
 	if req.IsPublished != nil {
 		post.IsPublished = *req.IsPublished
 	}
+	if req.Slug != nil && *req.Slug != post.Slug {
+		slug, err := s.uniqueSlug(*req.Slug, post.Title, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		post.Slug = slug
+	}
+	if req.MetaTitle != nil {
+		post.MetaTitle = *req.MetaTitle
+	}
+	if req.MetaDescription != nil {
+		post.MetaDescription = *req.MetaDescription
+	}
+	if req.CanonicalURL != nil {
+		post.CanonicalURL = *req.CanonicalURL
+	}
+	if req.OGImage != nil {
+		post.OGImage = *req.OGImage
+	}
+	if req.NoIndex != nil {
+		post.NoIndex = *req.NoIndex
+	}
 	if req.Tags != nil {
 		tags, err := s.getOrCreateTags(req.Tags)
 		if err != nil {
@@ -151,16 +204,59 @@ func (s *postService) UpdatePost(id string, req dto.UpdatePostRequest) (*dto.Pos
 		return nil, err
 	}
 
+	event := PostEvent{PostID: post.ID, Slug: post.Slug, NoIndex: post.NoIndex, PreviousNoIndex: wasNoIndex}
+	if previousSlug != post.Slug {
+		event.PreviousSlug = previousSlug
+	}
+	switch {
+	case post.IsPublished && !wasPublished:
+		event.Type = PostPublished
+		s.events.Publish(event)
+	case post.IsPublished:
+		event.Type = PostUpdated
+		s.events.Publish(event)
+	case wasPublished:
+		event.Type = PostUnpublished
+		s.events.Publish(event)
+	}
+
 	response := s.toPostResponse(post)
+	if err := s.attachReactions(&response, postID); err != nil {
+		return nil, err
+	}
 	return &response, nil
 }
 
-func (s *postService) DeletePost(id string) error {
+func (s *postService) DeletePost(id string, actor Actor) error {
 	postID, err := uuid.Parse(id)
 	if err != nil {
 		return errors.New("invalid post ID")
 	}
-	return s.postRepo.Delete(postID)
+
+	post, err := s.postRepo.FindByID(postID)
+	if err != nil {
+		return err
+	}
+	if !canEditPost(actor, post) {
+		return ErrForbidden
+	}
+
+	if err := s.postRepo.Delete(postID); err != nil {
+		return err
+	}
+
+	if post.IsPublished {
+		s.events.Publish(PostEvent{Type: PostDeleted, PostID: postID, Slug: post.Slug, NoIndex: post.NoIndex, PreviousNoIndex: post.NoIndex})
+	}
+	return nil
+}
+
+// canEditPost - 作者只能修改自己的文章，编辑和站长可以修改所有文章
+func canEditPost(actor Actor, post *entity.BlogPost) bool {
+	if actor.Can(PermEditAnyPost) {
+		return true
+	}
+	return actor.Can(PermWritePosts) && post.AuthorID != nil && *post.AuthorID == actor.ID
 }
 
 // toPostListItem - 转换为列表项（不含 content）
@@ -172,6 +268,7 @@ func (s *postService) toPostListItem(post *entity.BlogPost) dto.PostListItem {
 
 	return dto.PostListItem{
 		ID:          post.ID.String(),
+		Slug:        post.Slug,
 		Title:       post.Title,
 		Date:        post.PublishedDate.Format("2006-01-02"),
 		Tags:        tagNames,
@@ -193,6 +290,7 @@ func (s *postService) toPostResponse(post *entity.BlogPost) dto.PostResponse {
 
 	return dto.PostResponse{
 		ID:          post.ID.String(),
+		Slug:        post.Slug,
 		Title:       post.Title,
 		Date:        post.PublishedDate.Format("2006-01-02"),
 		Tags:        tagNames,
@@ -200,12 +298,31 @@ func (s *postService) toPostResponse(post *entity.BlogPost) dto.PostResponse {
 		Content:     post.Content,
 		ReadTime:    post.ReadTime,
 		ViewCount:   post.ViewCount,
+		Reactions:   map[string]int64{},
 		IsPublished: post.IsPublished,
 		CreatedAt:   post.CreatedAt,
 		UpdatedAt:   post.UpdatedAt,
+		PostSEOFields: dto.PostSEOFields{
+			MetaTitle:       post.MetaTitle,
+			MetaDescription: post.MetaDescription,
+			CanonicalURL:    post.CanonicalURL,
+			OGImage:         post.OGImage,
+			NoIndex:         post.NoIndex,
+		},
+		SEO: postSEO(s.siteURL, post),
 	}
 }
 
+// attachReactions - 填充文章的读者反馈统计
+func (s *postService) attachReactions(response *dto.PostResponse, postID uuid.UUID) error {
+	reactions, err := s.reactionRepo.CountByPostID(postID)
+	if err != nil {
+		return err
+	}
+	response.Reactions = reactions
+	return nil
+}
+
 func (s *postService) getOrCreateTags(tagNames []string) ([]entity.Tag, error) {
 	var result []entity.Tag
 	for _, name := range tagNames {
@@ -229,6 +346,45 @@ func (s *postService) slugify(text string) string {
 	return text
 }
 
+// uniqueSlug validates a requested slug, or derives one from the title
+// when none is given. Derived slugs get a numeric suffix until no other
+// post uses them; a requested slug that is taken is an error.
+func (s *postService) uniqueSlug(requested, title string, postID uuid.UUID) (string, error) {
+	if requested != "" {
+		if !slugPattern.MatchString(requested) {
+			return "", ErrInvalidSlug
+		}
+		exists, err := s.postRepo.SlugExists(requested, postID)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", ErrSlugTaken
+		}
+		return requested, nil
+	}
+
+	base := s.slugify(title)
+	if len(base) > 80 {
+		base = strings.Trim(base[:80], "-")
+	}
+	if base == "" {
+		base = "post"
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := s.postRepo.SlugExists(slug, postID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
 func (s *postService) calculateReadTime(content string) string {
 	words := len(strings.Fields(content))
 	minutes := words / 200
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type ReactionService interface {
+	React(postID string, reaction string, visitor Visitor) (*dto.ReactionSummaryResponse, error)
+	Unreact(postID string, reaction string, visitor Visitor) (*dto.ReactionSummaryResponse, error)
+}
+
+type reactionService struct {
+	reactionRepo repository.ReactionRepository
+	postRepo     repository.PostRepository
+	salt         []byte
+}
+
+func NewReactionService(reactionRepo repository.ReactionRepository, postRepo repository.PostRepository, salt string) ReactionService {
+	return &reactionService{
+		reactionRepo: reactionRepo,
+		postRepo:     postRepo,
+		salt:         newSalt(salt),
+	}
+}
+
+// opposingReactions lists reactions that cancel each other out
+var opposingReactions = map[string]string{
+	entity.ReactionHelpful:    entity.ReactionNotHelpful,
+	entity.ReactionNotHelpful: entity.ReactionHelpful,
+}
+
+func (s *reactionService) React(postID string, reaction string, visitor Visitor) (*dto.ReactionSummaryResponse, error) {
+	pID, err := s.findPublishedPost(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	visitorHash := visitor.hash(s.salt)
+
+	// A reader can't find a post both helpful and not helpful
+	if opposite, ok := opposingReactions[reaction]; ok {
+		if err := s.reactionRepo.Remove(pID, visitorHash, opposite); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.reactionRepo.Add(&entity.PostReaction{
+		PostID:      pID,
+		Reaction:    reaction,
+		VisitorHash: visitorHash,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.summary(pID, visitorHash)
+}
+
+func (s *reactionService) Unreact(postID string, reaction string, visitor Visitor) (*dto.ReactionSummaryResponse, error) {
+	pID, err := s.findPublishedPost(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	visitorHash := visitor.hash(s.salt)
+	if err := s.reactionRepo.Remove(pID, visitorHash, reaction); err != nil {
+		return nil, err
+	}
+
+	return s.summary(pID, visitorHash)
+}
+
+func (s *reactionService) findPublishedPost(postID string) (uuid.UUID, error) {
+	pID, err := uuid.Parse(postID)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid post ID")
+	}
+
+	post, err := s.postRepo.FindByID(pID)
+	if err != nil || !post.IsPublished {
+		return uuid.Nil, errors.New("post not found")
+	}
+	return pID, nil
+}
+
+func (s *reactionService) summary(postID uuid.UUID, visitorHash string) (*dto.ReactionSummaryResponse, error) {
+	counts, err := s.reactionRepo.CountByPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	reacted, err := s.reactionRepo.FindVisitorReactions(postID, visitorHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReactionSummaryResponse{
+		PostID:  postID.String(),
+		Counts:  counts,
+		Reacted: reacted,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/repository"
+	"time"
+)
+
+type ReportService interface {
+	GetEngagementReport(query dto.EngagementReportQuery) (*dto.EngagementReportResponse, error)
+}
+
+type reportService struct {
+	reportRepo repository.ReportRepository
+}
+
+func NewReportService(reportRepo repository.ReportRepository) ReportService {
+	return &reportService{reportRepo: reportRepo}
+}
+
+func (s *reportService) GetEngagementReport(query dto.EngagementReportQuery) (*dto.EngagementReportResponse, error) {
+	since := time.Now().AddDate(0, 0, -query.Days)
+
+	rows, err := s.reportRepo.EngagementRanking(since, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.EngagementItem, len(rows))
+	for i, row := range rows {
+		items[i] = dto.EngagementItem{
+			PostID:    row.PostID.String(),
+			Title:     row.Title,
+			Views:     row.Views,
+			Reactions: row.Reactions,
+			Comments:  row.Comments,
+			Score:     row.Score,
+		}
+	}
+
+	return &dto.EngagementReportResponse{
+		Since: since,
+		Days:  query.Days,
+		Posts: items,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// errQuotaExceeded is returned by a pusher when the engine refuses more URLs today
+var errQuotaExceeded = errors.New("daily quota exceeded")
+
+// PushResponse is what a search engine answered to a push request
+type PushResponse struct {
+	StatusCode int
+	Body       string
+	Remaining  int // Remaining daily quota reported by the engine, -1 if unknown
+	Sent       int // Number of leading URLs accepted before an error
+}
+
+// SearchEnginePusher notifies a search engine about added, changed
+// (entity.SEOActionUpdate) or removed (entity.SEOActionDelete) URLs.
+// Push returns errQuotaExceeded when the engine refuses more URLs today;
+// the URLs are then kept for the next day. Engines taking one URL per
+// request report in Sent how many were accepted before an error, so only
+// the others are sent again.
+type SearchEnginePusher interface {
+	Name() string
+	DailyQuota() int
+	Push(action string, urls []string) (*PushResponse, error)
+}
+
+// SearchEngineDeps are what pushers may need besides their config
+type SearchEngineDeps struct {
+	Client   *http.Client
+	IndexNow IndexNowService
+}
+
+// SearchEngineFactory builds a pusher from the SEO config. It returns nil
+// when the engine is disabled or not configured.
+type SearchEngineFactory func(cfg config.SEOConfig, deps SearchEngineDeps) (SearchEnginePusher, error)
+
+type searchEngineRegistration struct {
+	name    string
+	factory SearchEngineFactory
+}
+
+// searchEngines lists the known engines in the order they are pushed to
+var searchEngines = []searchEngineRegistration{
+	{name: entity.SEOEngineBaidu, factory: newBaiduPusher},
+	{name: entity.SEOEngineIndexNow, factory: newIndexNowPusher},
+	{name: entity.SEOEngineGoogle, factory: newGooglePusher},
+}
+
+// RegisterSearchEngine adds a search engine adapter. It must be called
+// before the SEO service is created.
+func RegisterSearchEngine(name string, factory SearchEngineFactory) {
+	searchEngines = append(searchEngines, searchEngineRegistration{name: name, factory: factory})
+}
+
+// NewSearchEnginePushers builds the pushers of all enabled engines. An
+// engine that fails to initialize is logged and skipped.
+func NewSearchEnginePushers(cfg config.SEOConfig, deps SearchEngineDeps) []SearchEnginePusher {
+	if cfg.SiteURL == "" {
+		return nil
+	}
+	if deps.Client == nil {
+		deps.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	var pushers []SearchEnginePusher
+	for _, engine := range searchEngines {
+		pusher, err := engine.factory(cfg, deps)
+		if err != nil {
+			log.Printf("Search engine %s not available: %v", engine.name, err)
+			continue
+		}
+		if pusher != nil {
+			pushers = append(pushers, pusher)
+		}
+	}
+	return pushers
+}
+
+// doPush sends a push request and reads the response, without judging it
+func doPush(client *http.Client, req *http.Request) (*PushResponse, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	return &PushResponse{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		Remaining:  -1,
+	}, nil
+}
+
+func newPushRequest(url, contentType string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return req, nil
+}
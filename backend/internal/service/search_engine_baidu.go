@@ -0,0 +1,68 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// baiduPusher pushes URLs to the Baidu webmaster API
+// API: {endpoint}/urls?site=xxx&token=xxx, {endpoint}/del for removed URLs
+type baiduPusher struct {
+	cfg    config.BaiduPushConfig
+	client *http.Client
+}
+
+func newBaiduPusher(cfg config.SEOConfig, deps SearchEngineDeps) (SearchEnginePusher, error) {
+	if !cfg.Baidu.Enabled || cfg.Baidu.Site == "" || cfg.Baidu.Token == "" {
+		return nil, nil
+	}
+	return &baiduPusher{cfg: cfg.Baidu, client: deps.Client}, nil
+}
+
+func (p *baiduPusher) Name() string {
+	return entity.SEOEngineBaidu
+}
+
+func (p *baiduPusher) DailyQuota() int {
+	return p.cfg.DailyQuota
+}
+
+func (p *baiduPusher) Push(action string, urls []string) (*PushResponse, error) {
+	path := "urls"
+	if action == entity.SEOActionDelete {
+		path = "del"
+	}
+	apiURL := fmt.Sprintf("%s/%s?site=%s&token=%s", strings.TrimSuffix(p.cfg.Endpoint, "/"), path,
+		url.QueryEscape(p.cfg.Site), url.QueryEscape(p.cfg.Token))
+
+	req, err := newPushRequest(apiURL, "text/plain", []byte(strings.Join(urls, "\n")))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doPush(p.client, req)
+	if err != nil {
+		return resp, err
+	}
+
+	var result struct {
+		Remain  *int   `json:"remain"`
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal([]byte(resp.Body), &result)
+	if result.Remain != nil {
+		resp.Remaining = *result.Remain
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if strings.Contains(result.Message, "over quota") {
+			return resp, errQuotaExceeded
+		}
+		return resp, fmt.Errorf("baidu API returned %d: %s", resp.StatusCode, resp.Body)
+	}
+	return resp, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	googleIndexingScope   = "https://www.googleapis.com/auth/indexing"
+	googleDefaultTokenURL = "https://oauth2.googleapis.com/token"
+)
+
+// googleServiceAccount is the part of a service account JSON key we need
+type googleServiceAccount struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// googlePusher notifies the Google Indexing API about updated and deleted
+// URLs. It authenticates as a service account: a JWT signed with the
+// account's key is exchanged for an access token, which is reused until it
+// expires. The API takes one URL per request.
+// https://developers.google.com/search/apis/indexing-api/v3/using-api
+type googlePusher struct {
+	cfg      config.GooglePushConfig
+	client   *http.Client
+	email    string
+	keyID    string
+	key      *rsa.PrivateKey
+	tokenURL string
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func newGooglePusher(cfg config.SEOConfig, deps SearchEngineDeps) (SearchEnginePusher, error) {
+	if !cfg.Google.Enabled || cfg.Google.CredentialsFile == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(cfg.Google.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	var account googleServiceAccount
+	if err := json.Unmarshal(content, &account); err != nil {
+		return nil, fmt.Errorf("invalid credentials file: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("credentials file is not a service account key")
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	tokenURL := cfg.Google.TokenURL
+	if tokenURL == "" {
+		tokenURL = account.TokenURI
+	}
+	if tokenURL == "" {
+		tokenURL = googleDefaultTokenURL
+	}
+
+	return &googlePusher{
+		cfg:      cfg.Google,
+		client:   deps.Client,
+		email:    account.ClientEmail,
+		keyID:    account.PrivateKeyID,
+		key:      key,
+		tokenURL: tokenURL,
+	}, nil
+}
+
+func (p *googlePusher) Name() string {
+	return entity.SEOEngineGoogle
+}
+
+func (p *googlePusher) DailyQuota() int {
+	return p.cfg.DailyQuota
+}
+
+func (p *googlePusher) Push(action string, urls []string) (*PushResponse, error) {
+	notificationType := "URL_UPDATED"
+	if action == entity.SEOActionDelete {
+		notificationType = "URL_DELETED"
+	}
+
+	token, err := p.token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	result := &PushResponse{Remaining: -1}
+	var bodies []string
+	for _, u := range urls {
+		body, err := json.Marshal(map[string]string{"url": u, "type": notificationType})
+		if err != nil {
+			return result, err
+		}
+
+		req, err := newPushRequest(p.cfg.Endpoint, "application/json", body)
+		if err != nil {
+			return result, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := doPush(p.client, req)
+		if err != nil {
+			return result, err
+		}
+
+		result.StatusCode = resp.StatusCode
+		bodies = append(bodies, fmt.Sprintf("%s: %d %s", u, resp.StatusCode, strings.TrimSpace(resp.Body)))
+		result.Body = strings.Join(bodies, "\n")
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return result, errQuotaExceeded
+		case resp.StatusCode == http.StatusUnauthorized:
+			p.resetToken()
+			return result, fmt.Errorf("google Indexing API rejected the access token")
+		case resp.StatusCode != http.StatusOK:
+			return result, fmt.Errorf("google Indexing API returned %d for %s", resp.StatusCode, u)
+		}
+		result.Sent++
+	}
+	return result, nil
+}
+
+// token returns a cached access token, fetching a new one shortly before
+// the current one expires
+func (p *googlePusher) token() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && time.Now().Before(p.expiresAt.Add(-time.Minute)) {
+		return p.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.email,
+		"scope": googleIndexingScope,
+		"aud":   p.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if p.keyID != "" {
+		assertion.Header["kid"] = p.keyID
+	}
+	signed, err := assertion.SignedString(p.key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+	req, err := newPushRequest(p.tokenURL, "application/x-www-form-urlencoded", []byte(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	resp, err := doPush(p.client, req)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal([]byte(resp.Body), &result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("invalid token response: %s", resp.Body)
+	}
+
+	p.accessToken = result.AccessToken
+	p.expiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
+
+func (p *googlePusher) resetToken() {
+	p.mu.Lock()
+	p.accessToken = ""
+	p.mu.Unlock()
+}
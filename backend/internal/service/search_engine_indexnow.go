@@ -0,0 +1,84 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// indexNowPusher pushes URLs using the IndexNow protocol, used by Bing and
+// others. Removed URLs are submitted the same way, engines recrawl them.
+// https://www.indexnow.org/documentation
+type indexNowPusher struct {
+	cfg      config.IndexNowConfig
+	host     string
+	client   *http.Client
+	indexNow IndexNowService
+}
+
+func newIndexNowPusher(cfg config.SEOConfig, deps SearchEngineDeps) (SearchEnginePusher, error) {
+	if deps.IndexNow == nil || !deps.IndexNow.Enabled() {
+		return nil, nil
+	}
+
+	site, err := url.Parse(cfg.SiteURL)
+	if err != nil || site.Host == "" {
+		return nil, fmt.Errorf("invalid site URL %q", cfg.SiteURL)
+	}
+
+	return &indexNowPusher{
+		cfg:      cfg.IndexNow,
+		host:     site.Host,
+		client:   deps.Client,
+		indexNow: deps.IndexNow,
+	}, nil
+}
+
+func (p *indexNowPusher) Name() string {
+	return entity.SEOEngineIndexNow
+}
+
+func (p *indexNowPusher) DailyQuota() int {
+	return p.cfg.DailyQuota
+}
+
+func (p *indexNowPusher) Push(action string, urls []string) (*PushResponse, error) {
+	key, err := p.indexNow.ActiveKey()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"host":        p.host,
+		"key":         key,
+		"keyLocation": p.indexNow.KeyLocation(key),
+		"urlList":     urls,
+	}
+
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := newPushRequest(p.cfg.Endpoint, "application/json; charset=utf-8", jsonBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doPush(p.client, req)
+	if err != nil {
+		return resp, err
+	}
+
+	// IndexNow returns 200, 202, 429 when throttled, or 4xx for errors
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return resp, nil
+	case http.StatusTooManyRequests:
+		return resp, errQuotaExceeded
+	default:
+		return resp, fmt.Errorf("IndexNow API returned %d: %s", resp.StatusCode, resp.Body)
+	}
+}
@@ -0,0 +1,559 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeSEORepository keeps the push queue and quotas in memory. Methods the
+// push loop doesn't use are left to the embedded interface.
+type fakeSEORepository struct {
+	repository.SEORepository
+
+	mu      sync.Mutex
+	tasks   []entity.SEOPushTask
+	quota   map[string]int
+	history []entity.SEOPushHistory
+}
+
+func newFakeSEORepository(engine string, urls ...string) *fakeSEORepository {
+	r := &fakeSEORepository{quota: make(map[string]int)}
+	for i, u := range urls {
+		r.tasks = append(r.tasks, entity.SEOPushTask{
+			ID:     int64(i + 1),
+			Engine: engine,
+			URL:    u,
+			Action: entity.SEOActionUpdate,
+			Status: entity.SEOTaskPending,
+		})
+	}
+	return r
+}
+
+func (r *fakeSEORepository) FindDueTasks(engine string, now time.Time, limit int) ([]entity.SEOPushTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tasks []entity.SEOPushTask
+	for _, task := range r.tasks {
+		if len(tasks) < limit && task.Engine == engine && task.Status == entity.SEOTaskPending && !task.NextAttemptAt.After(now) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *fakeSEORepository) update(ids []int64, fn func(task *entity.SEOPushTask)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		for i := range r.tasks {
+			if r.tasks[i].ID == id {
+				fn(&r.tasks[i])
+			}
+		}
+	}
+}
+
+func (r *fakeSEORepository) MarkTasksSent(ids []int64) error {
+	r.update(ids, func(task *entity.SEOPushTask) {
+		task.Status = entity.SEOTaskSent
+		task.Attempts++
+		task.LastError = ""
+	})
+	return nil
+}
+
+func (r *fakeSEORepository) MarkTasksFailed(ids []int64, lastError string, retryAt time.Time, maxAttempts int) error {
+	r.update(ids, func(task *entity.SEOPushTask) {
+		task.Attempts++
+		if task.Attempts >= maxAttempts {
+			task.Status = entity.SEOTaskFailed
+		}
+		task.LastError = lastError
+		task.NextAttemptAt = retryAt
+	})
+	return nil
+}
+
+func (r *fakeSEORepository) QuotaUsed(engine string, day time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.quota[engine], nil
+}
+
+func (r *fakeSEORepository) AddQuotaUsed(engine string, day time.Time, n int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quota[engine] += n
+	return nil
+}
+
+func (r *fakeSEORepository) RaiseQuotaUsed(engine string, day time.Time, used int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quota[engine] = max(r.quota[engine], used)
+	return nil
+}
+
+func (r *fakeSEORepository) CreateHistory(history *entity.SEOPushHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = append(r.history, *history)
+	return nil
+}
+
+// statuses returns the status of every task, in queue order
+func (r *fakeSEORepository) statuses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	statuses := make([]string, len(r.tasks))
+	for i, task := range r.tasks {
+		statuses[i] = task.Status
+	}
+	return statuses
+}
+
+// fakeIndexNow hands out a fixed key
+type fakeIndexNow struct {
+	IndexNowService
+	key string
+}
+
+func (f *fakeIndexNow) Enabled() bool                 { return true }
+func (f *fakeIndexNow) ActiveKey() (string, error)    { return f.key, nil }
+func (f *fakeIndexNow) KeyLocation(key string) string { return "https://example.com/" + key + ".txt" }
+
+func TestBaiduPusher(t *testing.T) {
+	var gotPath, gotSite, gotToken, gotBody string
+	status, reply := http.StatusOK, `{"remain":98,"success":2}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+		gotSite, gotToken = r.URL.Query().Get("site"), r.URL.Query().Get("token")
+		w.WriteHeader(status)
+		io.WriteString(w, reply)
+	}))
+	defer server.Close()
+
+	cfg := config.SEOConfig{Baidu: config.BaiduPushConfig{
+		Enabled: true, Site: "https://example.com", Token: "t&k", Endpoint: server.URL + "/", DailyQuota: 100,
+	}}
+	pusher, err := newBaiduPusher(cfg, SearchEngineDeps{Client: server.Client()})
+	if err != nil || pusher == nil {
+		t.Fatalf("newBaiduPusher() = %v, %v", pusher, err)
+	}
+
+	urls := []string{"https://example.com/a", "https://example.com/b"}
+	resp, err := pusher.Push(entity.SEOActionUpdate, urls)
+	if err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if gotPath != "/urls" || gotSite != cfg.Baidu.Site || gotToken != cfg.Baidu.Token {
+		t.Errorf("request path %q, site %q, token %q", gotPath, gotSite, gotToken)
+	}
+	if gotBody != strings.Join(urls, "\n") {
+		t.Errorf("request body = %q", gotBody)
+	}
+	if resp.Remaining != 98 {
+		t.Errorf("Remaining = %d, want 98", resp.Remaining)
+	}
+
+	if _, err := pusher.Push(entity.SEOActionDelete, urls); err != nil || gotPath != "/del" {
+		t.Errorf("delete pushed to %q, error = %v", gotPath, err)
+	}
+
+	status, reply = http.StatusBadRequest, `{"error":400,"message":"over quota"}`
+	if _, err := pusher.Push(entity.SEOActionUpdate, urls); !errors.Is(err, errQuotaExceeded) {
+		t.Errorf("Push() over quota error = %v, want errQuotaExceeded", err)
+	}
+
+	status, reply = http.StatusUnauthorized, `{"error":401,"message":"token is not valid"}`
+	if _, err := pusher.Push(entity.SEOActionUpdate, urls); err == nil || errors.Is(err, errQuotaExceeded) {
+		t.Errorf("Push() with a bad token error = %v", err)
+	}
+}
+
+func TestIndexNowPusher(t *testing.T) {
+	var got struct {
+		Host        string   `json:"host"`
+		Key         string   `json:"key"`
+		KeyLocation string   `json:"keyLocation"`
+		URLList     []string `json:"urlList"`
+	}
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	cfg := config.SEOConfig{
+		SiteURL:  "https://example.com",
+		IndexNow: config.IndexNowConfig{Endpoint: server.URL, DailyQuota: 100},
+	}
+	pusher, err := newIndexNowPusher(cfg, SearchEngineDeps{Client: server.Client(), IndexNow: &fakeIndexNow{key: "abc123"}})
+	if err != nil || pusher == nil {
+		t.Fatalf("newIndexNowPusher() = %v, %v", pusher, err)
+	}
+
+	urls := []string{"https://example.com/a", "https://example.com/b"}
+	if _, err := pusher.Push(entity.SEOActionUpdate, urls); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if got.Host != "example.com" || got.Key != "abc123" || got.KeyLocation != "https://example.com/abc123.txt" {
+		t.Errorf("request host %q, key %q, keyLocation %q", got.Host, got.Key, got.KeyLocation)
+	}
+	if !reflect.DeepEqual(got.URLList, urls) {
+		t.Errorf("urlList = %v, want %v", got.URLList, urls)
+	}
+
+	status = http.StatusTooManyRequests
+	if _, err := pusher.Push(entity.SEOActionUpdate, urls); !errors.Is(err, errQuotaExceeded) {
+		t.Errorf("Push() throttled error = %v, want errQuotaExceeded", err)
+	}
+
+	status = http.StatusForbidden
+	if _, err := pusher.Push(entity.SEOActionUpdate, urls); err == nil || errors.Is(err, errQuotaExceeded) {
+		t.Errorf("Push() with a bad key error = %v", err)
+	}
+}
+
+// googleStub serves the OAuth token and Indexing API endpoints. publish
+// decides the status code for each notified URL.
+type googleStub struct {
+	t       *testing.T
+	key     *rsa.PrivateKey
+	server  *httptest.Server
+	publish func(u string) int
+
+	mu        sync.Mutex
+	tokens    int
+	published []string
+	types     []string
+}
+
+func newGoogleStub(t *testing.T) *googleStub {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := &googleStub{t: t, key: key, publish: func(string) int { return http.StatusOK }}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", stub.handleToken)
+	mux.HandleFunc("/publish", stub.handlePublish)
+	stub.server = httptest.NewServer(mux)
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func (s *googleStub) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+		s.t.Errorf("grant_type = %q", r.FormValue("grant_type"))
+	}
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(r.FormValue("assertion"), claims, func(*jwt.Token) (interface{}, error) {
+		return &s.key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithAudience(s.server.URL+"/token"))
+	if err != nil {
+		s.t.Errorf("invalid assertion: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if claims["iss"] != "pusher@example.iam.gserviceaccount.com" || claims["scope"] != googleIndexingScope || token.Header["kid"] != "key-1" {
+		s.t.Errorf("assertion claims %v, header %v", claims, token.Header)
+	}
+
+	s.mu.Lock()
+	s.tokens++
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, `{"access_token":"access-token","expires_in":3600,"token_type":"Bearer"}`)
+}
+
+func (s *googleStub) handlePublish(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer access-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var body struct {
+		URL  string `json:"url"`
+		Type string `json:"type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.t.Errorf("invalid request body: %v", err)
+	}
+
+	s.mu.Lock()
+	s.published = append(s.published, body.URL)
+	s.types = append(s.types, body.Type)
+	s.mu.Unlock()
+	w.WriteHeader(s.publish(body.URL))
+	io.WriteString(w, `{}`)
+}
+
+// pusher writes a service account key for the stub and builds a pusher
+func (s *googleStub) pusher(dailyQuota int) SearchEnginePusher {
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(s.key)})
+	credentials, _ := json.Marshal(googleServiceAccount{
+		ClientEmail:  "pusher@example.iam.gserviceaccount.com",
+		PrivateKey:   string(keyPEM),
+		PrivateKeyID: "key-1",
+		TokenURI:     s.server.URL + "/token",
+	})
+	file := filepath.Join(s.t.TempDir(), "credentials.json")
+	if err := os.WriteFile(file, credentials, 0o600); err != nil {
+		s.t.Fatal(err)
+	}
+
+	cfg := config.SEOConfig{Google: config.GooglePushConfig{
+		Enabled: true, CredentialsFile: file, Endpoint: s.server.URL + "/publish", DailyQuota: dailyQuota,
+	}}
+	pusher, err := newGooglePusher(cfg, SearchEngineDeps{Client: s.server.Client()})
+	if err != nil || pusher == nil {
+		s.t.Fatalf("newGooglePusher() = %v, %v", pusher, err)
+	}
+	return pusher
+}
+
+func TestGooglePusher(t *testing.T) {
+	stub := newGoogleStub(t)
+	pusher := stub.pusher(200)
+
+	urls := []string{"https://example.com/a", "https://example.com/b"}
+	resp, err := pusher.Push(entity.SEOActionUpdate, urls)
+	if err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if resp.Sent != 2 {
+		t.Errorf("Sent = %d, want 2", resp.Sent)
+	}
+	if _, err := pusher.Push(entity.SEOActionDelete, urls[:1]); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(stub.published, []string{urls[0], urls[1], urls[0]}) {
+		t.Errorf("published %v", stub.published)
+	}
+	if !reflect.DeepEqual(stub.types, []string{"URL_UPDATED", "URL_UPDATED", "URL_DELETED"}) {
+		t.Errorf("notification types %v", stub.types)
+	}
+	if stub.tokens != 1 {
+		t.Errorf("fetched %d access tokens, want the first one reused", stub.tokens)
+	}
+}
+
+func TestGooglePusherPartialFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		isQuota bool
+	}{
+		{"quota exceeded", http.StatusTooManyRequests, true},
+		{"server error", http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newGoogleStub(t)
+			stub.publish = func(u string) int {
+				if strings.HasSuffix(u, "/b") {
+					return tt.status
+				}
+				return http.StatusOK
+			}
+
+			resp, err := stub.pusher(200).Push(entity.SEOActionUpdate,
+				[]string{"https://example.com/a", "https://example.com/b", "https://example.com/c"})
+			if err == nil || errors.Is(err, errQuotaExceeded) != tt.isQuota {
+				t.Fatalf("Push() error = %v", err)
+			}
+			if resp.Sent != 1 {
+				t.Errorf("Sent = %d, want 1", resp.Sent)
+			}
+			if len(stub.published) != 2 {
+				t.Errorf("published %v, want to stop after the failure", stub.published)
+			}
+		})
+	}
+}
+
+func TestGooglePusherRejectedToken(t *testing.T) {
+	stub := newGoogleStub(t)
+	pusher := stub.pusher(200)
+	if _, err := pusher.Push(entity.SEOActionUpdate, []string{"https://example.com/a"}); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	stub.publish = func(string) int { return http.StatusUnauthorized }
+	if _, err := pusher.Push(entity.SEOActionUpdate, []string{"https://example.com/a"}); err == nil {
+		t.Fatal("Push() with a rejected token succeeded")
+	}
+
+	stub.publish = func(string) int { return http.StatusOK }
+	if _, err := pusher.Push(entity.SEOActionUpdate, []string{"https://example.com/a"}); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if stub.tokens != 2 {
+		t.Errorf("fetched %d access tokens, want a new one after the rejection", stub.tokens)
+	}
+}
+
+func TestSEOServiceCarriesOverWhenQuotaIsUsed(t *testing.T) {
+	pushed := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		pushed += len(strings.Split(string(body), "\n"))
+		io.WriteString(w, `{"success":2}`)
+	}))
+	defer server.Close()
+
+	cfg := config.SEOConfig{Baidu: config.BaiduPushConfig{
+		Enabled: true, Site: "example.com", Token: "token", Endpoint: server.URL, DailyQuota: 2,
+	}}
+	pusher, _ := newBaiduPusher(cfg, SearchEngineDeps{Client: server.Client()})
+	repo := newFakeSEORepository(entity.SEOEngineBaidu, "https://example.com/a", "https://example.com/b", "https://example.com/c")
+	s := &seoService{cfg: cfg, seoRepo: repo, engines: []SearchEnginePusher{pusher}}
+
+	s.processEngine(pusher)
+	s.processEngine(pusher)
+
+	if pushed != 2 {
+		t.Errorf("pushed %d URLs, want the daily quota of 2", pushed)
+	}
+	want := []string{entity.SEOTaskSent, entity.SEOTaskSent, entity.SEOTaskPending}
+	if got := repo.statuses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("task statuses = %v, want %v", got, want)
+	}
+	if repo.quota[entity.SEOEngineBaidu] != 2 {
+		t.Errorf("quota used = %d, want 2", repo.quota[entity.SEOEngineBaidu])
+	}
+}
+
+func TestSEOServiceCarriesOverWhenEngineRefuses(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":400,"message":"over quota"}`)
+	}))
+	defer server.Close()
+
+	cfg := config.SEOConfig{Baidu: config.BaiduPushConfig{
+		Enabled: true, Site: "example.com", Token: "token", Endpoint: server.URL, DailyQuota: 100,
+	}}
+	pusher, _ := newBaiduPusher(cfg, SearchEngineDeps{Client: server.Client()})
+	repo := newFakeSEORepository(entity.SEOEngineBaidu, "https://example.com/a", "https://example.com/b")
+	s := &seoService{cfg: cfg, seoRepo: repo, engines: []SearchEnginePusher{pusher}}
+
+	s.processEngine(pusher)
+	s.processEngine(pusher)
+
+	if requests != 1 {
+		t.Errorf("sent %d requests, want none after the engine refused", requests)
+	}
+	want := []string{entity.SEOTaskPending, entity.SEOTaskPending}
+	if got := repo.statuses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("task statuses = %v, want %v", got, want)
+	}
+	for _, task := range repo.tasks {
+		if task.Attempts != 0 {
+			t.Errorf("task %d counted %d attempts, want the carry over not to count", task.ID, task.Attempts)
+		}
+	}
+	if repo.quota[entity.SEOEngineBaidu] != 100 {
+		t.Errorf("quota used = %d, want the whole quota", repo.quota[entity.SEOEngineBaidu])
+	}
+	if len(repo.history) != 1 || repo.history[0].Success {
+		t.Errorf("history = %+v, want one failed push", repo.history)
+	}
+}
+
+func TestSEOServiceRetriesOnlyUnsentURLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		want      []string
+		attempts  []int
+		quotaUsed int
+	}{
+		{
+			name:      "quota exceeded",
+			status:    http.StatusTooManyRequests,
+			want:      []string{entity.SEOTaskSent, entity.SEOTaskPending, entity.SEOTaskPending},
+			attempts:  []int{1, 0, 0},
+			quotaUsed: 100,
+		},
+		{
+			name:      "server error",
+			status:    http.StatusInternalServerError,
+			want:      []string{entity.SEOTaskSent, entity.SEOTaskPending, entity.SEOTaskPending},
+			attempts:  []int{1, 1, 1},
+			quotaUsed: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newGoogleStub(t)
+			stub.publish = func(u string) int {
+				if strings.HasSuffix(u, "/b") {
+					return tt.status
+				}
+				return http.StatusOK
+			}
+			pusher := stub.pusher(100)
+			repo := newFakeSEORepository(entity.SEOEngineGoogle, "https://example.com/a", "https://example.com/b", "https://example.com/c")
+			s := &seoService{seoRepo: repo, engines: []SearchEnginePusher{pusher}}
+
+			s.processEngine(pusher)
+
+			if got := repo.statuses(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("task statuses = %v, want %v", got, tt.want)
+			}
+			for i, task := range repo.tasks {
+				if task.Attempts != tt.attempts[i] {
+					t.Errorf("task %d attempts = %d, want %d", task.ID, task.Attempts, tt.attempts[i])
+				}
+			}
+			if repo.quota[entity.SEOEngineGoogle] != tt.quotaUsed {
+				t.Errorf("quota used = %d, want %d", repo.quota[entity.SEOEngineGoogle], tt.quotaUsed)
+			}
+			if len(stub.published) != 2 {
+				t.Errorf("published %v, want to stop at the failure", stub.published)
+			}
+
+			// The accepted URL is not sent again
+			stub.publish = func(string) int { return http.StatusOK }
+			for i := range repo.tasks {
+				repo.tasks[i].NextAttemptAt = time.Time{}
+			}
+			repo.quota[entity.SEOEngineGoogle] = 0
+			s.processEngine(pusher)
+			if got := stub.published[2:]; !reflect.DeepEqual(got, []string{"https://example.com/b", "https://example.com/c"}) {
+				t.Errorf("retried %v, want only the unsent URLs", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchStatsService aggregates internal search queries per day. Queries are
+// buffered in memory and flushed periodically; nothing about the searcher is
+// kept. Anyone can search, so the distinct queries buffered between flushes
+// are capped and old days are purged.
+type SearchStatsService interface {
+	Start(ctx context.Context)
+	Track(query string, results int64, visitor Visitor)
+}
+
+type searchKey struct {
+	day   string
+	query string
+}
+
+type searchCount struct {
+	searches    int
+	zeroResults int
+}
+
+type searchStatsService struct {
+	searchRepo    repository.SearchStatRepository
+	flushInterval time.Duration
+	maxQueries    int
+	retention     time.Duration // 0 keeps stats forever
+
+	mu      sync.Mutex
+	pending map[searchKey]*searchCount
+}
+
+func NewSearchStatsService(searchRepo repository.SearchStatRepository, cfg config.AnalyticsConfig) SearchStatsService {
+	interval, err := time.ParseDuration(cfg.ViewFlushInterval)
+	if err != nil {
+		interval = 30 * time.Second
+	}
+
+	maxQueries := cfg.MaxSearchQueries
+	if maxQueries <= 0 {
+		maxQueries = 1000
+	}
+
+	return &searchStatsService{
+		searchRepo:    searchRepo,
+		flushInterval: interval,
+		maxQueries:    maxQueries,
+		retention:     analyticsRetention(cfg),
+		pending:       make(map[searchKey]*searchCount),
+	}
+}
+
+// Start flushes buffered searches periodically until ctx is cancelled
+func (s *searchStatsService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	purge := time.NewTicker(analyticsPurgeInterval)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flush()
+			return
+		case <-ticker.C:
+			s.flush()
+		case <-purge.C:
+			if s.retention > 0 {
+				if _, err := s.searchRepo.PurgeBefore(time.Now().Add(-s.retention)); err != nil {
+					log.Printf("Failed to purge search stats: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Track counts a search, ignoring bots and single-character queries. New
+// queries are dropped once maxQueries are buffered until the next flush.
+func (s *searchStatsService) Track(query string, results int64, visitor Visitor) {
+	if visitor.IsBot() {
+		return
+	}
+
+	query = normalizeQuery(query)
+	if len([]rune(query)) < 2 {
+		return
+	}
+
+	key := searchKey{day: time.Now().UTC().Format("2006-01-02"), query: query}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count, ok := s.pending[key]
+	if !ok {
+		if len(s.pending) >= s.maxQueries {
+			return
+		}
+		count = &searchCount{}
+		s.pending[key] = count
+	}
+	count.searches++
+	if results == 0 {
+		count.zeroResults++
+	}
+}
+
+func (s *searchStatsService) flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[searchKey]*searchCount)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	stats := make([]entity.SearchQueryStat, 0, len(pending))
+	for key, count := range pending {
+		day, _ := time.Parse("2006-01-02", key.day)
+		stats = append(stats, entity.SearchQueryStat{
+			Day:         day,
+			Query:       key.query,
+			Searches:    count.searches,
+			ZeroResults: count.zeroResults,
+		})
+	}
+
+	if err := s.searchRepo.AddSearches(stats); err != nil {
+		log.Printf("Failed to flush %d search stats: %v", len(stats), err)
+
+		// Put the searches back so the next flush retries them, within the
+		// same cap as new queries
+		s.mu.Lock()
+		for key, count := range pending {
+			if existing, ok := s.pending[key]; ok {
+				existing.searches += count.searches
+				existing.zeroResults += count.zeroResults
+			} else if len(s.pending) < s.maxQueries {
+				s.pending[key] = count
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// normalizeQuery lowercases a query and collapses whitespace
+func normalizeQuery(query string) string {
+	return truncateRunes(strings.Join(strings.Fields(strings.ToLower(query)), " "), 100)
+}
@@ -1,209 +1,409 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"strings"
 	"time"
 
 	"backend/config"
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
 	"backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// pushBatchSize is the most URLs sent in a single push request
+	pushBatchSize = 100
+	// maxPushAttempts is how often a URL is tried before it is marked failed
+	maxPushAttempts = 5
+	// pushRetryDelay is the delay before the first retry, doubled per attempt
+	pushRetryDelay = 5 * time.Minute
 )
 
+var (
+	ErrSEODisabled        = errors.New("SEO push is not configured")
+	ErrSEOHistoryNotFound = errors.New("push history not found")
+)
+
+// SEOService pushes post URLs to search engines when posts are published,
+// updated or removed. URLs are queued in the database and sent within each
+// engine's daily quota; whatever doesn't fit is carried over to the next
+// day. Every push request and its response is kept as history.
 type SEOService interface {
 	Start(ctx context.Context)
-	PushAllURLs() error
-	PushURL(url string) error
+	HandlePostEvent(event PostEvent)
+	EnqueueAll() (int, error)
+	Repush(historyID int64) (int, error)
+	GetStatus() (*dto.SEOStatusResponse, error)
+	GetHistory(query dto.SEOHistoryQuery) (*dto.SEOHistoryResponse, error)
 }
 
 type seoService struct {
 	cfg      config.SEOConfig
 	postRepo repository.PostRepository
-	client   *http.Client
+	seoRepo  repository.SEORepository
+	engines  []SearchEnginePusher
+	wake     chan struct{}
 }
 
-func NewSEOService(cfg config.SEOConfig, postRepo repository.PostRepository) SEOService {
+// NewSEOService pushes to the given engines, see NewSearchEnginePushers
+func NewSEOService(cfg config.SEOConfig, postRepo repository.PostRepository, seoRepo repository.SEORepository, engines []SearchEnginePusher) SEOService {
 	return &seoService{
 		cfg:      cfg,
 		postRepo: postRepo,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		seoRepo:  seoRepo,
+		engines:  engines,
+		wake:     make(chan struct{}, 1),
 	}
 }
 
-// Start begins the scheduled URL pushing
+// Start processes the push queue periodically, and right away when new
+// URLs are queued, until ctx is cancelled
 func (s *seoService) Start(ctx context.Context) {
 	if s.cfg.SiteURL == "" {
 		log.Println("SEO service disabled: SEO_SITE_URL not configured")
 		return
 	}
+	if len(s.engines) == 0 {
+		log.Println("SEO service disabled: no search engine configured")
+		return
+	}
 
 	interval, err := time.ParseDuration(s.cfg.PushInterval)
 	if err != nil {
-		interval = 24 * time.Hour
+		interval = 5 * time.Minute
 	}
 
-	log.Printf("SEO service started, pushing URLs every %s", interval)
-
-	// Initial push
-	go func() {
-		time.Sleep(10 * time.Second) // Wait for server to fully start
-		if err := s.PushAllURLs(); err != nil {
-			log.Printf("Initial SEO push failed: %v", err)
-		}
-	}()
+	log.Printf("SEO service started, processing the push queue every %s", interval)
 
-	// Scheduled push
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	s.processQueue()
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("SEO service stopped")
 			return
 		case <-ticker.C:
-			if err := s.PushAllURLs(); err != nil {
-				log.Printf("Scheduled SEO push failed: %v", err)
-			}
+			s.processQueue()
+		case <-s.wake:
+			s.processQueue()
 		}
 	}
 }
 
-// PushAllURLs fetches latest published posts and pushes their URLs
-func (s *seoService) PushAllURLs() error {
-	log.Println("Starting SEO URL push...")
+// HandlePostEvent queues the URL of a changed post. Posts marked noindex
+// are left out of the sitemap, so they are not announced either, and a live
+// post that becomes noindex is removed.
+func (s *seoService) HandlePostEvent(event PostEvent) {
+	if len(s.engines) == 0 {
+		return
+	}
 
-	// Only push latest 5 posts to avoid exceeding Baidu daily quota
-	posts, _, err := s.postRepo.FindAll(1, 5, "", "", "published")
-	if err != nil {
-		return fmt.Errorf("failed to fetch posts: %w", err)
+	action := entity.SEOActionUpdate
+	switch {
+	case event.Type == PostUnpublished || event.Type == PostDeleted:
+		action = entity.SEOActionDelete
+	case event.NoIndex && event.Type == PostUpdated && !event.PreviousNoIndex:
+		action = entity.SEOActionDelete
+	case event.NoIndex:
+		action = ""
 	}
 
-	var urls []string
-	// Add homepage
-	urls = append(urls, s.cfg.SiteURL)
-	// Add about page
-	urls = append(urls, s.cfg.SiteURL+"/?view=about")
-	// Add latest post URLs
-	for _, post := range posts {
-		urls = append(urls, fmt.Sprintf("%s/?post=%s", s.cfg.SiteURL, post.ID))
+	url := postURL(s.cfg.SiteURL, event.Slug)
+	if action != "" {
+		if err := s.enqueue(s.engines, action, []string{url}); err != nil {
+			log.Printf("Failed to queue %s for search engines: %v", url, err)
+		}
 	}
 
-	log.Printf("Pushing %d URLs to search engines", len(urls))
+	// The URL under the old slug is gone
+	if event.PreviousSlug != "" && event.Type != PostPublished {
+		oldURL := postURL(s.cfg.SiteURL, event.PreviousSlug)
+		if err := s.enqueue(s.engines, entity.SEOActionDelete, []string{oldURL}); err != nil {
+			log.Printf("Failed to queue %s for search engines: %v", oldURL, err)
+		}
+	}
+}
 
-	// Push to Baidu
-	if s.cfg.BaiduToken != "" && s.cfg.BaiduSite != "" {
-		if err := s.pushToBaidu(urls); err != nil {
-			log.Printf("Baidu push failed: %v", err)
-		} else {
-			log.Println("Baidu push successful")
+// EnqueueAll queues the homepage, the about page and every published post
+func (s *seoService) EnqueueAll() (int, error) {
+	if len(s.engines) == 0 {
+		return 0, ErrSEODisabled
+	}
+
+	urls := []string{homeURL(s.cfg.SiteURL), aboutURL(s.cfg.SiteURL)}
+	for offset := 0; ; offset += 1000 {
+		posts, err := s.postRepo.FindPublishedSitemapEntries(offset, 1000)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch posts: %w", err)
+		}
+		for _, post := range posts {
+			urls = append(urls, postURL(s.cfg.SiteURL, post.Slug))
+		}
+		if len(posts) < 1000 {
+			break
 		}
 	}
 
-	// Push to Bing (IndexNow)
-	if s.cfg.BingAPIKey != "" {
-		if err := s.pushToBing(urls); err != nil {
-			log.Printf("Bing push failed: %v", err)
-		} else {
-			log.Println("Bing push successful")
+	if err := s.enqueue(s.engines, entity.SEOActionUpdate, urls); err != nil {
+		return 0, err
+	}
+	return len(urls), nil
+}
+
+// Repush queues the URLs of an earlier push again for the same engine
+func (s *seoService) Repush(historyID int64) (int, error) {
+	history, err := s.seoRepo.FindHistoryByID(historyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrSEOHistoryNotFound
 		}
+		return 0, err
 	}
 
-	log.Println("SEO URL push completed")
-	return nil
+	engine, ok := s.engine(history.Engine)
+	if !ok {
+		return 0, ErrSEODisabled
+	}
+
+	urls := splitURLs(history.URLs)
+	if err := s.enqueue([]SearchEnginePusher{engine}, history.Action, urls); err != nil {
+		return 0, err
+	}
+	return len(urls), nil
 }
 
-// PushURL pushes a single URL to all configured search engines
-func (s *seoService) PushURL(url string) error {
-	urls := []string{url}
+func (s *seoService) GetStatus() (*dto.SEOStatusResponse, error) {
+	pending, err := s.seoRepo.CountPendingByEngine()
+	if err != nil {
+		return nil, err
+	}
 
-	if s.cfg.BaiduToken != "" && s.cfg.BaiduSite != "" {
-		if err := s.pushToBaidu(urls); err != nil {
-			log.Printf("Baidu push failed for %s: %v", url, err)
+	response := &dto.SEOStatusResponse{Engines: make([]dto.SEOEngineStatus, 0, len(s.engines))}
+	for _, engine := range s.engines {
+		used, err := s.seoRepo.QuotaUsed(engine.Name(), time.Now())
+		if err != nil {
+			return nil, err
 		}
+		response.Engines = append(response.Engines, dto.SEOEngineStatus{
+			Engine:     engine.Name(),
+			DailyQuota: engine.DailyQuota(),
+			UsedToday:  used,
+			Pending:    pending[engine.Name()],
+		})
+	}
+	return response, nil
+}
+
+func (s *seoService) GetHistory(query dto.SEOHistoryQuery) (*dto.SEOHistoryResponse, error) {
+	history, total, err := s.seoRepo.FindHistory(query.Page, query.PageSize, query.Engine)
+	if err != nil {
+		return nil, err
 	}
 
-	if s.cfg.BingAPIKey != "" {
-		if err := s.pushToBing(urls); err != nil {
-			log.Printf("Bing push failed for %s: %v", url, err)
+	items := make([]dto.SEOPushHistoryItem, len(history))
+	for i, h := range history {
+		items[i] = dto.SEOPushHistoryItem{
+			ID:         h.ID,
+			Engine:     h.Engine,
+			Action:     h.Action,
+			URLs:       splitURLs(h.URLs),
+			StatusCode: h.StatusCode,
+			Success:    h.Success,
+			Response:   h.Response,
+			CreatedAt:  h.CreatedAt,
 		}
 	}
 
-	return nil
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize > 0 {
+		totalPages++
+	}
+
+	return &dto.SEOHistoryResponse{
+		Items:      items,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: totalPages,
+	}, nil
 }
 
-// pushToBaidu pushes URLs to Baidu webmaster API
-// API: http://data.zz.baidu.com/urls?site=xxx&token=xxx
-func (s *seoService) pushToBaidu(urls []string) error {
-	apiURL := fmt.Sprintf("http://data.zz.baidu.com/urls?site=%s&token=%s", s.cfg.BaiduSite, s.cfg.BaiduToken)
-	body := strings.Join(urls, "\n")
+func (s *seoService) enqueue(engines []SearchEnginePusher, action string, urls []string) error {
+	now := time.Now()
+	tasks := make([]entity.SEOPushTask, 0, len(engines)*len(urls))
+	for _, engine := range engines {
+		for _, url := range urls {
+			tasks = append(tasks, entity.SEOPushTask{
+				Engine:        engine.Name(),
+				URL:           url,
+				Action:        action,
+				Status:        entity.SEOTaskPending,
+				NextAttemptAt: now,
+			})
+		}
+	}
 
-	req, err := http.NewRequest("POST", apiURL, strings.NewReader(body))
-	if err != nil {
+	if err := s.seoRepo.Enqueue(tasks); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "text/plain")
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return err
+	select {
+	case s.wake <- struct{}{}:
+	default:
 	}
-	defer resp.Body.Close()
+	return nil
+}
 
-	respBody, _ := io.ReadAll(resp.Body)
+func (s *seoService) engine(name string) (SearchEnginePusher, bool) {
+	for _, engine := range s.engines {
+		if engine.Name() == name {
+			return engine, true
+		}
+	}
+	return nil, false
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("baidu API returned %d: %s", resp.StatusCode, string(respBody))
+func (s *seoService) processQueue() {
+	for _, engine := range s.engines {
+		s.processEngine(engine)
 	}
+}
 
-	log.Printf("Baidu response: %s", string(respBody))
-	return nil
+// processEngine sends due URLs in batches until the queue is drained, a
+// push fails or today's quota is used up
+func (s *seoService) processEngine(engine SearchEnginePusher) {
+	for {
+		now := time.Now()
+
+		used, err := s.seoRepo.QuotaUsed(engine.Name(), now)
+		if err != nil {
+			log.Printf("Failed to read %s push quota: %v", engine.Name(), err)
+			return
+		}
+
+		limit := min(engine.DailyQuota()-used, pushBatchSize)
+		if limit <= 0 {
+			return
+		}
+
+		tasks, err := s.seoRepo.FindDueTasks(engine.Name(), now, limit)
+		if err != nil {
+			log.Printf("Failed to fetch %s push queue: %v", engine.Name(), err)
+			return
+		}
+
+		byAction := make(map[string][]entity.SEOPushTask)
+		for _, task := range tasks {
+			byAction[task.Action] = append(byAction[task.Action], task)
+		}
+		for action, batch := range byAction {
+			if !s.pushBatch(engine, action, batch, now) {
+				return
+			}
+		}
+
+		if len(tasks) < limit {
+			return
+		}
+	}
 }
 
-// pushToBing pushes URLs using IndexNow protocol
-// https://www.indexnow.org/documentation
-func (s *seoService) pushToBing(urls []string) error {
-	// Parse host from site URL
-	host := strings.TrimPrefix(s.cfg.SiteURL, "https://")
-	host = strings.TrimPrefix(host, "http://")
-	host = strings.Split(host, "/")[0]
+// pushBatch sends tasks to an engine and records the outcome. It reports
+// whether processing should continue.
+func (s *seoService) pushBatch(engine SearchEnginePusher, action string, tasks []entity.SEOPushTask, now time.Time) bool {
+	ids := make([]int64, len(tasks))
+	urls := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+		urls[i] = task.URL
+	}
 
-	payload := map[string]interface{}{
-		"host":        host,
-		"key":         s.cfg.BingAPIKey,
-		"keyLocation": fmt.Sprintf("%s/%s.txt", s.cfg.SiteURL, s.cfg.BingAPIKey),
-		"urlList":     urls,
+	resp, err := engine.Push(action, urls)
+
+	history := &entity.SEOPushHistory{
+		Engine:   engine.Name(),
+		Action:   action,
+		URLs:     strings.Join(urls, "\n"),
+		URLCount: len(urls),
+		Success:  err == nil,
+	}
+	if resp != nil {
+		history.StatusCode = resp.StatusCode
+		history.Response = truncateRunes(resp.Body, 2000)
+	}
+	if err != nil && history.Response == "" {
+		history.Response = err.Error()
+	}
+	if herr := s.seoRepo.CreateHistory(history); herr != nil {
+		log.Printf("Failed to record %s push history: %v", engine.Name(), herr)
 	}
 
-	jsonBody, err := json.Marshal(payload)
-	if err != nil {
-		return err
+	// URLs accepted before an error are done, only the others are retried
+	if err != nil && resp != nil && resp.Sent > 0 {
+		sent := min(resp.Sent, len(ids))
+		if !s.markSent(engine, ids[:sent], now) {
+			return false
+		}
+		ids, tasks = ids[sent:], tasks[sent:]
+		if len(ids) == 0 {
+			return false
+		}
 	}
 
-	req, err := http.NewRequest("POST", "https://api.indexnow.org/indexnow", bytes.NewReader(jsonBody))
-	if err != nil {
-		return err
+	if errors.Is(err, errQuotaExceeded) {
+		// Leave the URLs pending for tomorrow
+		log.Printf("%s push quota exhausted, %d URLs carried over", engine.Name(), len(ids))
+		if err := s.seoRepo.RaiseQuotaUsed(engine.Name(), now, engine.DailyQuota()); err != nil {
+			log.Printf("Failed to update %s push quota: %v", engine.Name(), err)
+		}
+		return false
 	}
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
-	resp, err := s.client.Do(req)
 	if err != nil {
-		return err
+		log.Printf("%s push of %d URLs failed: %v", engine.Name(), len(ids), err)
+		retryAt := now.Add(pushRetryDelay << min(tasks[0].Attempts, maxPushAttempts))
+		if err := s.seoRepo.MarkTasksFailed(ids, err.Error(), retryAt, maxPushAttempts); err != nil {
+			log.Printf("Failed to reschedule %s push: %v", engine.Name(), err)
+		}
+		return false
 	}
-	defer resp.Body.Close()
 
-	// IndexNow returns 200, 202, or 400/422 for errors
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("IndexNow API returned %d: %s", resp.StatusCode, string(respBody))
+	log.Printf("%s push of %d URLs successful", engine.Name(), len(urls))
+	if !s.markSent(engine, ids, now) {
+		return false
+	}
+	if resp.Remaining >= 0 {
+		if err := s.seoRepo.RaiseQuotaUsed(engine.Name(), now, engine.DailyQuota()-resp.Remaining); err != nil {
+			log.Printf("Failed to update %s push quota: %v", engine.Name(), err)
+		}
 	}
+	return true
+}
 
-	return nil
+// markSent records tasks as sent and counts them against today's quota
+func (s *seoService) markSent(engine SearchEnginePusher, ids []int64, now time.Time) bool {
+	if err := s.seoRepo.MarkTasksSent(ids); err != nil {
+		log.Printf("Failed to mark %s push as sent: %v", engine.Name(), err)
+		return false
+	}
+	if err := s.seoRepo.AddQuotaUsed(engine.Name(), now, len(ids)); err != nil {
+		log.Printf("Failed to update %s push quota: %v", engine.Name(), err)
+		return false
+	}
+	return true
+}
+
+func splitURLs(urls string) []string {
+	if urls == "" {
+		return []string{}
+	}
+	return strings.Split(urls, "\n")
 }
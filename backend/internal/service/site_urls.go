@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// The public page URLs of the frontend, shared by feeds, the sitemap and
+// search engine pushes.
+
+func homeURL(siteURL string) string {
+	return strings.TrimSuffix(siteURL, "/") + "/"
+}
+
+func aboutURL(siteURL string) string {
+	return strings.TrimSuffix(siteURL, "/") + "/?view=about"
+}
+
+// postURL is the server-rendered page of a post, which crawlers get as is
+// and browsers are redirected from to the SPA
+func postURL(siteURL, slug string) string {
+	return fmt.Sprintf("%s/p/%s", strings.TrimSuffix(siteURL, "/"), url.PathEscape(slug))
+}
+
+// spaPostURL is the page of a post in the SPA
+func spaPostURL(siteURL string, postID uuid.UUID) string {
+	return fmt.Sprintf("%s/?post=%s", strings.TrimSuffix(siteURL, "/"), postID)
+}
+
+// ogImageURL is the generated share image of a post. It stays the same
+// across edits, the image itself follows the post.
+func ogImageURL(siteURL string, postID uuid.UUID) string {
+	return fmt.Sprintf("%s/og/posts/%s.png", strings.TrimSuffix(siteURL, "/"), postID)
+}
+
+func tagURL(siteURL, slug string) string {
+	return fmt.Sprintf("%s/?tag=%s", strings.TrimSuffix(siteURL, "/"), url.QueryEscape(slug))
+}
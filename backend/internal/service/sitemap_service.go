@@ -0,0 +1,170 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/repository"
+	"backend/pkg/sitemap"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+// SitemapService builds the sitemap from all published posts and tag pages,
+// and the robots.txt that points crawlers to it.
+//
+// Up to sitemap.MaxURLs URLs are served as a single /sitemap.xml. Beyond
+// that /sitemap.xml becomes a sitemap index of /sitemaps/{n}.xml files.
+type SitemapService interface {
+	GetSitemap(siteURL string) ([]byte, error)
+	GetSitemapPage(siteURL string, page int) ([]byte, error)
+	GetRobots(siteURL string) string
+}
+
+type sitemapService struct {
+	postRepo repository.PostRepository
+	tagRepo  repository.TagRepository
+	disallow []string
+	robots   string // Custom robots.txt, empty to generate one
+}
+
+func NewSitemapService(cfg config.SEOConfig, postRepo repository.PostRepository, tagRepo repository.TagRepository) SitemapService {
+	s := &sitemapService{
+		postRepo: postRepo,
+		tagRepo:  tagRepo,
+	}
+
+	for _, path := range strings.Split(cfg.RobotsDisallow, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			s.disallow = append(s.disallow, path)
+		}
+	}
+
+	if cfg.RobotsFile != "" {
+		content, err := os.ReadFile(cfg.RobotsFile)
+		if err != nil {
+			log.Printf("Failed to read robots file %s, using the generated robots.txt: %v", cfg.RobotsFile, err)
+		} else {
+			s.robots = string(content)
+		}
+	}
+
+	return s
+}
+
+func (s *sitemapService) GetSitemap(siteURL string) ([]byte, error) {
+	fixed, postCount, err := s.count(siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(fixed) + int(postCount)
+	if total <= sitemap.MaxURLs {
+		return s.page(siteURL, fixed, 1)
+	}
+
+	pages := (total + sitemap.MaxURLs - 1) / sitemap.MaxURLs
+	locs := make([]string, pages)
+	for i := range locs {
+		locs[i] = fmt.Sprintf("%s/sitemaps/%d.xml", strings.TrimSuffix(siteURL, "/"), i+1)
+	}
+	return sitemap.Index(locs)
+}
+
+func (s *sitemapService) GetSitemapPage(siteURL string, page int) ([]byte, error) {
+	fixed, postCount, err := s.count(siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(fixed) + int(postCount)
+	if page < 1 || (page-1)*sitemap.MaxURLs >= total {
+		return nil, ErrSitemapNotFound
+	}
+	return s.page(siteURL, fixed, page)
+}
+
+func (s *sitemapService) GetRobots(siteURL string) string {
+	sitemapLine := fmt.Sprintf("Sitemap: %s/sitemap.xml\n", strings.TrimSuffix(siteURL, "/"))
+
+	if s.robots != "" {
+		if strings.Contains(strings.ToLower(s.robots), "sitemap:") {
+			return s.robots
+		}
+		return strings.TrimRight(s.robots, "\n") + "\n\n" + sitemapLine
+	}
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if len(s.disallow) == 0 {
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range s.disallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\n" + sitemapLine)
+	return b.String()
+}
+
+// count returns the URLs listed before the posts (home, about and tag
+// pages) and the number of published posts.
+func (s *sitemapService) count(siteURL string) ([]sitemap.URL, int64, error) {
+	tags, err := s.tagRepo.FindSitemapEntries()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	postCount, err := s.postRepo.CountPublished()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fixed := []sitemap.URL{
+		{Loc: homeURL(siteURL), ChangeFreq: "daily", Priority: 1.0},
+		{Loc: aboutURL(siteURL), ChangeFreq: "monthly", Priority: 0.6},
+	}
+	for _, tag := range tags {
+		fixed = append(fixed, sitemap.URL{
+			Loc:        tagURL(siteURL, tag.Slug),
+			LastMod:    tag.UpdatedAt,
+			ChangeFreq: "weekly",
+			Priority:   0.5,
+		})
+	}
+
+	return fixed, postCount, nil
+}
+
+// page renders the URLs [(page-1)*MaxURLs, page*MaxURLs) of the fixed URLs
+// followed by all published posts.
+func (s *sitemapService) page(siteURL string, fixed []sitemap.URL, page int) ([]byte, error) {
+	start := (page - 1) * sitemap.MaxURLs
+	end := start + sitemap.MaxURLs
+
+	var urls []sitemap.URL
+	if start < len(fixed) {
+		urls = append(urls, fixed[start:min(end, len(fixed))]...)
+	}
+
+	postStart := max(start-len(fixed), 0)
+	postEnd := end - len(fixed)
+	if postEnd > postStart {
+		posts, err := s.postRepo.FindPublishedSitemapEntries(postStart, postEnd-postStart)
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range posts {
+			urls = append(urls, sitemap.URL{
+				Loc:        postURL(siteURL, post.Slug),
+				LastMod:    post.UpdatedAt,
+				ChangeFreq: "monthly",
+				Priority:   0.8,
+			})
+		}
+	}
+
+	return sitemap.URLSet(urls)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/repository"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StatsService interface {
+	GetOverview(query dto.StatsQuery) (*dto.StatsOverviewResponse, error)
+	GetViewSeries(query dto.ViewSeriesQuery) (*dto.ViewSeriesResponse, error)
+	GetTopPosts(query dto.StatsQuery) (*dto.TopPostsResponse, error)
+	GetTopReferrers(query dto.StatsQuery) (*dto.TopReferrersResponse, error)
+	GetTopTags(query dto.StatsQuery) (*dto.TopTagsResponse, error)
+	GetAIUsage(query dto.StatsQuery) (*dto.AIUsageResponse, error)
+	GetPostSources(postID string, query dto.StatsQuery) (*dto.PostSourcesResponse, error)
+	GetSearchTerms(query dto.StatsQuery, zeroResultsOnly bool) (*dto.SearchTermsResponse, error)
+}
+
+// ErrInvalidStatsRange is returned for a range that is not "all", "<n>h" or "<n>d"
+var ErrInvalidStatsRange = errors.New("invalid range, expected e.g. 24h, 7d, 30d or all")
+
+type statsService struct {
+	statsRepo repository.StatsRepository
+}
+
+func NewStatsService(statsRepo repository.StatsRepository) StatsService {
+	return &statsService{statsRepo: statsRepo}
+}
+
+func (s *statsService) GetOverview(query dto.StatsQuery) (*dto.StatsOverviewResponse, error) {
+	since, statsRange, err := parseStatsRange(query.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.statsRepo.CountPosts(since)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.statsRepo.CountComments(since)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := s.statsRepo.TotalViews(since)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.StatsOverviewResponse{
+		StatsRange: statsRange,
+		Posts: dto.PostStats{
+			Total:          posts.Total,
+			Published:      posts.Published,
+			Draft:          posts.Draft,
+			CreatedInRange: posts.CreatedInRange,
+		},
+		Comments: dto.CommentStats{
+			Total:         comments.Total,
+			Active:        comments.Active,
+			Deleted:       comments.Deleted,
+			AwaitingReply: comments.AwaitingReply,
+		},
+		Views: views,
+	}, nil
+}
+
+func (s *statsService) GetViewSeries(query dto.ViewSeriesQuery) (*dto.ViewSeriesResponse, error) {
+	since, statsRange, err := parseStatsRange(query.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.statsRepo.ViewSeries(since, query.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]dto.ViewPoint, len(rows))
+	for i, row := range rows {
+		points[i] = dto.ViewPoint{
+			Date:  row.Period.Format("2006-01-02"),
+			Views: row.Views,
+		}
+	}
+
+	return &dto.ViewSeriesResponse{
+		StatsRange: statsRange,
+		Interval:   query.Interval,
+		Points:     points,
+	}, nil
+}
+
+func (s *statsService) GetTopPosts(query dto.StatsQuery) (*dto.TopPostsResponse, error) {
+	since, statsRange, err := parseStatsRange(query.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.statsRepo.TopPosts(since, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]dto.TopPostItem, len(rows))
+	for i, row := range rows {
+		posts[i] = dto.TopPostItem{
+			PostID: row.PostID.String(),
+			Title:  row.Title,
+			Views:  row.Views,
+		}
+	}
+
+	return &dto.TopPostsResponse{StatsRange: statsRange, Posts: posts}, nil
+}
+
+func (s *statsService) GetTopReferrers(query dto.StatsQuery) (*dto.TopReferrersResponse, error) {
+	since, statsRange, err := parseStatsRange(query.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.statsRepo.TopReferrers(since, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	referrers := make([]dto.ReferrerItem, len(rows))
+	for i, row := range rows {
+		referrers[i] = dto.ReferrerItem{Host: row.Host, Views: row.Views}
+	}
+
+	return &dto.TopReferrersResponse{StatsRange: statsRange, Referrers: referrers}, nil
+}
+
+func (s *statsService) GetTopTags(query dto.StatsQuery) (*dto.TopTagsResponse, error) {
+	since, statsRange, err := parseStatsRange(query.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.statsRepo.TopTags(since, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]dto.TagViewsItem, len(rows))
+	for i, row := range rows {
+		tags[i] = dto.TagViewsItem{Name: row.Name, Slug: row.Slug, Views: row.Views}
+	}
+
+	return &dto.TopTagsResponse{StatsRange: statsRange, Tags: tags}, nil
+}
+
+func (s *statsService) GetAIUsage(query dto.StatsQuery) (*dto.AIUsageResponse, error) {
+	since, statsRange, err := parseStatsRange(query.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.statsRepo.AIUsage(since)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AIUsageResponse{
+		StatsRange: statsRange,
+		Operations: make([]dto.AIUsageItem, len(rows)),
+	}
+	for i, row := range rows {
+		response.Operations[i] = dto.AIUsageItem{
+			Operation:     row.Operation,
+			Provider:      row.Provider,
+			Calls:         row.Calls,
+			Failures:      row.Failures,
+			AvgDurationMs: row.AvgDurationMs,
+		}
+		response.TotalCalls += row.Calls
+		response.TotalFailures += row.Failures
+	}
+
+	return response, nil
+}
+
+func (s *statsService) GetPostSources(postID string, query dto.StatsQuery) (*dto.PostSourcesResponse, error) {
+	pID, err := uuid.Parse(postID)
+	if err != nil {
+		return nil, errors.New("invalid post ID")
+	}
+
+	since, statsRange, err := parseStatsRange(query.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.statsRepo.TopSources(pID, since, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]dto.TrafficSourceItem, len(rows))
+	for i, row := range rows {
+		sources[i] = dto.TrafficSourceItem{
+			ReferrerHost: row.ReferrerHost,
+			UTMSource:    row.UTMSource,
+			UTMMedium:    row.UTMMedium,
+			UTMCampaign:  row.UTMCampaign,
+			Visits:       row.Visits,
+		}
+	}
+
+	return &dto.PostSourcesResponse{
+		StatsRange: statsRange,
+		PostID:     pID.String(),
+		Sources:    sources,
+	}, nil
+}
+
+func (s *statsService) GetSearchTerms(query dto.StatsQuery, zeroResultsOnly bool) (*dto.SearchTermsResponse, error) {
+	since, statsRange, err := parseStatsRange(query.Range)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.statsRepo.TopSearches(since, query.Limit, zeroResultsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	searches := make([]dto.SearchTermItem, len(rows))
+	for i, row := range rows {
+		searches[i] = dto.SearchTermItem{
+			Query:       row.Query,
+			Searches:    row.Searches,
+			ZeroResults: row.ZeroResults,
+		}
+	}
+
+	return &dto.SearchTermsResponse{StatsRange: statsRange, Searches: searches}, nil
+}
+
+// parseStatsRange converts a range such as "24h", "7d" or "all" into the
+// start time of the window. "all" starts at the zero time.
+func parseStatsRange(value string) (time.Time, dto.StatsRange, error) {
+	if value == "all" {
+		return time.Time{}, dto.StatsRange{Range: value}, nil
+	}
+
+	if len(value) < 2 {
+		return time.Time{}, dto.StatsRange{}, ErrInvalidStatsRange
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 || n > 3650 {
+		return time.Time{}, dto.StatsRange{}, ErrInvalidStatsRange
+	}
+
+	now := time.Now()
+	var since time.Time
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "h":
+		since = now.Add(-time.Duration(n) * time.Hour)
+	case "d":
+		since = now.AddDate(0, 0, -n)
+	default:
+		return time.Time{}, dto.StatsRange{}, ErrInvalidStatsRange
+	}
+
+	return since, dto.StatsRange{Range: value, Since: &since}, nil
+}
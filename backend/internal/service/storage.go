@@ -0,0 +1,50 @@
+package service
+
+import (
+	"backend/config"
+	"context"
+	"fmt"
+	"io"
+)
+
+// uploadCacheControl is sent with stored files. Keys are never reused, so
+// browsers and CDNs may keep them forever.
+const uploadCacheControl = "public, max-age=31536000, immutable"
+
+// Storage keeps uploaded files under keys like
+// "uploads/2024/11/20/{uuid}.png" and tells their public URL
+type Storage interface {
+	Name() string
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// StorageFactory builds a storage driver from the storage config
+type StorageFactory func(cfg config.StorageConfig) (Storage, error)
+
+// storageDrivers are the known drivers by STORAGE_DRIVER name
+var storageDrivers = map[string]StorageFactory{
+	config.StorageLocal: newLocalStorage,
+	config.StorageS3:    newS3Storage,
+	config.StorageOSS:   newOSSStorage,
+}
+
+// RegisterStorage adds a storage driver. It must be called before
+// NewStorage.
+func RegisterStorage(name string, factory StorageFactory) {
+	storageDrivers[name] = factory
+}
+
+// NewStorage builds the driver selected by the config
+func NewStorage(cfg config.StorageConfig) (Storage, error) {
+	factory, ok := storageDrivers[cfg.Driver]
+	if !ok {
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+	storage, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s storage: %w", cfg.Driver, err)
+	}
+	return storage, nil
+}
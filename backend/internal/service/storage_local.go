@@ -0,0 +1,93 @@
+package service
+
+import (
+	"backend/config"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// localStorage writes files below a directory, whose uploads directory the
+// router serves at /uploads/. It needs no external service, for self-hosted
+// and offline instances.
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+func newLocalStorage(cfg config.StorageConfig) (Storage, error) {
+	dir, err := filepath.Abs(cfg.Local.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &localStorage{dir: dir, baseURL: cfg.Local.BaseURL}, nil
+}
+
+func (s *localStorage) Name() string {
+	return config.StorageLocal
+}
+
+// Put writes to a temporary file first, so a failed upload never leaves a
+// partial file at the key
+func (s *localStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	filename, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	filename, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// URL is relative to the site when no base URL is configured
+func (s *localStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// path maps a key to a file below the directory, refusing keys that would
+// leave it
+func (s *localStorage) path(key string) (string, error) {
+	if key == "" || path.Clean(key) != key || strings.HasPrefix(key, "/") || strings.HasPrefix(key, "..") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"backend/config"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// ossStorage puts files into an Alibaba Cloud OSS bucket
+type ossStorage struct {
+	bucket  *oss.Bucket
+	baseURL string
+}
+
+func newOSSStorage(cfg config.StorageConfig) (Storage, error) {
+	client, err := oss.New(cfg.OSS.Endpoint, cfg.OSS.AccessKeyID, cfg.OSS.AccessKeySecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OSS client: %w", err)
+	}
+
+	bucket, err := client.Bucket(cfg.OSS.BucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bucket: %w", err)
+	}
+
+	// Fall back to the bucket URL
+	baseURL := strings.TrimSuffix(cfg.OSS.BaseURL, "/")
+	if baseURL == "" {
+		endpoint := strings.TrimPrefix(strings.TrimPrefix(cfg.OSS.Endpoint, "https://"), "http://")
+		baseURL = fmt.Sprintf("https://%s.%s", cfg.OSS.BucketName, endpoint)
+	}
+
+	return &ossStorage{bucket: bucket, baseURL: baseURL}, nil
+}
+
+func (s *ossStorage) Name() string {
+	return config.StorageOSS
+}
+
+func (s *ossStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	options := []oss.Option{
+		oss.WithContext(ctx),
+		oss.ContentType(contentType),
+		oss.CacheControl(uploadCacheControl),
+	}
+	if err := s.bucket.PutObject(key, body, options...); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
+
+func (s *ossStorage) Delete(ctx context.Context, key string) error {
+	return s.bucket.DeleteObject(key, oss.WithContext(ctx))
+}
+
+func (s *ossStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"backend/config"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Storage puts files into a bucket of an S3 compatible service, like
+// MinIO, Cloudflare R2 or AWS S3
+type s3Storage struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+func newS3Storage(cfg config.StorageConfig) (Storage, error) {
+	s3 := cfg.S3
+	lookup := minio.BucketLookupAuto
+	if s3.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(s3.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(s3.AccessKeyID, s3.SecretAccessKey, ""),
+		Secure:       s3.UseSSL,
+		Region:       s3.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	baseURL := strings.TrimSuffix(s3.BaseURL, "/")
+	if baseURL == "" {
+		scheme := "http"
+		if s3.UseSSL {
+			scheme = "https"
+		}
+		if s3.PathStyle {
+			baseURL = fmt.Sprintf("%s://%s/%s", scheme, s3.Endpoint, s3.Bucket)
+		} else {
+			baseURL = fmt.Sprintf("%s://%s.%s", scheme, s3.Bucket, s3.Endpoint)
+		}
+	}
+
+	return &s3Storage{client: client, bucket: s3.Bucket, baseURL: baseURL}, nil
+}
+
+func (s *s3Storage) Name() string {
+	return config.StorageS3
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, body, size, minio.PutObjectOptions{
+		ContentType:  contentType,
+		CacheControl: uploadCacheControl,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *s3Storage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"backend/pkg/totp"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotSetUp    = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+)
+
+const (
+	// totpIssuer is the account name authenticator apps show
+	totpIssuer = "DevLog"
+	// totpSkew accepts codes one step before and after the current one
+	totpSkew = 1
+	// recoveryCodeCount is how many recovery codes are generated at once
+	recoveryCodeCount = 10
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorService interface {
+	GetStatus(adminID uuid.UUID) (*dto.TwoFactorStatusResponse, error)
+	Setup(adminID uuid.UUID) (*dto.TwoFactorSetupResponse, error)
+	Enable(adminID uuid.UUID, code string, visitor Visitor) (*dto.RecoveryCodesResponse, error)
+	Disable(adminID uuid.UUID, req dto.DisableTwoFactorRequest, visitor Visitor) error
+	RegenerateRecoveryCodes(adminID uuid.UUID, code string, visitor Visitor) (*dto.RecoveryCodesResponse, error)
+	Verify(admin *entity.Admin, code string) (bool, error)
+}
+
+type twoFactorService struct {
+	adminRepo    repository.AdminRepository
+	recoveryRepo repository.RecoveryCodeRepository
+	throttle     LoginThrottleService
+}
+
+func NewTwoFactorService(adminRepo repository.AdminRepository, recoveryRepo repository.RecoveryCodeRepository, throttle LoginThrottleService) TwoFactorService {
+	return &twoFactorService{
+		adminRepo:    adminRepo,
+		recoveryRepo: recoveryRepo,
+		throttle:     throttle,
+	}
+}
+
+func (s *twoFactorService) GetStatus(adminID uuid.UUID) (*dto.TwoFactorStatusResponse, error) {
+	admin, err := s.adminRepo.FindByID(adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.TwoFactorStatusResponse{Enabled: admin.TOTPEnabled}
+	if admin.TOTPEnabled {
+		response.RecoveryCodesRemaining, err = s.recoveryRepo.CountUnused(adminID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// Setup generates a new secret. It only takes effect once Enable confirms
+// the authenticator app produces matching codes.
+func (s *twoFactorService) Setup(adminID uuid.UUID) (*dto.TwoFactorSetupResponse, error) {
+	admin, err := s.adminRepo.FindByID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin.TOTPEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.adminRepo.SetTOTPSecret(adminID, secret); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(totpIssuer, admin.Username, secret),
+	}, nil
+}
+
+// Enable turns 2FA on with a code of the secret from Setup and returns the
+// first recovery codes
+func (s *twoFactorService) Enable(adminID uuid.UUID, code string, visitor Visitor) (*dto.RecoveryCodesResponse, error) {
+	admin, err := s.adminRepo.FindByID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin.TOTPEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	if admin.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotSetUp
+	}
+
+	if err := s.checkCode(admin, visitor, func() (bool, error) { return s.verifyTOTP(admin, code) }); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(adminID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.adminRepo.SetTOTPEnabled(adminID, true); err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns 2FA off; it takes the password and a current code so a
+// stolen session alone cannot remove the second factor
+func (s *twoFactorService) Disable(adminID uuid.UUID, req dto.DisableTwoFactorRequest, visitor Visitor) error {
+	admin, err := s.adminRepo.FindByID(adminID)
+	if err != nil {
+		return err
+	}
+	if !admin.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if err := s.throttle.Check(admin.Username, visitor.IP); err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(req.Password)); err != nil {
+		s.throttle.RecordFailure(admin.Username, visitor)
+		return ErrInvalidCredentials
+	}
+	if err := s.checkCode(admin, visitor, func() (bool, error) { return s.Verify(admin, req.Code) }); err != nil {
+		return err
+	}
+
+	if err := s.adminRepo.SetTOTPEnabled(adminID, false); err != nil {
+		return err
+	}
+	return s.recoveryRepo.DeleteByAdmin(adminID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, used or not
+func (s *twoFactorService) RegenerateRecoveryCodes(adminID uuid.UUID, code string, visitor Visitor) (*dto.RecoveryCodesResponse, error) {
+	admin, err := s.adminRepo.FindByID(adminID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin.TOTPEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	if err := s.checkCode(admin, visitor, func() (bool, error) { return s.verifyTOTP(admin, code) }); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(adminID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Verify checks a second factor: a 6 digit code from the authenticator app
+// or an unused recovery code, which is used up
+func (s *twoFactorService) Verify(admin *entity.Admin, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(admin, code)
+	}
+	return s.recoveryRepo.Use(admin.ID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// checkCode verifies a code submitted from a session. Failures count
+// against the username and IP like failed logins, so a stolen access token
+// cannot be used to guess codes.
+func (s *twoFactorService) checkCode(admin *entity.Admin, visitor Visitor, verify func() (bool, error)) error {
+	if err := s.throttle.Check(admin.Username, visitor.IP); err != nil {
+		return err
+	}
+	ok, err := verify()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.throttle.RecordFailure(admin.Username, visitor)
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// verifyTOTP accepts each code once, even within its validity window
+func (s *twoFactorService) verifyTOTP(admin *entity.Admin, code string) (bool, error) {
+	step, ok := totp.Validate(admin.TOTPSecret, strings.TrimSpace(code), time.Now(), totpSkew)
+	if !ok {
+		return false, nil
+	}
+	return s.adminRepo.AdvanceTOTPStep(admin.ID, step)
+}
+
+func (s *twoFactorService) replaceRecoveryCodes(adminID uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashToken(normalizeRecoveryCode(code))
+	}
+
+	if err := s.recoveryRepo.Replace(adminID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCode returns 50 random bits as two groups of 5 characters,
+// e.g. "k3m9x-q2w7e"
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes as typed by the user
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"backend/config"
+	"backend/pkg/imaging"
+	"backend/pkg/svg"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadPrefix is the first segment of every upload's storage key, and the
+// path the local storage driver is served at
+const UploadPrefix = "uploads"
+
+// sniffLength is the number of bytes the content type is detected from
+const sniffLength = 512
+
+// maxImageJobs bounds the images processed at once, a decoded photo takes
+// tens of megabytes
+const maxImageJobs = 2
+
+var (
+	ErrUploadTooLarge        = errors.New("file exceeds the maximum upload size")
+	ErrImageTooLarge         = errors.New("image dimensions exceed the limit")
+	ErrUnsupportedMediaType  = errors.New("file type is not allowed")
+	ErrUploadContentMismatch = errors.New("file content does not match its type or extension")
+)
+
+// processedImageTypes are stored without metadata and with scaled
+// renditions
+var processedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// imageExtensions are the extensions of renditions by format
+var imageExtensions = map[string]string{
+	imaging.JPEG: ".jpg",
+	imaging.PNG:  ".png",
+	imaging.WebP: ".webp",
+}
+
+// uploadExtensions lists the extensions accepted for each media type that
+// can be detected from the content
+var uploadExtensions = map[string][]string{
+	"image/jpeg":      {".jpg", ".jpeg"},
+	"image/png":       {".png"},
+	"image/gif":       {".gif"},
+	"image/webp":      {".webp"},
+	"image/avif":      {".avif"},
+	"image/bmp":       {".bmp"},
+	"image/x-icon":    {".ico"},
+	"image/svg+xml":   {".svg"},
+	"application/pdf": {".pdf"},
+	"video/mp4":       {".mp4", ".m4v"},
+	"video/webm":      {".webm"},
+	"audio/mpeg":      {".mp3"},
+	"audio/wave":      {".wav"},
+}
+
+// uploadTypeAliases are client content types naming a detected media type
+var uploadTypeAliases = map[string]string{
+	"image/jpg":      "image/jpeg",
+	"image/pjpeg":    "image/jpeg",
+	"image/x-png":    "image/png",
+	"image/x-ms-bmp": "image/bmp",
+	"audio/mp3":      "audio/mpeg",
+	"audio/wav":      "audio/wave",
+	"audio/x-wav":    "audio/wave",
+}
+
+// UploadedFile is a stored upload. Processed images also have their
+// dimensions, placeholders and renditions, the first of which is the file
+// itself.
+type UploadedFile struct {
+	Key         string
+	URL         string
+	ContentType string
+	Size        int64
+	Width       int
+	Height      int
+	Blurhash    string
+	Placeholder string
+	Variants    []UploadedVariant
+}
+
+// UploadedVariant is a stored rendition of an image
+type UploadedVariant struct {
+	Key         string
+	URL         string
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+}
+
+type UploadService interface {
+	Upload(ctx context.Context, file io.Reader, size int64, filename, contentType string) (*UploadedFile, error)
+	MaxSize() int64
+	AllowedTypes() []string
+}
+
+type uploadService struct {
+	storage      Storage
+	maxSize      int64
+	allowedTypes []string
+	allowed      map[string]bool
+	imageOptions imaging.Options
+	imageJobs    chan struct{}
+}
+
+// NewUploadService fails for allowed types whose content cannot be
+// detected, as no upload could ever match them
+func NewUploadService(storage Storage, cfg config.UploadConfig) (UploadService, error) {
+	allowed := make(map[string]bool, len(cfg.AllowedTypes))
+	for _, mediaType := range cfg.AllowedTypes {
+		if _, ok := uploadExtensions[mediaType]; !ok {
+			return nil, fmt.Errorf("upload type %q is not supported", mediaType)
+		}
+		allowed[mediaType] = true
+	}
+	return &uploadService{
+		storage:      storage,
+		maxSize:      cfg.MaxSize,
+		allowedTypes: cfg.AllowedTypes,
+		allowed:      allowed,
+		imageOptions: imaging.Options{
+			Widths:  cfg.ImageWidths,
+			Quality: cfg.ImageQuality,
+			WebP:    cfg.ImageWebP,
+		},
+		imageJobs: make(chan struct{}, maxImageJobs),
+	}, nil
+}
+
+func (s *uploadService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *uploadService) AllowedTypes() []string {
+	return s.allowedTypes
+}
+
+// Upload stores the file under a new key with a date prefix. The media type
+// is detected from the content, and the client's content type and file
+// extension must agree with it. SVG images are stored sanitized, JPEG, PNG
+// and WebP images are processed.
+func (s *uploadService) Upload(ctx context.Context, file io.Reader, size int64, filename, contentType string) (*UploadedFile, error) {
+	if size > s.maxSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	mediaType := detectUploadType(head)
+	if !s.allowed[mediaType] {
+		return nil, ErrUnsupportedMediaType
+	}
+	if declared := declaredUploadType(contentType); declared != "" && declared != mediaType {
+		return nil, ErrUploadContentMismatch
+	}
+	ext := strings.ToLower(path.Ext(filename))
+	if !validUploadExtension(mediaType, ext) {
+		return nil, ErrUploadContentMismatch
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), file)
+	key := fmt.Sprintf("%s/%s/%s", UploadPrefix, time.Now().Format("2006/01/02"), uuid.New().String())
+	if processedImageTypes[mediaType] {
+		return s.uploadImage(ctx, key, ext, body)
+	}
+	if mediaType == "image/svg+xml" {
+		data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > s.maxSize {
+			return nil, ErrUploadTooLarge
+		}
+		if data, err = svg.Sanitize(data); err != nil {
+			return nil, ErrUploadContentMismatch
+		}
+		body, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	key += ext
+	if err := s.storage.Put(ctx, key, body, size, mediaType); err != nil {
+		return nil, err
+	}
+
+	return &UploadedFile{
+		Key:         key,
+		URL:         s.storage.URL(key),
+		ContentType: mediaType,
+		Size:        size,
+	}, nil
+}
+
+// uploadImage stores the renditions of an image. The full size one keeps
+// the extension of the upload, scaled ones get their width in the key.
+// Renditions stored before a failure are deleted again.
+func (s *uploadService) uploadImage(ctx context.Context, key, ext string, body io.Reader) (*UploadedFile, error) {
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	select {
+	case s.imageJobs <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	result, err := imaging.Process(data, s.imageOptions)
+	<-s.imageJobs
+	if err != nil {
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return nil, ErrImageTooLarge
+		}
+		return nil, ErrUploadContentMismatch
+	}
+
+	variants := make([]UploadedVariant, 0, len(result.Renditions))
+	for i, rendition := range result.Renditions {
+		variantKey := key + ext
+		if i > 0 {
+			suffix := ""
+			if rendition.Width < result.Width {
+				suffix = fmt.Sprintf("-%dw", rendition.Width)
+			}
+			variantKey = key + suffix + imageExtensions[rendition.Format]
+		}
+
+		if err := s.storage.Put(ctx, variantKey, bytes.NewReader(rendition.Data), int64(len(rendition.Data)), rendition.ContentType()); err != nil {
+			for _, stored := range variants {
+				if err := s.storage.Delete(context.WithoutCancel(ctx), stored.Key); err != nil {
+					log.Printf("Failed to delete upload %s: %v", stored.Key, err)
+				}
+			}
+			return nil, err
+		}
+		variants = append(variants, UploadedVariant{
+			Key:         variantKey,
+			URL:         s.storage.URL(variantKey),
+			ContentType: rendition.ContentType(),
+			Width:       rendition.Width,
+			Height:      rendition.Height,
+			Size:        int64(len(rendition.Data)),
+		})
+	}
+
+	original := variants[0]
+	return &UploadedFile{
+		Key:         original.Key,
+		URL:         original.URL,
+		ContentType: original.ContentType,
+		Size:        original.Size,
+		Width:       result.Width,
+		Height:      result.Height,
+		Blurhash:    result.Blurhash,
+		Placeholder: result.Placeholder,
+		Variants:    variants,
+	}, nil
+}
+
+// detectUploadType returns the media type of the content without
+// parameters, text that starts with an <svg> element counts as an SVG image
+func detectUploadType(head []byte) string {
+	if len(head) >= 12 && string(head[4:8]) == "ftyp" {
+		if brand := string(head[8:12]); brand == "avif" || brand == "avis" {
+			return "image/avif"
+		}
+	}
+
+	mediaType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if mediaType == "text/xml" || mediaType == "text/plain" {
+		if looksLikeSVG(head) {
+			return "image/svg+xml"
+		}
+	}
+	return mediaType
+}
+
+// looksLikeSVG skips the XML declaration, comments and the DOCTYPE and
+// checks the first element. The sanitizer parses the whole document later.
+func looksLikeSVG(head []byte) bool {
+	rest := bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	for {
+		rest = bytes.TrimLeft(rest, " \t\r\n")
+		switch {
+		case bytes.HasPrefix(rest, []byte("<?")):
+			_, rest, _ = bytes.Cut(rest, []byte("?>"))
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			_, rest, _ = bytes.Cut(rest, []byte("-->"))
+		case bytes.HasPrefix(rest, []byte("<!")):
+			// A DOCTYPE's internal subset contains declarations of its own
+			end := bytes.IndexByte(rest, '>')
+			if subset := bytes.IndexByte(rest, '['); subset >= 0 && subset < end {
+				_, rest, _ = bytes.Cut(rest, []byte("]"))
+			}
+			_, rest, _ = bytes.Cut(rest, []byte(">"))
+		default:
+			return bytes.HasPrefix(rest, []byte("<svg")) && len(rest) > 4 && strings.ContainsRune(" \t\r\n>/", rune(rest[4]))
+		}
+	}
+}
+
+// declaredUploadType normalizes the client's content type. Generic types
+// browsers send for unknown files are ignored.
+func declaredUploadType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "application/octet-stream" {
+		return ""
+	}
+	if alias, ok := uploadTypeAliases[mediaType]; ok {
+		return alias
+	}
+	return mediaType
+}
+
+func validUploadExtension(mediaType, ext string) bool {
+	for _, allowed := range uploadExtensions[mediaType] {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"backend/internal/model/dto"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"errors"
+	"log"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// emailPattern mirrors the email_format constraint of the admins table
+var emailPattern = regexp.MustCompile(`^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$`)
+
+var (
+	ErrUserNotFound   = errors.New("user not found")
+	ErrUsernameTaken  = errors.New("username is already taken")
+	ErrEmailTaken     = errors.New("email is already used by another user")
+	ErrInvalidEmail   = errors.New("invalid email address")
+	ErrLastOwner      = errors.New("the last owner cannot be removed or demoted")
+	ErrCannotDeleteMe = errors.New("you cannot delete your own account")
+)
+
+// UserService manages admin accounts and their roles
+type UserService interface {
+	ListUsers() ([]dto.UserResponse, error)
+	GetUser(id uuid.UUID) (*dto.UserResponse, error)
+	CreateUser(req dto.CreateUserRequest) (*dto.UserResponse, error)
+	UpdateUser(id uuid.UUID, req dto.UpdateUserRequest) (*dto.UserResponse, error)
+	DeleteUser(id uuid.UUID, actor Actor) error
+	ResetTwoFactor(id uuid.UUID) error
+	UpdateProfile(id uuid.UUID, req dto.UpdateProfileRequest) (*dto.UserResponse, error)
+	ChangePassword(token *AccessToken, req dto.ChangePasswordRequest) error
+}
+
+type userService struct {
+	adminRepo    repository.AdminRepository
+	tokenRepo    repository.AuthTokenRepository
+	recoveryRepo repository.RecoveryCodeRepository
+}
+
+func NewUserService(adminRepo repository.AdminRepository, tokenRepo repository.AuthTokenRepository, recoveryRepo repository.RecoveryCodeRepository) UserService {
+	return &userService{
+		adminRepo:    adminRepo,
+		tokenRepo:    tokenRepo,
+		recoveryRepo: recoveryRepo,
+	}
+}
+
+func (s *userService) ListUsers() ([]dto.UserResponse, error) {
+	admins, err := s.adminRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]dto.UserResponse, len(admins))
+	for i := range admins {
+		users[i] = toUserResponse(&admins[i])
+	}
+	return users, nil
+}
+
+func (s *userService) GetUser(id uuid.UUID) (*dto.UserResponse, error) {
+	admin, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toUserResponse(admin)
+	return &response, nil
+}
+
+func (s *userService) CreateUser(req dto.CreateUserRequest) (*dto.UserResponse, error) {
+	username := strings.TrimSpace(req.Username)
+	exists, err := s.adminRepo.UsernameExists(username)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrUsernameTaken
+	}
+
+	admin := &entity.Admin{
+		Username: username,
+		Role:     req.Role,
+		IsActive: true,
+	}
+	if err := s.setEmail(admin, req.Email); err != nil {
+		return nil, err
+	}
+	if err := ValidatePassword(req.Password, username); err != nil {
+		return nil, err
+	}
+	if err := setPassword(admin, req.Password); err != nil {
+		return nil, err
+	}
+
+	if err := s.adminRepo.Create(admin); err != nil {
+		return nil, err
+	}
+
+	response := toUserResponse(admin)
+	return &response, nil
+}
+
+// UpdateUser changes the email, password or role. A new password ends the
+// user's sessions; a new role applies to their next request.
+func (s *userService) UpdateUser(id uuid.UUID, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	admin, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	endSessions := false
+	if req.Role != nil && *req.Role != admin.Role {
+		if err := s.ensureOtherOwner(admin); err != nil {
+			return nil, err
+		}
+		admin.Role = *req.Role
+	}
+	if req.Email != nil {
+		if err := s.setEmail(admin, *req.Email); err != nil {
+			return nil, err
+		}
+	}
+	if req.Password != nil {
+		if err := ValidatePassword(*req.Password, admin.Username); err != nil {
+			return nil, err
+		}
+		if err := setPassword(admin, *req.Password); err != nil {
+			return nil, err
+		}
+		endSessions = true
+	}
+
+	if err := s.adminRepo.Update(admin); err != nil {
+		return nil, err
+	}
+	if endSessions {
+		s.endSessions(admin.ID)
+	}
+
+	response := toUserResponse(admin)
+	return &response, nil
+}
+
+// DeleteUser deactivates an account and ends its sessions. Posts keep the
+// deleted user as author.
+func (s *userService) DeleteUser(id uuid.UUID, actor Actor) error {
+	if id == actor.ID {
+		return ErrCannotDeleteMe
+	}
+
+	admin, err := s.findUser(id)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureOtherOwner(admin); err != nil {
+		return err
+	}
+
+	if err := s.adminRepo.Deactivate(id); err != nil {
+		return err
+	}
+	s.endSessions(id)
+	return nil
+}
+
+// ResetTwoFactor turns off 2FA of a user who lost their authenticator and
+// recovery codes
+func (s *userService) ResetTwoFactor(id uuid.UUID) error {
+	if _, err := s.findUser(id); err != nil {
+		return err
+	}
+	if err := s.adminRepo.SetTOTPEnabled(id, false); err != nil {
+		return err
+	}
+	return s.recoveryRepo.DeleteByAdmin(id)
+}
+
+// UpdateProfile changes the admin's own email
+func (s *userService) UpdateProfile(id uuid.UUID, req dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	admin, err := s.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil {
+		if err := s.setEmail(admin, *req.Email); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.adminRepo.Update(admin); err != nil {
+		return nil, err
+	}
+
+	response := toUserResponse(admin)
+	return &response, nil
+}
+
+// ChangePassword sets a new password after checking the current one, and
+// ends every other session of the admin
+func (s *userService) ChangePassword(token *AccessToken, req dto.ChangePasswordRequest) error {
+	admin, err := s.findUser(token.AdminID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(req.CurrentPassword)); err != nil {
+		return ErrInvalidCredentials
+	}
+	if err := ValidatePassword(req.NewPassword, admin.Username); err != nil {
+		return err
+	}
+	if err := setPassword(admin, req.NewPassword); err != nil {
+		return err
+	}
+	if err := s.adminRepo.Update(admin); err != nil {
+		return err
+	}
+
+	if _, err := s.tokenRepo.RevokeOtherFamilies(admin.ID, token.SessionID); err != nil {
+		log.Printf("Failed to end other sessions of admin %s: %v", admin.ID, err)
+	}
+	return nil
+}
+
+func (s *userService) findUser(id uuid.UUID) (*entity.Admin, error) {
+	admin, err := s.adminRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return admin, nil
+}
+
+// ensureOtherOwner keeps at least one owner, who can manage users
+func (s *userService) ensureOtherOwner(admin *entity.Admin) error {
+	if admin.Role != entity.RoleOwner {
+		return nil
+	}
+
+	owners, err := s.adminRepo.CountByRole(entity.RoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func (s *userService) setEmail(admin *entity.Admin, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		admin.Email = nil
+		return nil
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	taken, err := s.adminRepo.EmailExists(email, admin.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+	admin.Email = &email
+	return nil
+}
+
+// normalizeEmail accepts a bare address that fits the admins.email column.
+// Display names ("Bob <bob@example.com>") and hosts without a domain are
+// refused rather than failing the insert.
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 255 || !emailPattern.MatchString(email) {
+		return "", ErrInvalidEmail
+	}
+	return addr.Address, nil
+}
+
+func (s *userService) endSessions(adminID uuid.UUID) {
+	if _, err := s.tokenRepo.RevokeAllFamilies(adminID); err != nil {
+		log.Printf("Failed to end sessions of admin %s: %v", adminID, err)
+	}
+}
+
+func setPassword(admin *entity.Admin, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	admin.PasswordHash = string(hash)
+	return nil
+}
+
+func toUserResponse(admin *entity.Admin) dto.UserResponse {
+	email := ""
+	if admin.Email != nil {
+		email = *admin.Email
+	}
+
+	return dto.UserResponse{
+		ID:               admin.ID.String(),
+		Username:         admin.Username,
+		Email:            email,
+		Role:             admin.Role,
+		TwoFactorEnabled: admin.TOTPEnabled,
+		CreatedAt:        admin.CreatedAt,
+		LastLogin:        admin.LastLogin,
+	}
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"backend/config"
+	"backend/internal/model/entity"
+	"backend/internal/repository"
+	"context"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ViewService buffers post page views in memory and flushes them in batches
+type ViewService interface {
+	Start(ctx context.Context)
+	Track(postID uuid.UUID, visitor Visitor, source TrafficSource)
+}
+
+// TrafficSource describes where a view came from
+type TrafficSource struct {
+	Referrer    string
+	UTMSource   string
+	UTMMedium   string
+	UTMCampaign string
+}
+
+// maxViewFlushRetries is how often buffered views are put back after a
+// failed flush before they are dropped
+const maxViewFlushRetries = 5
+
+// analyticsPurgeInterval is how often daily stats past the retention are
+// deleted
+const analyticsPurgeInterval = time.Hour
+
+type viewKey struct {
+	postID uuid.UUID
+	bucket time.Time
+}
+
+type sourceKey struct {
+	day          string
+	postID       uuid.UUID
+	referrerHost string
+	utmSource    string
+	utmMedium    string
+	utmCampaign  string
+}
+
+type viewService struct {
+	viewRepo      repository.ViewRepository
+	siteHost      string
+	dedupWindow   time.Duration
+	flushInterval time.Duration
+	batchSize     int
+	retention     time.Duration // 0 keeps traffic sources forever
+
+	mu       sync.Mutex
+	salt     []byte
+	saltDay  string
+	seen     map[string]time.Time
+	pending  map[viewKey]int
+	sources  map[sourceKey]int
+	buffered int
+	retries  int // failed flushes whose views were put back
+	flushNow chan struct{}
+}
+
+func NewViewService(cfg config.AnalyticsConfig, siteURL string, viewRepo repository.ViewRepository) ViewService {
+	dedupWindow, err := time.ParseDuration(cfg.ViewDedupWindow)
+	if err != nil {
+		dedupWindow = 30 * time.Minute
+	}
+
+	flushInterval, err := time.ParseDuration(cfg.ViewFlushInterval)
+	if err != nil {
+		flushInterval = 30 * time.Second
+	}
+
+	batchSize := cfg.ViewBatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &viewService{
+		viewRepo:      viewRepo,
+		siteHost:      referrerHost(siteURL, ""),
+		dedupWindow:   dedupWindow,
+		flushInterval: flushInterval,
+		batchSize:     batchSize,
+		retention:     analyticsRetention(cfg),
+		seen:          make(map[string]time.Time),
+		pending:       make(map[viewKey]int),
+		sources:       make(map[sourceKey]int),
+		flushNow:      make(chan struct{}, 1),
+	}
+}
+
+// Start flushes buffered views periodically until ctx is cancelled
+func (s *viewService) Start(ctx context.Context) {
+	log.Printf("View tracking started, flushing every %s", s.flushInterval)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+	purge := time.NewTicker(analyticsPurgeInterval)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flush()
+			log.Println("View tracking stopped")
+			return
+		case <-ticker.C:
+			s.flush()
+		case <-s.flushNow:
+			s.flush()
+		case <-purge.C:
+			if s.retention > 0 {
+				if _, err := s.viewRepo.PurgeSourcesBefore(time.Now().Add(-s.retention)); err != nil {
+					log.Printf("Failed to purge traffic sources: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// analyticsRetention parses how long daily traffic sources and search
+// stats are kept, 0 keeps them forever
+func analyticsRetention(cfg config.AnalyticsConfig) time.Duration {
+	retention, err := time.ParseDuration(cfg.Retention)
+	if err != nil || retention < 0 {
+		return 365 * 24 * time.Hour
+	}
+	return retention
+}
+
+// Track records a view unless it comes from a bot or the same visitor
+// already viewed the post within the dedup window. Only the host of the
+// referrer is kept, and referrals from the site itself count as direct.
+func (s *viewService) Track(postID uuid.UUID, visitor Visitor, source TrafficSource) {
+	if visitor.IsBot() {
+		return
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rotateSalt(now)
+
+	key := visitor.hash(s.salt) + postID.String()
+	if last, ok := s.seen[key]; ok && now.Sub(last) < s.dedupWindow {
+		return
+	}
+	s.seen[key] = now
+
+	host := referrerHost(source.Referrer, s.siteHost)
+	s.pending[viewKey{postID: postID, bucket: now.UTC().Truncate(time.Hour)}]++
+	s.sources[sourceKey{
+		day:          now.UTC().Format("2006-01-02"),
+		postID:       postID,
+		referrerHost: host,
+		utmSource:    normalizeTag(source.UTMSource),
+		utmMedium:    normalizeTag(source.UTMMedium),
+		utmCampaign:  normalizeTag(source.UTMCampaign),
+	}]++
+	s.buffered++
+
+	if s.buffered >= s.batchSize {
+		select {
+		case s.flushNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// rotateSalt replaces the visitor salt once per day so hashes can't be
+// correlated across days. When no salt can be generated the current one is
+// kept and the next view tries again. Must be called with s.mu held.
+func (s *viewService) rotateSalt(now time.Time) {
+	day := now.UTC().Format("2006-01-02")
+	if day == s.saltDay {
+		return
+	}
+	salt, err := randomSalt()
+	if err != nil {
+		log.Printf("Failed to rotate the visitor salt: %v", err)
+		return
+	}
+	s.salt = salt
+	s.saltDay = day
+	s.seen = make(map[string]time.Time)
+}
+
+func (s *viewService) flush() {
+	s.mu.Lock()
+	pending := s.pending
+	sources := s.sources
+	buffered := s.buffered
+	s.pending = make(map[viewKey]int)
+	s.sources = make(map[sourceKey]int)
+	s.buffered = 0
+
+	// Forget visitors whose dedup window has passed
+	now := time.Now()
+	for key, last := range s.seen {
+		if now.Sub(last) >= s.dedupWindow {
+			delete(s.seen, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	stats := make([]entity.PostViewStat, 0, len(pending))
+	for key, views := range pending {
+		stats = append(stats, entity.PostViewStat{
+			PostID:      key.postID,
+			BucketStart: key.bucket,
+			Views:       views,
+		})
+	}
+
+	batch := repository.ViewBatch{
+		Stats:   stats,
+		Sources: make([]entity.PostTrafficSource, 0, len(sources)),
+	}
+	for key, visits := range sources {
+		day, _ := time.Parse("2006-01-02", key.day)
+		batch.Sources = append(batch.Sources, entity.PostTrafficSource{
+			Day:          day,
+			PostID:       key.postID,
+			ReferrerHost: key.referrerHost,
+			UTMSource:    key.utmSource,
+			UTMMedium:    key.utmMedium,
+			UTMCampaign:  key.utmCampaign,
+			Visits:       visits,
+		})
+	}
+
+	if err := s.viewRepo.AddViews(batch); err != nil {
+		log.Printf("Failed to flush %d view buckets: %v", len(stats), err)
+
+		// Put the views back so the next flush retries them, unless they
+		// failed too often, so a batch that can never be stored does not
+		// grow the buffers forever
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.retries >= maxViewFlushRetries {
+			log.Printf("Dropping %d views after %d failed flushes", buffered, s.retries+1)
+			s.retries = 0
+			return
+		}
+		s.retries++
+		for key, views := range pending {
+			s.pending[key] += views
+		}
+		for key, visits := range sources {
+			s.sources[key] += visits
+		}
+		s.buffered += buffered
+		return
+	}
+
+	s.mu.Lock()
+	s.retries = 0
+	s.mu.Unlock()
+}
+
+// referrerHost reduces a referrer URL to its lowercase host without "www.",
+// returning "" for empty, unparsable or same-site referrers.
+func referrerHost(referrer, siteHost string) string {
+	referrer = strings.TrimSpace(referrer)
+	if referrer == "" {
+		return ""
+	}
+	if !strings.Contains(referrer, "://") {
+		referrer = "https://" + referrer
+	}
+
+	u, err := url.Parse(referrer)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host == siteHost {
+		return ""
+	}
+	return host
+}
+
+// normalizeTag lowercases and trims a UTM value so variants aggregate together
+func normalizeTag(value string) string {
+	return truncateRunes(strings.ToLower(strings.TrimSpace(value)), 100)
+}
+
+func truncateRunes(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"regexp"
+)
+
+// botUserAgent matches crawlers, link unfurlers and scripted clients
+var botUserAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|archiver|facebookexternalhit|embedly|iframely|preview|whatsapp|pinterest|vkshare|headless|lighthouse|curl|wget|python-requests|httpclient|go-http-client|okhttp|java/|libwww|scrapy|phantomjs|puppeteer|playwright`)
+
+// Visitor identifies an anonymous reader. Only a salted hash of these
+// fields is ever persisted.
+type Visitor struct {
+	IP        string
+	UserAgent string
+}
+
+// IsBot reports whether the visitor is a crawler, a link preview or a
+// script rather than a person with a browser
+func (v Visitor) IsBot() bool {
+	return v.UserAgent == "" || botUserAgent.MatchString(v.UserAgent)
+}
+
+// hash returns a hex encoded SHA-256 of the salt and the visitor fields
+func (v Visitor) hash(salt []byte) string {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(v.IP))
+	h.Write([]byte{0})
+	h.Write([]byte(v.UserAgent))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// newSalt returns the configured salt, or a random one when unset. It is
+// only called at startup.
+func newSalt(configured string) []byte {
+	if configured != "" {
+		return []byte(configured)
+	}
+	log.Println("Warning: ANALYTICS_SALT not configured, using a random salt (visitor dedup resets on restart)")
+	salt, err := randomSalt()
+	if err != nil {
+		log.Fatalf("Failed to generate the visitor salt: %v", err)
+	}
+	return salt
+}
+
+func randomSalt() ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
@@ -26,15 +26,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"backend/config"
 	"backend/database"
 	"backend/internal/api"
-	"backend/internal/repository"
-	"backend/internal/service"
 	"backend/pkg/tlsutil"
 
 	_ "backend/docs" // swagger docs
@@ -57,23 +57,17 @@ func main() {
 	gin.SetMode(cfg.Server.Mode)
 
 	// Initialize Router with all API endpoints
-	router := api.NewRouter(database.DB)
+	router := api.NewRouter(database.DB, cfg)
 
-	// Start SEO service (URL pushing to search engines)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	postRepo := repository.NewPostRepository(database.DB)
-	seoService := service.NewSEOService(cfg.SEO, postRepo)
-	go seoService.Start(ctx)
-
-	// Graceful shutdown
+	// Start background workers (view tracking flushes on shutdown, SEO push queue)
+	var workers sync.WaitGroup
+	workers.Add(1)
 	go func() {
-		quit := make(chan os.Signal, 1)
-		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-		<-quit
-		log.Println("Shutting down server...")
-		cancel()
+		defer workers.Done()
+		router.Start(ctx)
 	}()
 
 	log.Printf("Server starting on port %s", cfg.Server.Port)
@@ -83,6 +77,21 @@ func main() {
 		Handler: router.Engine(),
 	}
 
+	// Graceful shutdown
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+		log.Println("Shutting down server...")
+
+		shutdownCtx, stop := context.WithTimeout(context.Background(), 10*time.Second)
+		defer stop()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Server shutdown error: %v", err)
+		}
+		cancel()
+	}()
+
 	if cfg.Server.SSL {
 		log.Printf("Enabling SSL with JKS file: %s", cfg.Server.JKSPath)
 		tlsCert, err := tlsutil.LoadTLSCertFromJKS(cfg.Server.JKSPath, cfg.Server.JKSPassword)
@@ -94,12 +103,14 @@ func main() {
 			Certificates: []tls.Certificate{tlsCert},
 		}
 
-		if err := srv.ListenAndServeTLS("", ""); err != nil {
+		if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start SSL server: %v", err)
 		}
 	} else {
-		if err := srv.ListenAndServe(); err != nil {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}
+
+	workers.Wait()
 }
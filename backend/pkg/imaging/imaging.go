@@ -0,0 +1,227 @@
+// Package imaging prepares uploaded raster images for the web. It removes
+// their metadata, scales them to several widths, adds WebP versions and
+// computes a placeholder to show while they load.
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"sort"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/buckket/go-blurhash"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Formats
+const (
+	JPEG = "jpeg"
+	PNG  = "png"
+	WebP = "webp"
+)
+
+// MaxPixels bounds the size of decoded images, as a small file can declare
+// a huge image
+const MaxPixels = 50_000_000
+
+// Placeholder sizes
+const (
+	blurhashWidth    = 32
+	placeholderWidth = 16
+	placeholderJPEG  = 40
+)
+
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Options control the renditions of an image
+type Options struct {
+	Widths  []int // widths of the scaled renditions, those not below the image's width are skipped
+	Quality int   // JPEG quality, 1 to 100
+	WebP    bool  // add WebP renditions when they are smaller
+}
+
+// Rendition is an encoded version of an image
+type Rendition struct {
+	Data   []byte
+	Format string
+	Width  int
+	Height int
+}
+
+// ContentType is the media type of the rendition
+func (r Rendition) ContentType() string {
+	return "image/" + r.Format
+}
+
+// Result is a processed image. The first rendition has the full size and
+// the format of the source.
+type Result struct {
+	Width       int
+	Height      int
+	Blurhash    string
+	Placeholder string // tiny version of the image as a data URI
+	Renditions  []Rendition
+}
+
+// Process decodes a JPEG, PNG or WebP image and encodes it again, without
+// its metadata. JPEG photos are turned upright first, as their orientation
+// is part of the removed EXIF data. Scaled renditions keep the source's
+// format, except WebP images, whose scaled renditions are JPEG or PNG with
+// optional WebP versions like for the other formats. The WebP encoder is
+// lossless, so its renditions are only kept when they are smaller, which
+// is common for screenshots and graphics but not for photos.
+func Process(data []byte, opts Options) (*Result, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format != JPEG && format != PNG && format != WebP {
+		return nil, image.ErrFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format == JPEG {
+		img = orient(img, jpegOrientation(data))
+	}
+	bounds := img.Bounds()
+
+	result := &Result{Width: bounds.Dx(), Height: bounds.Dy()}
+
+	// The full size WebP source is kept as it is, without its metadata
+	scaledFormat := format
+	if format == WebP {
+		stripped, err := stripWebPMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+		result.Renditions = append(result.Renditions, Rendition{Data: stripped, Format: WebP, Width: result.Width, Height: result.Height})
+		scaledFormat = JPEG
+		if !opaque(img) {
+			scaledFormat = PNG
+		}
+	} else if err := result.add(img, format, opts); err != nil {
+		return nil, err
+	}
+
+	// Each width is scaled from the previous one, which is much faster than
+	// scaling the full image every time
+	widths := append([]int(nil), opts.Widths...)
+	sort.Sort(sort.Reverse(sort.IntSlice(widths)))
+	smallest := img
+	for i, width := range widths {
+		if width <= 0 || width >= result.Width || (i > 0 && width == widths[i-1]) {
+			continue
+		}
+		smallest = resize(smallest, width, xdraw.CatmullRom)
+		if err := result.add(smallest, scaledFormat, opts); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(result.Renditions[1:], func(i, j int) bool {
+		return result.Renditions[1+i].Width < result.Renditions[1+j].Width
+	})
+
+	result.Blurhash, result.Placeholder, err = placeholders(smallest)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// add encodes a rendition and its WebP version
+func (r *Result) add(img image.Image, format string, opts Options) error {
+	data, err := encode(img, format, opts.Quality)
+	if err != nil {
+		return err
+	}
+	bounds := img.Bounds()
+	r.Renditions = append(r.Renditions, Rendition{Data: data, Format: format, Width: bounds.Dx(), Height: bounds.Dy()})
+
+	if opts.WebP {
+		webp, err := encode(img, WebP, opts.Quality)
+		if err != nil {
+			return err
+		}
+		if len(webp) < len(data) {
+			r.Renditions = append(r.Renditions, Rendition{Data: webp, Format: WebP, Width: bounds.Dx(), Height: bounds.Dy()})
+		}
+	}
+	return nil
+}
+
+func encode(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case JPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case PNG:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		err = encoder.Encode(&buf, img)
+	case WebP:
+		err = nativewebp.Encode(&buf, img, nil)
+	default:
+		err = image.ErrFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resize scales the image to a width, keeping its aspect ratio
+func resize(img image.Image, width int, scaler xdraw.Scaler) image.Image {
+	bounds := img.Bounds()
+	height := (bounds.Dy()*width + bounds.Dx()/2) / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	scaler.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// placeholders computes the blurhash and a tiny JPEG, or PNG for images
+// with transparency, of the image. Both are blurry, so a fast scaler is
+// good enough.
+func placeholders(img image.Image) (string, string, error) {
+	bounds := img.Bounds()
+	xComponents, yComponents := 4, 3
+	if bounds.Dy() > bounds.Dx() {
+		xComponents, yComponents = 3, 4
+	}
+	hash, err := blurhash.Encode(xComponents, yComponents, resize(img, min(blurhashWidth, bounds.Dx()), xdraw.ApproxBiLinear))
+	if err != nil {
+		return "", "", err
+	}
+
+	tiny := resize(img, min(placeholderWidth, bounds.Dx()), xdraw.ApproxBiLinear)
+	format := JPEG
+	if !opaque(img) {
+		format = PNG
+	}
+	data, err := encode(tiny, format, placeholderJPEG)
+	if err != nil {
+		return "", "", err
+	}
+	return hash, "data:image/" + format + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// VP8X flags of the metadata chunks
+const (
+	vp8xEXIF = 0x08
+	vp8xXMP  = 0x04
+)
+
+var errInvalidWebP = errors.New("invalid WebP file")
+
+// stripWebPMetadata removes the EXIF and XMP chunks of a WebP file, without
+// decoding the image
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errInvalidWebP
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF\x00\x00\x00\x00WEBP")
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, errInvalidWebP
+		}
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + size + size%2
+		if i+8+size > len(data) {
+			return nil, errInvalidWebP
+		}
+		if end > len(data) {
+			end = len(data)
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[i:end]...)
+			if size > 0 {
+				chunk[8] &^= vp8xEXIF | vp8xXMP
+			}
+			buf.Write(chunk)
+		default:
+			buf.Write(data[i:end])
+		}
+		i = end
+	}
+
+	out := buf.Bytes()
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, nil
+}
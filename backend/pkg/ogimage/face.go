@@ -0,0 +1,111 @@
+package ogimage
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// scaledFace enlarges a bitmap face by an integer factor with nearest
+// neighbour sampling, which keeps the pixel look of the glyphs
+type scaledFace struct {
+	face  font.Face
+	scale int
+}
+
+func (f *scaledFace) Close() error {
+	return nil
+}
+
+func (f *scaledFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	dr, mask, maskp, advance, ok := f.face.Glyph(fixed.Point26_6{}, r)
+	if !ok {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+
+	s := f.scale
+	scaled := image.NewAlpha(image.Rect(0, 0, dr.Dx()*s, dr.Dy()*s))
+	for y := 0; y < dr.Dy(); y++ {
+		for x := 0; x < dr.Dx(); x++ {
+			_, _, _, a := mask.At(maskp.X+x, maskp.Y+y).RGBA()
+			if a == 0 {
+				continue
+			}
+			for dy := 0; dy < s; dy++ {
+				for dx := 0; dx < s; dx++ {
+					scaled.Pix[(y*s+dy)*scaled.Stride+x*s+dx] = uint8(a >> 8)
+				}
+			}
+		}
+	}
+
+	origin := image.Pt(dot.X.Round(), dot.Y.Round())
+	return scaled.Rect.Add(dr.Min.Mul(s)).Add(origin), scaled, image.Point{}, advance * fixed.Int26_6(s), true
+}
+
+func (f *scaledFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	bounds, advance, ok := f.face.GlyphBounds(r)
+	s := fixed.Int26_6(f.scale)
+	bounds.Min = fixed.Point26_6{X: bounds.Min.X * s, Y: bounds.Min.Y * s}
+	bounds.Max = fixed.Point26_6{X: bounds.Max.X * s, Y: bounds.Max.Y * s}
+	return bounds, advance * s, ok
+}
+
+func (f *scaledFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	advance, ok := f.face.GlyphAdvance(r)
+	return advance * fixed.Int26_6(f.scale), ok
+}
+
+func (f *scaledFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return f.face.Kern(r0, r1) * fixed.Int26_6(f.scale)
+}
+
+func (f *scaledFace) Metrics() font.Metrics {
+	m := f.face.Metrics()
+	s := fixed.Int26_6(f.scale)
+	return font.Metrics{
+		Height:     m.Height * s,
+		Ascent:     m.Ascent * s,
+		Descent:    m.Descent * s,
+		XHeight:    m.XHeight * s,
+		CapHeight:  m.CapHeight * s,
+		CaretSlope: m.CaretSlope,
+	}
+}
+
+// fallbackFace draws runes with the first face that has a glyph for them,
+// so Go Mono covers Latin text and the bitmap font everything else
+type fallbackFace []font.Face
+
+func (f fallbackFace) pick(r rune) font.Face {
+	for _, face := range f {
+		if _, ok := face.GlyphAdvance(r); ok {
+			return face
+		}
+	}
+	return f[len(f)-1]
+}
+
+// advance measures text
+func (f fallbackFace) advance(text string) fixed.Int26_6 {
+	var width fixed.Int26_6
+	for _, r := range text {
+		a, _ := f.pick(r).GlyphAdvance(r)
+		width += a
+	}
+	return width
+}
+
+// draw writes text with its baseline at dot and returns the dot after it
+func (f fallbackFace) draw(dst *image.RGBA, src image.Image, dot fixed.Point26_6, text string) fixed.Point26_6 {
+	for _, r := range text {
+		dr, mask, maskp, advance, ok := f.pick(r).Glyph(dot, r)
+		if ok {
+			draw.DrawMask(dst, dr, src, image.Point{}, mask, maskp, draw.Over)
+		}
+		dot.X += advance
+	}
+	return dot
+}
@@ -0,0 +1,320 @@
+// Package ogimage draws Open Graph share images of posts as a terminal
+// window, with Go Mono for Latin text and a pixel font for CJK and
+// everything else Go Mono lacks. Both fonts are embedded in the binary.
+package ogimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/hajimehoshi/bitmapfont/v3"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Size of the image recommended by Facebook, X and LinkedIn
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// Card is the text shown on a share image
+type Card struct {
+	Title    string
+	Tags     []string
+	ReadTime string // e.g. "8 min"
+	Date     string
+	SiteName string
+	Command  string // Shown at the prompt, e.g. "cat posts/hello-world.md"
+}
+
+// Colors of the site's dark theme
+var (
+	colorPage      = color.RGBA{0x18, 0x19, 0x1b, 0xff}
+	colorBg        = color.RGBA{0x1e, 0x1f, 0x22, 0xff}
+	colorSurface   = color.RGBA{0x2b, 0x2d, 0x30, 0xff}
+	colorBorder    = color.RGBA{0x4e, 0x51, 0x57, 0xff}
+	colorPrimary   = color.RGBA{0x54, 0x8a, 0xf7, 0xff}
+	colorSecondary = color.RGBA{0x7c, 0x7f, 0x88, 0xff}
+	colorAccent    = color.RGBA{0xc3, 0xe8, 0x8d, 0xff}
+	colorTextLight = color.RGBA{0xdf, 0xe1, 0xe5, 0xff}
+	colorRed       = color.RGBA{0xef, 0x44, 0x44, 0xff}
+	colorYellow    = color.RGBA{0xea, 0xb3, 0x08, 0xff}
+	colorGreen     = color.RGBA{0x22, 0xc5, 0x5e, 0xff}
+)
+
+// Layout in pixels
+const (
+	margin       = 48
+	titleBar     = 56
+	padding      = 48
+	radius       = 14
+	titleSize    = 56
+	titleLeading = 72
+	titleLines   = 3
+	textSize     = 28
+	smallSize    = 20
+)
+
+var (
+	fontsOnce sync.Once
+	fontsErr  error
+	regular   *opentype.Font
+	bold      *opentype.Font
+
+	// Faces keep glyph buffers, so images are drawn one at a time
+	renderMu sync.Mutex
+)
+
+func loadFonts() error {
+	fontsOnce.Do(func() {
+		if regular, fontsErr = opentype.Parse(gomono.TTF); fontsErr != nil {
+			return
+		}
+		bold, fontsErr = opentype.Parse(gomonobold.TTF)
+	})
+	return fontsErr
+}
+
+// newFace returns Go Mono at the size, falling back to the 12px bitmap
+// font scaled to about the same size
+func newFace(f *opentype.Font, size float64) (fallbackFace, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, err
+	}
+	scale := int(size/12 + 0.5)
+	if scale < 1 {
+		scale = 1
+	}
+	return fallbackFace{face, &scaledFace{face: bitmapfont.FaceSC, scale: scale}}, nil
+}
+
+// Render draws the card as a PNG image
+func Render(card Card) ([]byte, error) {
+	if err := loadFonts(); err != nil {
+		return nil, err
+	}
+
+	renderMu.Lock()
+	defer renderMu.Unlock()
+
+	titleFace, err := newFace(bold, titleSize)
+	if err != nil {
+		return nil, err
+	}
+	textFace, err := newFace(regular, textSize)
+	if err != nil {
+		return nil, err
+	}
+	textBoldFace, err := newFace(bold, textSize)
+	if err != nil {
+		return nil, err
+	}
+	smallFace, err := newFace(regular, smallSize)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colorPage), image.Point{}, draw.Src)
+
+	// Window with its title bar and traffic lights
+	window := image.Rect(margin, margin, Width-margin, Height-margin)
+	fillRoundedRect(img, window, radius, colorBorder)
+	fillRoundedRect(img, window.Inset(1), radius-1, colorBg)
+	bar := image.Rect(window.Min.X+1, window.Min.Y+1, window.Max.X-1, window.Min.Y+titleBar)
+	fillRoundedRect(img, bar, radius-1, colorSurface)
+	draw.Draw(img, image.Rect(bar.Min.X, bar.Max.Y-radius, bar.Max.X, bar.Max.Y), image.NewUniform(colorSurface), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(bar.Min.X, bar.Max.Y, bar.Max.X, bar.Max.Y+1), image.NewUniform(colorBorder), image.Point{}, draw.Src)
+	for i, c := range []color.RGBA{colorRed, colorYellow, colorGreen} {
+		fillCircle(img, image.Pt(window.Min.X+32+i*28, window.Min.Y+titleBar/2), 8, c)
+	}
+	barText := "zsh — " + card.SiteName
+	barWidth := smallFace.advance(barText).Round()
+	smallFace.draw(img, image.NewUniform(colorSecondary), baseline(smallFace, (Width-barWidth)/2, bar.Min.Y, bar.Dy()), barText)
+
+	left := window.Min.X + padding
+	maxWidth := fixed.I(window.Dx() - 2*padding)
+
+	// Prompt
+	y := window.Min.Y + titleBar + padding
+	dot := baseline(textFace, left, y, textSize+8)
+	dot = textFace.draw(img, image.NewUniform(colorPrimary), dot, "➜ ")
+	dot = textFace.draw(img, image.NewUniform(colorSecondary), dot, "~ ")
+	textFace.draw(img, image.NewUniform(colorAccent), dot, card.Command)
+	y += textSize + 8 + 28
+
+	// Title, followed by the cursor
+	lines := wrap(titleFace, card.Title, maxWidth, titleLines)
+	for i, line := range lines {
+		dot = titleFace.draw(img, image.NewUniform(colorTextLight), baseline(titleFace, left, y, titleLeading), line)
+		if i == len(lines)-1 {
+			cursor := image.Rect(dot.X.Round()+12, y+10, dot.X.Round()+12+titleSize/2, y+titleLeading-10)
+			if cursor.Max.X <= window.Max.X-padding/2 {
+				draw.Draw(img, cursor, image.NewUniform(colorAccent), image.Point{}, draw.Src)
+			}
+		}
+		y += titleLeading
+	}
+
+	// Tags
+	if len(card.Tags) > 0 {
+		tags := make([]string, len(card.Tags))
+		for i, tag := range card.Tags {
+			tags[i] = "#" + tag
+		}
+		line := wrap(textFace, strings.Join(tags, "  "), maxWidth, 1)
+		textFace.draw(img, image.NewUniform(colorAccent), baseline(textFace, left, y+16, textSize+8), line[0])
+	}
+
+	// Footer: read time and date on the left, site name on the right
+	footerY := window.Max.Y - padding - textSize
+	var meta []string
+	if card.ReadTime != "" {
+		meta = append(meta, card.ReadTime+" read")
+	}
+	if card.Date != "" {
+		meta = append(meta, card.Date)
+	}
+	textFace.draw(img, image.NewUniform(colorSecondary), baseline(textFace, left, footerY, textSize), strings.Join(meta, " · "))
+	nameWidth := textBoldFace.advance(card.SiteName).Round()
+	textBoldFace.draw(img, image.NewUniform(colorPrimary), baseline(textBoldFace, window.Max.X-padding-nameWidth, footerY, textSize), card.SiteName)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// baseline returns the dot that vertically centers a line of the face in
+// a box starting at top
+func baseline(face fallbackFace, x, top, height int) fixed.Point26_6 {
+	m := face[0].Metrics()
+	textHeight := (m.Ascent + m.Descent).Round()
+	return fixed.P(x, top+(height-textHeight)/2+m.Ascent.Round())
+}
+
+// wrap breaks text into at most maxLines lines no wider than maxWidth.
+// Latin words are kept whole, CJK text may break between any characters.
+// Text that does not fit ends with an ellipsis.
+func wrap(face fallbackFace, text string, maxWidth fixed.Int26_6, maxLines int) []string {
+	var lines []string
+	var line strings.Builder
+	for _, word := range splitWords(strings.Join(strings.Fields(text), " ")) {
+		candidate := line.String() + word
+		if line.Len() == 0 {
+			candidate = strings.TrimLeft(word, " ")
+		}
+		if face.advance(candidate) <= maxWidth {
+			line.Reset()
+			line.WriteString(candidate)
+			continue
+		}
+
+		if line.Len() > 0 {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		// Words wider than a line are broken anywhere
+		for _, r := range strings.TrimLeft(word, " ") {
+			if line.Len() > 0 && face.advance(line.String()+string(r)) > maxWidth {
+				lines = append(lines, line.String())
+				line.Reset()
+			}
+			line.WriteRune(r)
+		}
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	if len(lines) == 0 {
+		return []string{""}
+	}
+	if len(lines) <= maxLines {
+		return lines
+	}
+
+	lines = lines[:maxLines]
+	last := []rune(strings.TrimRight(lines[maxLines-1], " "))
+	for len(last) > 0 && face.advance(string(last)+"…") > maxWidth {
+		last = last[:len(last)-1]
+	}
+	lines[maxLines-1] = strings.TrimRight(string(last), " ") + "…"
+	return lines
+}
+
+// splitWords splits text into words that keep their leading space, with
+// every wide character as a word of its own
+func splitWords(text string) []string {
+	var words []string
+	var word strings.Builder
+	for _, r := range text {
+		switch {
+		case r == ' ':
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			word.WriteRune(r)
+		case isWide(r):
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			words = append(words, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
+
+func fillRoundedRect(img *image.RGBA, r image.Rectangle, radius int, c color.RGBA) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cx, cy := x, y
+			switch {
+			case x < r.Min.X+radius:
+				cx = r.Min.X + radius
+			case x >= r.Max.X-radius:
+				cx = r.Max.X - radius - 1
+			}
+			switch {
+			case y < r.Min.Y+radius:
+				cy = r.Min.Y + radius
+			case y >= r.Max.Y-radius:
+				cy = r.Max.Y - radius - 1
+			}
+			if dx, dy := x-cx, y-cy; dx*dx+dy*dy <= radius*radius {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+func fillCircle(img *image.RGBA, center image.Point, radius int, c color.RGBA) {
+	fillRoundedRect(img, image.Rect(center.X-radius, center.Y-radius, center.X+radius+1, center.Y+radius+1), radius, c)
+}
@@ -0,0 +1,77 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+// MaxURLs is the most URLs a single sitemap file may list
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is a single sitemap entry. LastMod, ChangeFreq and Priority are
+// omitted when zero.
+type URL struct {
+	Loc        string
+	LastMod    time.Time
+	ChangeFreq string
+	Priority   float64
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []xmlURL `xml:"url"`
+}
+
+type xmlURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []xmlSitemap `xml:"sitemap"`
+}
+
+type xmlSitemap struct {
+	Loc string `xml:"loc"`
+}
+
+// URLSet renders a sitemap listing urls
+func URLSet(urls []URL) ([]byte, error) {
+	set := urlSet{XMLNS: namespace, URLs: make([]xmlURL, len(urls))}
+	for i, u := range urls {
+		entry := xmlURL{Loc: u.Loc, ChangeFreq: u.ChangeFreq}
+		if !u.LastMod.IsZero() {
+			entry.LastMod = u.LastMod.UTC().Format("2006-01-02")
+		}
+		if u.Priority > 0 {
+			entry.Priority = strconv.FormatFloat(u.Priority, 'f', 1, 64)
+		}
+		set.URLs[i] = entry
+	}
+	return marshal(set)
+}
+
+// Index renders a sitemap index pointing at the given sitemap files
+func Index(locs []string) ([]byte, error) {
+	index := sitemapIndex{XMLNS: namespace, Sitemaps: make([]xmlSitemap, len(locs))}
+	for i, loc := range locs {
+		index.Sitemaps[i] = xmlSitemap{Loc: loc}
+	}
+	return marshal(index)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
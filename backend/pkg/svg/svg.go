@@ -0,0 +1,175 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrInvalid is returned for input that is not a well-formed SVG document
+var ErrInvalid = errors.New("invalid SVG document")
+
+// blockedElements are removed together with their content. They run
+// scripts or embed documents that can.
+var blockedElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"embed":         true,
+	"object":        true,
+	"handler":       true,
+	"listener":      true,
+}
+
+// animationElements can set attributes, so they are removed when they
+// target a link or an event handler
+var animationElements = map[string]bool{
+	"set":              true,
+	"animate":          true,
+	"animatemotion":    true,
+	"animatetransform": true,
+}
+
+// urlAttributes hold URLs, whose scheme is checked
+var urlAttributes = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"action": true,
+}
+
+// Sanitize parses an SVG document and writes it back without scripts,
+// event handler attributes, javascript: links, comments, processing
+// instructions and the DOCTYPE, so entities cannot be declared either.
+func Sanitize(src []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(src))
+	decoder.Strict = true
+
+	var buf bytes.Buffer
+	depth, skip := 0, 0
+	done := false
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalid
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skip > 0 {
+				skip++
+				continue
+			}
+			if depth == 0 && (done || t.Name.Local != "svg") {
+				return nil, ErrInvalid
+			}
+			if blocked(t) {
+				skip = 1
+				continue
+			}
+			buf.WriteString("<" + qualifiedName(t.Name))
+			for _, attr := range t.Attr {
+				if !allowedAttr(attr) {
+					continue
+				}
+				buf.WriteString(" " + qualifiedName(attr.Name) + `="`)
+				xml.EscapeText(&buf, []byte(attr.Value))
+				buf.WriteString(`"`)
+			}
+			buf.WriteString(">")
+			depth++
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if depth == 0 {
+				return nil, ErrInvalid
+			}
+			buf.WriteString("</" + qualifiedName(t.Name) + ">")
+			depth--
+			done = depth == 0
+		case xml.CharData:
+			if skip == 0 && depth > 0 {
+				xml.EscapeText(&buf, t)
+			}
+		}
+	}
+
+	if !done || depth != 0 {
+		return nil, ErrInvalid
+	}
+	return buf.Bytes(), nil
+}
+
+func blocked(t xml.StartElement) bool {
+	name := strings.ToLower(t.Name.Local)
+	if blockedElements[name] {
+		return true
+	}
+	if animationElements[name] {
+		for _, attr := range t.Attr {
+			if strings.ToLower(attr.Name.Local) != "attributename" {
+				continue
+			}
+			target := strings.ToLower(strings.TrimSpace(attr.Value))
+			if i := strings.LastIndex(target, ":"); i >= 0 {
+				target = target[i+1:]
+			}
+			if urlAttributes[target] || strings.HasPrefix(target, "on") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func allowedAttr(attr xml.Attr) bool {
+	name := strings.ToLower(attr.Name.Local)
+	if strings.HasPrefix(name, "on") {
+		return false
+	}
+	if urlAttributes[name] {
+		return safeURL(attr.Value)
+	}
+	return true
+}
+
+// safeURL allows relative URLs, fragments, http(s) and mailto links and
+// inline raster images
+func safeURL(value string) bool {
+	// Browsers ignore whitespace and control characters in the scheme
+	normalized := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(value))
+
+	scheme, rest, ok := strings.Cut(normalized, ":")
+	if !ok || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+	switch scheme {
+	case "http", "https", "mailto":
+		return true
+	case "data":
+		for _, prefix := range []string{"image/png", "image/jpeg", "image/gif", "image/webp"} {
+			if strings.HasPrefix(rest, prefix+";") || strings.HasPrefix(rest, prefix+",") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
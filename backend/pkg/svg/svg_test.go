@@ -0,0 +1,180 @@
+package svg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		blocked []string // must not appear in the output, case-insensitively
+	}{
+		{
+			name:  "plain shapes are kept",
+			input: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><rect width="10" height="10" fill="red"/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><rect width="10" height="10" fill="red"></rect></svg>`,
+		},
+		{
+			name:    "script element with its content",
+			input:   `<svg><script>alert(1)</script><circle r="1"/></svg>`,
+			want:    `<svg><circle r="1"></circle></svg>`,
+			blocked: []string{"script", "alert"},
+		},
+		{
+			name:    "nested script",
+			input:   `<svg><g><script type="text/javascript"><![CDATA[alert(1)]]></script></g></svg>`,
+			want:    `<svg><g></g></svg>`,
+			blocked: []string{"script", "alert"},
+		},
+		{
+			name:    "prefixed svg:script",
+			input:   `<svg:svg xmlns:svg="http://www.w3.org/2000/svg"><svg:script>alert(1)</svg:script></svg:svg>`,
+			want:    `<svg:svg xmlns:svg="http://www.w3.org/2000/svg"></svg:svg>`,
+			blocked: []string{"script", "alert"},
+		},
+		{
+			name:    "event handler attributes",
+			input:   `<svg onload="alert(1)"><rect ONCLICK="alert(2)" onMouseOver="alert(3)" width="1"/></svg>`,
+			want:    `<svg><rect width="1"></rect></svg>`,
+			blocked: []string{"alert", "onload", "onclick", "onmouseover"},
+		},
+		{
+			name:    "javascript link",
+			input:   `<svg><a href="javascript:alert(1)"><text>x</text></a></svg>`,
+			want:    `<svg><a><text>x</text></a></svg>`,
+			blocked: []string{"javascript"},
+		},
+		{
+			name:    "javascript link with mixed case and xlink prefix",
+			input:   `<svg xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="JaVaScRiPt:alert(1)">x</a></svg>`,
+			blocked: []string{"javascript"},
+		},
+		{
+			name:    "whitespace in the scheme",
+			input:   `<svg><a href=" java&#x09;script&#x0A;:alert(1)">x</a></svg>`,
+			blocked: []string{"alert"},
+		},
+		{
+			name:    "control characters in the scheme",
+			input:   `<svg><a href="java&#x0D;script:alert(1)">x</a><image href="&#x20;javascript:alert(2)"/></svg>`,
+			blocked: []string{"alert"},
+		},
+		{
+			name:    "vbscript and data HTML are refused",
+			input:   `<svg><a href="vbscript:msgbox(1)">x</a><image href="data:text/html;base64,PHNjcmlwdD4="/></svg>`,
+			blocked: []string{"vbscript", "text/html"},
+		},
+		{
+			name:  "safe links and inline images are kept",
+			input: `<svg><a href="https://example.com/a?b#c">x</a><a href="#top">y</a><image href="data:image/png;base64,iVBORw0KGgo="/></svg>`,
+			want:  `<svg><a href="https://example.com/a?b#c">x</a><a href="#top">y</a><image href="data:image/png;base64,iVBORw0KGgo="></image></svg>`,
+		},
+		{
+			name:    "set targeting href",
+			input:   `<svg><a><set attributeName="href" to="javascript:alert(1)"/><text>x</text></a></svg>`,
+			want:    `<svg><a><text>x</text></a></svg>`,
+			blocked: []string{"javascript", "<set"},
+		},
+		{
+			name:    "animate targeting xlink:href and event handlers",
+			input:   `<svg><a><animate attributeName="xlink:href" values="javascript:alert(1)"/><animate attributeName=" ONCLICK " to="alert(2)"/></a></svg>`,
+			want:    `<svg><a></a></svg>`,
+			blocked: []string{"alert", "animate"},
+		},
+		{
+			name:  "animation of other attributes is kept",
+			input: `<svg><rect><animate attributeName="width" from="0" to="10" dur="1s"></animate></rect></svg>`,
+			want:  `<svg><rect><animate attributeName="width" from="0" to="10" dur="1s"></animate></rect></svg>`,
+		},
+		{
+			name:    "foreignObject with HTML",
+			input:   `<svg><foreignObject width="10" height="10"><body xmlns="http://www.w3.org/1999/xhtml"><img src="x" onerror="alert(1)"/></body></foreignObject></svg>`,
+			want:    `<svg></svg>`,
+			blocked: []string{"foreignobject", "onerror", "alert"},
+		},
+		{
+			name:    "embedded documents",
+			input:   `<svg><iframe src="https://evil.example"/><embed src="x.swf"/><object data="x"/></svg>`,
+			want:    `<svg></svg>`,
+			blocked: []string{"iframe", "embed", "object"},
+		},
+		{
+			name:    "comments, processing instructions and DOCTYPE are dropped",
+			input:   `<?xml version="1.0"?><!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd"><!-- <script>alert(1)</script> --><svg><?php echo 1; ?></svg>`,
+			want:    `<svg></svg>`,
+			blocked: []string{"doctype", "alert", "php", "<?"},
+		},
+		{
+			name:    "text is escaped",
+			input:   `<svg><text>&lt;script&gt;alert(1)&lt;/script&gt;</text></svg>`,
+			want:    `<svg><text>&lt;script&gt;alert(1)&lt;/script&gt;</text></svg>`,
+			blocked: []string{"<script"},
+		},
+		{
+			name:    "attribute values are escaped",
+			input:   `<svg><rect class="&quot;&gt;&lt;script&gt;"/></svg>`,
+			blocked: []string{"<script"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Sanitize() error = %v", err)
+			}
+			if tt.want != "" && string(got) != tt.want {
+				t.Errorf("Sanitize() = %s, want %s", got, tt.want)
+			}
+			lower := strings.ToLower(string(got))
+			for _, s := range tt.blocked {
+				if strings.Contains(lower, strings.ToLower(s)) {
+					t.Errorf("Sanitize() = %s, contains %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitizeInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ``},
+		{"not svg", `<html><body/></html>`},
+		{"not xml", `<svg><rect></svg>`},
+		{"text only", `hello`},
+		{"second root", `<svg></svg><svg></svg>`},
+		{"content after the root", `<svg></svg><script>alert(1)</script>`},
+		{"entity from the DOCTYPE", `<!DOCTYPE svg [<!ENTITY xss "<script>alert(1)</script>">]><svg><text>&xss;</text></svg>`},
+		{"external entity", `<!DOCTYPE svg [<!ENTITY xxe SYSTEM "file:///etc/passwd">]><svg>&xxe;</svg>`},
+		{"entity expansion", `<!DOCTYPE svg [<!ENTITY a "aaaa"><!ENTITY b "&a;&a;&a;&a;">]><svg>&b;</svg>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize([]byte(tt.input))
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Sanitize() = %s, %v, want ErrInvalid", got, err)
+			}
+		})
+	}
+}
+
+// TestSanitizeDoctypeWithoutReferences checks that an internal subset whose
+// entities are never referenced is dropped with the DOCTYPE
+func TestSanitizeDoctypeWithoutReferences(t *testing.T) {
+	input := `<!DOCTYPE svg [<!ENTITY xss "<script>alert(1)</script>">]><svg><rect/></svg>`
+	got, err := Sanitize([]byte(input))
+	if err != nil {
+		t.Fatalf("Sanitize() error = %v", err)
+	}
+	if want := `<svg><rect></rect></svg>`; string(got) != want {
+		t.Errorf("Sanitize() = %s, want %s", got, want)
+	}
+}
@@ -14,6 +14,23 @@ CREATE EXTENSION IF NOT EXISTS vector;
 -- Drop existing objects if needed (uncomment to reset database)
 -- DROP VIEW IF EXISTS comment_threads CASCADE;
 -- DROP VIEW IF EXISTS published_posts_with_tags CASCADE;
+-- DROP TABLE IF EXISTS audit_logs CASCADE;
+-- DROP TABLE IF EXISTS admin_identities CASCADE;
+-- DROP TABLE IF EXISTS api_tokens CASCADE;
+-- DROP TABLE IF EXISTS login_throttles CASCADE;
+-- DROP TABLE IF EXISTS admin_recovery_codes CASCADE;
+-- DROP TABLE IF EXISTS auth_events CASCADE;
+-- DROP TABLE IF EXISTS revoked_tokens CASCADE;
+-- DROP TABLE IF EXISTS refresh_tokens CASCADE;
+-- DROP TABLE IF EXISTS indexnow_keys CASCADE;
+-- DROP TABLE IF EXISTS seo_push_history CASCADE;
+-- DROP TABLE IF EXISTS seo_push_quotas CASCADE;
+-- DROP TABLE IF EXISTS seo_push_queue CASCADE;
+-- DROP TABLE IF EXISTS search_query_stats CASCADE;
+-- DROP TABLE IF EXISTS post_traffic_sources CASCADE;
+-- DROP TABLE IF EXISTS ai_usage_events CASCADE;
+-- DROP TABLE IF EXISTS post_view_stats CASCADE;
+-- DROP TABLE IF EXISTS post_reactions CASCADE;
 -- DROP TABLE IF EXISTS ai_generated_content CASCADE;
 -- DROP TABLE IF EXISTS post_tags CASCADE;
 -- DROP TABLE IF EXISTS comments CASCADE;
@@ -33,12 +50,27 @@ CREATE TABLE IF NOT EXISTS admins (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     last_login TIMESTAMP WITH TIME ZONE,
     is_active BOOLEAN DEFAULT TRUE,
+    totp_secret VARCHAR(64) NOT NULL DEFAULT '',
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    totp_last_step BIGINT NOT NULL DEFAULT 0,
+    role VARCHAR(20) NOT NULL DEFAULT 'owner',
     CONSTRAINT email_format CHECK (email ~* '^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$')
 );
 
+-- Columns added after the first release, for existing databases
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'owner';
+ALTER TABLE admins DROP CONSTRAINT IF EXISTS valid_admin_role;
+ALTER TABLE admins ADD CONSTRAINT valid_admin_role CHECK (role IN ('owner', 'editor', 'author', 'moderator'));
+
 COMMENT ON TABLE admins IS 'Administrator user accounts';
 COMMENT ON COLUMN admins.password_hash IS 'Bcrypt hashed password';
 COMMENT ON COLUMN admins.is_active IS 'Soft delete flag for admin accounts';
+COMMENT ON COLUMN admins.totp_secret IS 'Base32 TOTP secret, set by 2FA setup and checked once totp_enabled';
+COMMENT ON COLUMN admins.role IS 'owner (everything, users), editor (all content), author (own posts), moderator (comments)';
+COMMENT ON COLUMN admins.totp_last_step IS 'Last accepted TOTP time step, so a code works only once';
 
 -- ==========================================
 -- Table: blog_posts
@@ -47,6 +79,7 @@ COMMENT ON COLUMN admins.is_active IS 'Soft delete flag for admin accounts';
 CREATE TABLE IF NOT EXISTS blog_posts (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     title VARCHAR(500) NOT NULL,
+    slug VARCHAR(200) NOT NULL,
     excerpt TEXT NOT NULL,
     content TEXT NOT NULL,
     read_time VARCHAR(20) NOT NULL DEFAULT '1 min',
@@ -56,14 +89,36 @@ CREATE TABLE IF NOT EXISTS blog_posts (
     is_published BOOLEAN DEFAULT FALSE,
     view_count INTEGER DEFAULT 0,
     author_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    meta_title VARCHAR(500) NOT NULL DEFAULT '',
+    meta_description TEXT NOT NULL DEFAULT '',
+    canonical_url TEXT NOT NULL DEFAULT '',
+    og_image TEXT NOT NULL DEFAULT '',
+    no_index BOOLEAN NOT NULL DEFAULT FALSE,
     CONSTRAINT positive_view_count CHECK (view_count >= 0)
 );
 
+-- Columns added after the first release, for existing databases
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS meta_title VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS meta_description TEXT NOT NULL DEFAULT '';
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS canonical_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS og_image TEXT NOT NULL DEFAULT '';
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS no_index BOOLEAN NOT NULL DEFAULT FALSE;
+-- Existing posts keep their ID as slug until it is changed
+ALTER TABLE blog_posts ADD COLUMN IF NOT EXISTS slug VARCHAR(200);
+UPDATE blog_posts SET slug = id::text WHERE slug IS NULL;
+ALTER TABLE blog_posts ALTER COLUMN slug SET NOT NULL;
+
 COMMENT ON TABLE blog_posts IS 'Blog post articles';
+COMMENT ON COLUMN blog_posts.slug IS 'URL slug of the server-rendered page, /p/{slug}';
 COMMENT ON COLUMN blog_posts.content IS 'Markdown formatted content';
 COMMENT ON COLUMN blog_posts.read_time IS 'Estimated reading time (e.g., "8 min")';
 COMMENT ON COLUMN blog_posts.is_published IS 'Draft/Published status';
 COMMENT ON COLUMN blog_posts.view_count IS 'Number of times the post has been viewed';
+COMMENT ON COLUMN blog_posts.meta_title IS 'SEO title, empty to use the post title';
+COMMENT ON COLUMN blog_posts.meta_description IS 'SEO description, empty to use the excerpt';
+COMMENT ON COLUMN blog_posts.canonical_url IS 'Canonical URL, empty for the post URL';
+COMMENT ON COLUMN blog_posts.og_image IS 'Open Graph image, empty for the generated share image';
+COMMENT ON COLUMN blog_posts.no_index IS 'Hide from search engines and the sitemap';
 
 -- ==========================================
 -- Table: tags
@@ -151,11 +206,327 @@ COMMENT ON COLUMN ai_generated_content.is_applied IS 'Whether the suggestion has
 COMMENT ON COLUMN ai_generated_content.applied_at IS 'Timestamp when the content was applied to the post';
 COMMENT ON COLUMN ai_generated_content.error_message IS 'Error details if generation failed';
 
+-- ==========================================
+-- Table: post_reactions
+-- Description: Anonymous reader reactions ("was this helpful") on posts
+-- ==========================================
+CREATE TABLE IF NOT EXISTS post_reactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES blog_posts(id) ON DELETE CASCADE,
+    reaction VARCHAR(20) NOT NULL,
+    visitor_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_reaction CHECK (reaction IN ('like', 'insightful', 'helpful', 'not_helpful')),
+    CONSTRAINT unique_visitor_reaction UNIQUE (post_id, visitor_hash, reaction)
+);
+
+COMMENT ON TABLE post_reactions IS 'Anonymous reader reactions, deduplicated per visitor';
+COMMENT ON COLUMN post_reactions.reaction IS 'Reaction type: like, insightful, helpful, not_helpful';
+COMMENT ON COLUMN post_reactions.visitor_hash IS 'Salted SHA-256 of visitor IP and user agent (no raw IPs stored)';
+
+-- ==========================================
+-- Table: post_view_stats
+-- Description: Deduplicated post views bucketed by hour
+-- ==========================================
+CREATE TABLE IF NOT EXISTS post_view_stats (
+    post_id UUID NOT NULL REFERENCES blog_posts(id) ON DELETE CASCADE,
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, bucket_start),
+    CONSTRAINT positive_views CHECK (views >= 0)
+);
+
+COMMENT ON TABLE post_view_stats IS 'Hourly deduplicated view counts per post (bots excluded)';
+COMMENT ON COLUMN post_view_stats.bucket_start IS 'Start of the hour (UTC) the views fall into';
+
+-- Referrers are ranked from post_traffic_sources, individual views are not kept
+DROP TABLE IF EXISTS post_view_events;
+
+-- ==========================================
+-- Table: ai_usage_events
+-- Description: Calls made to the AI provider
+-- ==========================================
+CREATE TABLE IF NOT EXISTS ai_usage_events (
+    id BIGSERIAL PRIMARY KEY,
+    operation VARCHAR(30) NOT NULL,
+    provider VARCHAR(30) NOT NULL,
+    success BOOLEAN NOT NULL,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE ai_usage_events IS 'AI provider calls for usage statistics';
+COMMENT ON COLUMN ai_usage_events.operation IS 'AI operation: excerpt, readtime, tags, chat, chat_stream, summarize';
+
+-- ==========================================
+-- Table: post_traffic_sources
+-- Description: Daily visits per post, referrer host and UTM tags
+-- ==========================================
+CREATE TABLE IF NOT EXISTS post_traffic_sources (
+    day DATE NOT NULL,
+    post_id UUID NOT NULL REFERENCES blog_posts(id) ON DELETE CASCADE,
+    referrer_host VARCHAR(255) NOT NULL DEFAULT '',
+    utm_source VARCHAR(100) NOT NULL DEFAULT '',
+    utm_medium VARCHAR(100) NOT NULL DEFAULT '',
+    utm_campaign VARCHAR(100) NOT NULL DEFAULT '',
+    visits INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, post_id, referrer_host, utm_source, utm_medium, utm_campaign)
+);
+
+COMMENT ON TABLE post_traffic_sources IS 'Daily deduplicated visits per traffic source, without any visitor data';
+COMMENT ON COLUMN post_traffic_sources.referrer_host IS 'External referrer host, empty for direct or internal traffic';
+
+-- ==========================================
+-- Table: search_query_stats
+-- Description: Daily counts of internal search queries
+-- ==========================================
+CREATE TABLE IF NOT EXISTS search_query_stats (
+    day DATE NOT NULL,
+    query VARCHAR(100) NOT NULL,
+    searches INTEGER NOT NULL DEFAULT 0,
+    zero_results INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, query)
+);
+
+COMMENT ON TABLE search_query_stats IS 'Internal searches per normalized query, without any visitor data';
+COMMENT ON COLUMN search_query_stats.zero_results IS 'Number of searches that returned no posts';
+
+-- ==========================================
+-- Table: seo_push_queue
+-- Description: URLs waiting to be pushed to search engines
+-- ==========================================
+CREATE TABLE IF NOT EXISTS seo_push_queue (
+    id BIGSERIAL PRIMARY KEY,
+    engine VARCHAR(20) NOT NULL,
+    url TEXT NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('update', 'delete')),
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE seo_push_queue IS 'Search engine push queue, at most one pending task per engine and URL';
+COMMENT ON COLUMN seo_push_queue.engine IS 'Search engine: baidu, indexnow, google';
+COMMENT ON COLUMN seo_push_queue.status IS 'pending until pushed, failed after too many attempts';
+
+-- ==========================================
+-- Table: seo_push_quotas
+-- Description: URLs pushed per search engine and day
+-- ==========================================
+CREATE TABLE IF NOT EXISTS seo_push_quotas (
+    engine VARCHAR(20) NOT NULL,
+    day DATE NOT NULL,
+    used INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (engine, day)
+);
+
+COMMENT ON TABLE seo_push_quotas IS 'Daily push quota usage, also raised to what the engine reports';
+
+-- ==========================================
+-- Table: seo_push_history
+-- Description: Push requests and search engine responses
+-- ==========================================
+CREATE TABLE IF NOT EXISTS seo_push_history (
+    id BIGSERIAL PRIMARY KEY,
+    engine VARCHAR(20) NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    urls TEXT NOT NULL,
+    url_count INTEGER NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL,
+    response TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE seo_push_history IS 'One row per push request to a search engine';
+COMMENT ON COLUMN seo_push_history.urls IS 'Pushed URLs, newline separated';
+COMMENT ON COLUMN seo_push_history.status_code IS 'HTTP status of the response, 0 when the request failed';
+
+-- ==========================================
+-- Table: indexnow_keys
+-- Description: IndexNow keys served at /{key}.txt
+-- ==========================================
+CREATE TABLE IF NOT EXISTS indexnow_keys (
+    key VARCHAR(128) PRIMARY KEY,
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    retired_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE indexnow_keys IS 'IndexNow ownership keys, one active key used for pushes';
+COMMENT ON COLUMN indexnow_keys.retired_at IS 'When the key was rotated out, it is still served for 7 days';
+
+-- ==========================================
+-- Table: refresh_tokens
+-- Description: Rotating refresh tokens of admin sessions
+-- ==========================================
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id UUID NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT ''
+);
+
+-- Columns added after the first release, for existing databases
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip VARCHAR(45) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+
+COMMENT ON TABLE refresh_tokens IS 'Refresh tokens, each is used once and replaced by the next one of its family';
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Login session; reusing a used token revokes the whole family';
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'SHA-256 hex of the token, the token itself is not stored';
+COMMENT ON COLUMN refresh_tokens.ip IS 'Client IP the token was issued to';
+
+-- ==========================================
+-- Table: revoked_tokens
+-- Description: Access tokens revoked before they expire
+-- ==========================================
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE revoked_tokens IS 'Revoked access token IDs, purged once the tokens expire';
+
+-- ==========================================
+-- Table: auth_events
+-- Description: Login history of admin accounts
+-- ==========================================
+CREATE TABLE IF NOT EXISTS auth_events (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    username VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(50) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    session_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE auth_events IS 'Every login attempt, successful or not, and lockouts and unlocks';
+COMMENT ON COLUMN auth_events.admin_id IS 'NULL when the username matched no admin';
+COMMENT ON COLUMN auth_events.reason IS 'Why a login failed: unknown_user, invalid_password or invalid_2fa_code; username or ip for lockouts and unlocks';
+COMMENT ON COLUMN auth_events.session_id IS 'Refresh token family started by a successful login';
+
+-- ==========================================
+-- Table: admin_recovery_codes
+-- Description: Single-use two-factor recovery codes
+-- ==========================================
+CREATE TABLE IF NOT EXISTS admin_recovery_codes (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id UUID NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE admin_recovery_codes IS 'Two-factor recovery codes, replaced as a set when regenerated';
+COMMENT ON COLUMN admin_recovery_codes.code_hash IS 'SHA-256 hex of the normalized code, the code itself is not stored';
+
+-- ==========================================
+-- Table: login_throttles
+-- Description: Failed login counters per username and IP
+-- ==========================================
+CREATE TABLE IF NOT EXISTS login_throttles (
+    scope VARCHAR(20) NOT NULL,
+    value VARCHAR(100) NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (scope, value)
+);
+
+COMMENT ON TABLE login_throttles IS 'Recent failed logins; logins are refused until locked_until';
+COMMENT ON COLUMN login_throttles.scope IS 'username (lowercased) or ip';
+
+-- ==========================================
+-- Table: api_tokens
+-- Description: Personal API tokens for scripts and CI
+-- ==========================================
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id UUID NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    last_used_ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMENT ON TABLE api_tokens IS 'Long-lived tokens that act as their admin, limited to their scopes';
+COMMENT ON COLUMN api_tokens.prefix IS 'Start of the token (dlp_ and 8 characters), shown to tell tokens apart';
+COMMENT ON COLUMN api_tokens.token_hash IS 'SHA-256 hex of the token, the token itself is only shown once';
+COMMENT ON COLUMN api_tokens.scopes IS 'Space separated permissions, e.g. posts:write files:upload';
+COMMENT ON COLUMN api_tokens.last_used_at IS 'Updated at most once a minute';
+
+-- ==========================================
+-- Table: admin_identities
+-- Description: OpenID Connect identities linked to admins
+-- ==========================================
+CREATE TABLE IF NOT EXISTS admin_identities (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id UUID NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    provider VARCHAR(32) NOT NULL,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT unique_identity_subject UNIQUE (issuer, subject)
+);
+
+COMMENT ON TABLE admin_identities IS 'Accounts at OpenID Connect providers that sign in as an admin';
+COMMENT ON COLUMN admin_identities.provider IS 'Configured provider name (OIDC_PROVIDERS) at the time of linking';
+COMMENT ON COLUMN admin_identities.email IS 'Verified email reported by the provider at the last login';
+
+-- ==========================================
+-- Table: audit_logs
+-- Description: Mutating admin requests, deleted after AUDIT_RETENTION
+-- ==========================================
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id UUID NOT NULL,
+    actor_username VARCHAR(50) NOT NULL,
+    api_token_id UUID,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(20) NOT NULL DEFAULT '',
+    target_id VARCHAR(100) NOT NULL DEFAULT '',
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    status INTEGER NOT NULL,
+    changes TEXT NOT NULL DEFAULT '{}',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE audit_logs IS 'Who changed what through the admin API, including refused and failed requests';
+COMMENT ON COLUMN audit_logs.actor_id IS 'Admin making the request, no foreign key so entries outlive the account';
+COMMENT ON COLUMN audit_logs.api_token_id IS 'API token the request used, NULL for login sessions';
+COMMENT ON COLUMN audit_logs.action IS 'e.g. post.create, post.publish, tag.delete, user.update';
+COMMENT ON COLUMN audit_logs.changes IS 'JSON object of changed fields to {from, to}, long values cut to 200 characters';
+
 -- ==========================================
 -- INDEXES
 -- ==========================================
 
 -- Blog Posts Indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_blog_posts_slug ON blog_posts(slug);
 CREATE INDEX IF NOT EXISTS idx_blog_posts_published_date ON blog_posts(published_date DESC);
 CREATE INDEX IF NOT EXISTS idx_blog_posts_is_published ON blog_posts(is_published);
 CREATE INDEX IF NOT EXISTS idx_blog_posts_author_id ON blog_posts(author_id);
@@ -178,7 +549,9 @@ CREATE INDEX IF NOT EXISTS idx_comments_is_deleted ON comments(is_deleted);
 
 -- Admins Indexes
 CREATE INDEX IF NOT EXISTS idx_admins_username ON admins(username);
-CREATE INDEX IF NOT EXISTS idx_admins_email ON admins(email);
+-- Emails are unique regardless of case, as SSO links accounts by email
+DROP INDEX IF EXISTS idx_admins_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admins_email_lower ON admins (LOWER(email));
 
 -- AI Generated Content Indexes
 CREATE INDEX IF NOT EXISTS idx_ai_generated_content_post_id ON ai_generated_content(post_id);
@@ -186,6 +559,45 @@ CREATE INDEX IF NOT EXISTS idx_ai_generated_content_status ON ai_generated_conte
 CREATE INDEX IF NOT EXISTS idx_ai_generated_content_is_applied ON ai_generated_content(is_applied);
 CREATE INDEX IF NOT EXISTS idx_ai_generated_content_created_at ON ai_generated_content(created_at DESC);
 
+-- Post Reactions Indexes
+CREATE INDEX IF NOT EXISTS idx_post_reactions_post_id ON post_reactions(post_id);
+CREATE INDEX IF NOT EXISTS idx_post_reactions_created_at ON post_reactions(created_at DESC);
+
+-- Post View Stats Indexes
+CREATE INDEX IF NOT EXISTS idx_post_view_stats_bucket_start ON post_view_stats(bucket_start DESC);
+
+-- AI Usage Events Indexes
+CREATE INDEX IF NOT EXISTS idx_ai_usage_events_created_at ON ai_usage_events(created_at DESC);
+
+-- Post Traffic Sources Indexes
+CREATE INDEX IF NOT EXISTS idx_post_traffic_sources_post_id_day ON post_traffic_sources(post_id, day DESC);
+
+-- Search Query Stats Indexes
+CREATE INDEX IF NOT EXISTS idx_search_query_stats_day ON search_query_stats(day DESC);
+
+-- SEO Push Indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seo_push_queue_pending ON seo_push_queue(engine, url) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_seo_push_queue_status_next_attempt ON seo_push_queue(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_seo_push_history_created_at ON seo_push_history(created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_indexnow_keys_active ON indexnow_keys(is_active) WHERE is_active;
+
+-- Auth Token Indexes
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_admin_id ON refresh_tokens(admin_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- Auth Event Indexes
+CREATE INDEX IF NOT EXISTS idx_auth_events_admin_created ON auth_events(admin_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_auth_events_created_at ON auth_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_recovery_codes_admin_id ON admin_recovery_codes(admin_id);
+CREATE INDEX IF NOT EXISTS idx_login_throttles_locked_until ON login_throttles(locked_until);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_admin_id ON api_tokens(admin_id);
+CREATE INDEX IF NOT EXISTS idx_admin_identities_admin_id ON admin_identities(admin_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_created ON audit_logs(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON audit_logs(target_type, target_id);
+
 -- ==========================================
 -- TRIGGERS
 -- ==========================================
@@ -235,9 +647,8 @@ CREATE TRIGGER trigger_update_tag_use_count
 -- ==========================================
 
 -- Insert default admin
--- ⚠️ 请修改以下内容：
--- 1. 使用 backend/cmd/genhash 生成你的密码哈希
--- 2. 替换 username, email 和 password_hash
+-- ⚠️ 默认密码为 root123456，部署后请立即修改：
+--    go run ./cmd/devlog admin set-password admin
 INSERT INTO admins (username, email, password_hash) 
 SELECT 'admin', 'admin@example.com', '$2a$10$2Wm1.HSKoAeq1MxtEHBG/O16YPXnUBM34vwONiPo7DhfMVUJ7EmWC'
 WHERE NOT EXISTS (SELECT 1 FROM admins WHERE username = 'admin');
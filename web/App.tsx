@@ -6,12 +6,12 @@ import { PostView } from './components/PostView';
 import { AboutView } from './components/AboutView';
 import { ThemeToggle } from './components/ThemeToggle';
 import { BackgroundCanvas } from './components/BackgroundCanvas';
-import { AdminLogin } from './components/AdminLogin';
+import { AdminLogin, LoginResult } from './components/AdminLogin';
 import { AdminDashboard } from './components/AdminDashboard';
 import { BlogPost, ViewState } from './types';
 import { siteConfig } from './config';
 import { PostsService, AuthService } from './api-client';
-import { getToken, setToken, clearToken } from './services/api';
+import { getToken, setToken, clearToken, logout, verifyLogin, completeOIDCLogin, AuthTokens } from './services/api';
 
 // 将后端响应映射为前端 BlogPost 类型
 const mapPostResponse = (post: any): BlogPost => ({
@@ -26,6 +26,10 @@ const mapPostResponse = (post: any): BlogPost => ({
   isPublished: post.isPublished ?? post.is_published ?? false,
 });
 
+// 当前 URL 中的标签筛选（?tag=slug，站点地图中的标签页）
+const currentTag = (): string | undefined =>
+  new URLSearchParams(window.location.search).get('tag') || undefined;
+
 // Icons
 const GitHubIcon = () => (
   <svg viewBox="0 0 24 24" width="20" height="20" stroke="currentColor" strokeWidth="2" fill="none" strokeLinecap="round" strokeLinejoin="round"><path d="M9 19c-5 1.5-5-2.5-7-3m14 6v-3.87a3.37 3.37 0 0 0-.94-2.61c3.14-.35 6.44-1.54 6.44-7A5.44 5.44 0 0 0 20 4.77 5.07 5.07 0 0 0 19.91 1S18.73.65 16 2.48a13.38 13.38 0 0 0-7 0C6.27.65 5.09 1 5.09 1A5.07 5.07 0 0 0 5 4.77a5.44 5.44 0 0 0-1.5 3.78c0 5.42 3.3 6.61 6.44 7A3.37 3.37 0 0 0 9 18.13V22"></path></svg>
@@ -54,6 +58,7 @@ const App: React.FC = () => {
   // Lifted State
   const [posts, setPosts] = useState<BlogPost[]>([]);
   const [isLoggedIn, setIsLoggedIn] = useState(false);
+  const [loginError, setLoginError] = useState<string | undefined>();
   const [isLoading, setIsLoading] = useState(true);
   
   // 分页状态
@@ -69,7 +74,7 @@ const App: React.FC = () => {
   const loadPosts = async (isAdmin: boolean, pageNum: number = 1) => {
     const res = isAdmin
       ? await PostsService.getAdminPosts(pageNum, PAGE_SIZE, 'all')
-      : await PostsService.getPosts(pageNum, PAGE_SIZE);
+      : await PostsService.getPosts(pageNum, PAGE_SIZE, currentTag());
     const newPosts = res.data?.posts?.map(mapPostResponse) || [];
     setPosts(pageNum === 1 ? newPosts : prev => [...prev, ...newPosts]);
     setPage(pageNum);
@@ -82,6 +87,16 @@ const App: React.FC = () => {
     const initApp = async () => {
       setIsLoading(true);
       try {
+        // 单点登录回调
+        const sso = await completeOIDCLogin();
+        if (sso === 'failed') {
+          setLoginError('SSO Error: Sign-in failed or no account is linked');
+          setViewState(ViewState.LOGIN);
+        } else if (sso) {
+          setToken(sso);
+          setViewState(ViewState.ADMIN);
+        }
+
         const token = getToken();
         if (token) {
           const userRes = await AuthService.getAuthMe();
@@ -162,11 +177,16 @@ const App: React.FC = () => {
   };
 
   const navigateHome = () => {
+    const hadTag = currentTag() !== undefined;
     setViewState(ViewState.HOME);
     setSelectedPostId(null);
     setIsMobileMenuOpen(false);
     window.scrollTo(0, 0);
     window.history.pushState(null, '', '/');
+    // 离开标签页时恢复完整列表
+    if (hadTag) {
+      loadPosts(isLoggedIn);
+    }
   };
 
   const navigateToAbout = (e: React.MouseEvent) => {
@@ -210,24 +230,53 @@ const App: React.FC = () => {
   }, []);
 
   // Admin Actions
-  const handleLogin = async (username: string, password: string): Promise<boolean> => {
+  // 开启两步验证时, 密码校验通过后保存 challengeToken, 等待输入验证码
+  const loginChallenge = useRef<string | null>(null);
+
+  const completeLogin = async (tokens: AuthTokens) => {
+    setToken(tokens);
+    setIsLoggedIn(true);
+    setViewState(ViewState.ADMIN);
+    await loadPosts(true);
+  };
+
+  const handleLogin = async (username: string, password: string): Promise<LoginResult> => {
     try {
       const res = await AuthService.postAuthLogin({ username, password });
-      if (res.data?.token) {
-        setToken(res.data.token);
-        setIsLoggedIn(true);
-        setViewState(ViewState.ADMIN);
-        await loadPosts(true);
-        return true;
+      const data = res.data as (AuthTokens & { twoFactorRequired?: boolean; challengeToken?: string }) | undefined;
+      if (data?.twoFactorRequired && data.challengeToken) {
+        loginChallenge.current = data.challengeToken;
+        return '2fa';
+      }
+      if (data?.token) {
+        await completeLogin(data);
+        return 'ok';
       }
     } catch (e) {
       console.error('Login failed:', e);
     }
+    return 'denied';
+  };
+
+  const handleVerifyLogin = async (code: string): Promise<boolean> => {
+    if (!loginChallenge.current) {
+      return false;
+    }
+    try {
+      const tokens = await verifyLogin(loginChallenge.current, code);
+      if (tokens?.token) {
+        loginChallenge.current = null;
+        await completeLogin(tokens);
+        return true;
+      }
+    } catch (e) {
+      console.error('Two-factor verification failed:', e);
+    }
     return false;
   };
 
   const handleLogout = async () => {
-    clearToken();
+    await logout();
     setIsLoggedIn(false);
     await loadPosts(false);
     navigateHome();
@@ -422,7 +471,7 @@ const App: React.FC = () => {
           )}
           
           {viewState === ViewState.LOGIN && (
-             <AdminLogin onLogin={handleLogin} onCancel={navigateHome} />
+             <AdminLogin onLogin={handleLogin} onVerify={handleVerifyLogin} onCancel={navigateHome} initialError={loginError} />
           )}
 
           {viewState === ViewState.ADMIN && isLoggedIn && (
@@ -1,15 +1,37 @@
-import React, { useState } from 'react';
+import React, { useEffect, useState } from 'react';
+import { getOIDCProviders, startOIDCLogin, OIDCProvider } from '../services/api';
+
+// '2fa': 密码正确, 还需要两步验证码
+export type LoginResult = 'ok' | 'denied' | '2fa';
 
 interface AdminLoginProps {
-  onLogin: (username: string, password: string) => Promise<boolean>;
+  onLogin: (username: string, password: string) => Promise<LoginResult>;
+  onVerify: (code: string) => Promise<boolean>;
   onCancel: () => void;
+  initialError?: string; // 例如单点登录回调失败
 }
 
-export const AdminLogin: React.FC<AdminLoginProps> = ({ onLogin, onCancel }) => {
+export const AdminLogin: React.FC<AdminLoginProps> = ({ onLogin, onVerify, onCancel, initialError }) => {
   const [username, setUsername] = useState('');
   const [password, setPassword] = useState('');
-  const [error, setError] = useState('');
+  const [code, setCode] = useState('');
+  const [needsCode, setNeedsCode] = useState(false);
+  const [error, setError] = useState(initialError ?? '');
   const [isLoading, setIsLoading] = useState(false);
+  const [providers, setProviders] = useState<OIDCProvider[]>([]);
+
+  useEffect(() => {
+    getOIDCProviders().then(setProviders);
+  }, []);
+
+  const handleSSO = async (provider: string) => {
+    setError('');
+    setIsLoading(true);
+    if (!(await startOIDCLogin(provider))) {
+      setError('SSO Error: Provider unavailable');
+      setIsLoading(false);
+    }
+  };
 
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault();
@@ -17,8 +39,19 @@ export const AdminLogin: React.FC<AdminLoginProps> = ({ onLogin, onCancel }) =>
     setIsLoading(true);
     
     try {
-      const success = await onLogin(username, password);
-      if (!success) {
+      if (needsCode) {
+        const success = await onVerify(code);
+        if (!success) {
+          setError('Access Denied: Invalid Code');
+          setCode('');
+        }
+        return;
+      }
+
+      const result = await onLogin(username, password);
+      if (result === '2fa') {
+        setNeedsCode(true);
+      } else if (result === 'denied') {
         setError('Access Denied: Invalid Credentials');
         setPassword('');
       }
@@ -47,6 +80,22 @@ export const AdminLogin: React.FC<AdminLoginProps> = ({ onLogin, onCancel }) =>
           </div>
 
           <form onSubmit={handleSubmit} className="space-y-6">
+            {needsCode ? (
+            <div>
+              <label className="block text-xs uppercase tracking-wider text-secondary mb-2 font-mono">Authentication Code</label>
+              <input 
+                type="text" 
+                value={code}
+                onChange={(e) => setCode(e.target.value)}
+                className="w-full bg-bg border border-border rounded p-3 text-textLight focus:border-primary focus:outline-none font-mono tracking-widest"
+                placeholder="123456"
+                autoComplete="one-time-code"
+                autoFocus
+              />
+              <p className="mt-2 text-[10px] text-gray-500 font-mono">Enter the 6-digit code from your authenticator app, or a recovery code.</p>
+            </div>
+            ) : (
+            <>
             <div>
               <label className="block text-xs uppercase tracking-wider text-secondary mb-2 font-mono">Username</label>
               <input 
@@ -69,6 +118,8 @@ export const AdminLogin: React.FC<AdminLoginProps> = ({ onLogin, onCancel }) =>
                 placeholder="••••••••"
               />
             </div>
+            </>
+            )}
 
             {error && (
               <div className="p-3 bg-red-500/10 border border-red-500/20 rounded text-red-400 text-xs font-mono flex items-center">
@@ -89,10 +140,26 @@ export const AdminLogin: React.FC<AdminLoginProps> = ({ onLogin, onCancel }) =>
                 disabled={isLoading}
                 className="flex-1 py-2.5 bg-primary text-white rounded font-bold hover:opacity-90 transition-opacity shadow-lg shadow-primary/20 text-sm disabled:opacity-50 disabled:cursor-not-allowed"
               >
-                {isLoading ? 'Authenticating...' : 'Authenticate'}
+                {isLoading ? 'Authenticating...' : needsCode ? 'Verify' : 'Authenticate'}
               </button>
             </div>
           </form>
+
+          {!needsCode && providers.length > 0 && (
+            <div className="mt-6 pt-6 border-t border-border space-y-3">
+              {providers.map((provider) => (
+                <button
+                  key={provider.name}
+                  type="button"
+                  onClick={() => handleSSO(provider.name)}
+                  disabled={isLoading}
+                  className="w-full py-2.5 border border-border text-secondary hover:text-textLight hover:border-primary rounded font-bold transition-colors text-sm font-mono disabled:opacity-50 disabled:cursor-not-allowed"
+                >
+                  Sign in with {provider.displayName}
+                </button>
+              ))}
+            </div>
+          )}
         </div>
         
         <div className="px-6 py-3 bg-bg border-t border-border text-[10px] text-gray-500 font-mono text-center">
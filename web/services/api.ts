@@ -9,10 +9,22 @@ OpenAPI.BASE = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api/v
 
 // Token 管理
 const TOKEN_KEY = 'devlog_token';
+const EXPIRES_AT_KEY = 'devlog_token_expires_at';
+const REFRESH_TOKEN_KEY = 'devlog_refresh_token';
 
-export const setToken = (token: string) => {
+// 登录与刷新接口返回的令牌
+export interface AuthTokens {
+  token: string;
+  expiresAt?: number; // 访问令牌过期时间 (Unix 秒)
+  refreshToken?: string;
+}
+
+export const setToken = ({ token, expiresAt, refreshToken }: AuthTokens) => {
   localStorage.setItem(TOKEN_KEY, token);
-  OpenAPI.TOKEN = token;
+  localStorage.setItem(EXPIRES_AT_KEY, String(expiresAt ?? 0));
+  if (refreshToken) {
+    localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
+  }
 };
 
 export const getToken = (): string | null => {
@@ -21,15 +33,164 @@ export const getToken = (): string | null => {
 
 export const clearToken = () => {
   localStorage.removeItem(TOKEN_KEY);
-  OpenAPI.TOKEN = undefined;
+  localStorage.removeItem(EXPIRES_AT_KEY);
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
+};
+
+// 访问令牌剩余有效期低于该值 (毫秒) 时刷新
+const REFRESH_MARGIN_MS = 30_000;
+const REFRESH_LOCK = 'devlog_token_refresh';
+
+const tokenExpiring = (): boolean => {
+  const expiresAt = Number(localStorage.getItem(EXPIRES_AT_KEY) || 0);
+  return expiresAt > 0 && expiresAt * 1000 - Date.now() < REFRESH_MARGIN_MS;
+};
+
+const doRefresh = async (): Promise<string | null> => {
+  // 等锁期间其他标签页可能已经换发了令牌, 直接使用
+  const token = getToken();
+  if (token && !tokenExpiring()) {
+    return token;
+  }
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+  if (!refreshToken) {
+    return null;
+  }
+
+  const res = await fetch(`${OpenAPI.BASE}/auth/refresh`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ refreshToken }),
+  }).catch(() => null);
+  if (!res) {
+    return null;
+  }
+  if (!res.ok) {
+    // 请求期间其他标签页已换发令牌时保留新令牌
+    if (localStorage.getItem(REFRESH_TOKEN_KEY) === refreshToken) {
+      clearToken();
+    }
+    return null;
+  }
+  const body = await res.json();
+  setToken(body.data);
+  return body.data.token as string;
+};
+
+// 同一时间只发起一次刷新: 刷新令牌只能使用一次, 重复使用会导致会话被撤销。
+// 各标签页共享 localStorage 中的刷新令牌, 因此用 Web Locks 在标签页之间排队
+// (仅 HTTPS 或 localhost 下可用, 否则只在当前标签页内排队)
+let refreshing: Promise<string | null> | null = null;
+
+const refreshAccessToken = (): Promise<string | null> => {
+  if (!refreshing) {
+    const run = navigator.locks
+      ? navigator.locks.request(REFRESH_LOCK, doRefresh)
+      : doRefresh();
+    refreshing = run.catch(() => null).finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+};
+
+// 返回有效的访问令牌, 即将过期时先使用刷新令牌换发
+export const getAccessToken = async (): Promise<string> => {
+  const token = getToken();
+  if (!token) {
+    return '';
+  }
+  if (tokenExpiring()) {
+    return (await refreshAccessToken()) ?? '';
+  }
+  return token;
 };
 
-// 初始化 token
-const savedToken = getToken();
-if (savedToken) {
-  OpenAPI.TOKEN = savedToken;
+OpenAPI.TOKEN = getAccessToken;
+
+// 两步验证登录: 提交登录返回的 challengeToken 和验证码 (或恢复码)
+export const verifyLogin = async (challengeToken: string, code: string): Promise<AuthTokens | null> => {
+  const res = await fetch(`${OpenAPI.BASE}/auth/login/2fa`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ challengeToken, code }),
+  });
+  if (!res.ok) {
+    return null;
+  }
+  const body = await res.json();
+  return body.data as AuthTokens;
+};
+
+// ==================== 单点登录 (OIDC) ====================
+
+const OIDC_STATE_KEY = 'devlog_oidc_state';
+
+export interface OIDCProvider {
+  name: string;
+  displayName: string;
 }
 
+export const getOIDCProviders = async (): Promise<OIDCProvider[]> => {
+  const res = await fetch(`${OpenAPI.BASE}/auth/oidc/providers`).catch(() => null);
+  if (!res?.ok) {
+    return [];
+  }
+  const body = await res.json();
+  return (body.data as OIDCProvider[]) ?? [];
+};
+
+// 开始单点登录: 保存 stateToken 后跳转到身份提供方
+export const startOIDCLogin = async (provider: string): Promise<boolean> => {
+  const res = await fetch(`${OpenAPI.BASE}/auth/oidc/${encodeURIComponent(provider)}/start`, { method: 'POST' });
+  if (!res.ok) {
+    return false;
+  }
+  const body = await res.json();
+  sessionStorage.setItem(OIDC_STATE_KEY, body.data.stateToken);
+  window.location.assign(body.data.authorizationUrl);
+  return true;
+};
+
+// 身份提供方跳回 (?view=sso&code=...&state=...) 后换取令牌, 不是回调时返回 null
+export const completeOIDCLogin = async (): Promise<AuthTokens | 'failed' | null> => {
+  const params = new URLSearchParams(window.location.search);
+  if (params.get('view') !== 'sso') {
+    return null;
+  }
+  const stateToken = sessionStorage.getItem(OIDC_STATE_KEY);
+  sessionStorage.removeItem(OIDC_STATE_KEY);
+  window.history.replaceState(null, '', window.location.pathname);
+
+  const code = params.get('code');
+  const state = params.get('state');
+  if (!code || !state || !stateToken) {
+    return 'failed';
+  }
+  const res = await fetch(`${OpenAPI.BASE}/auth/oidc/callback`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ code, state, stateToken }),
+  }).catch(() => null);
+  if (!res?.ok) {
+    return 'failed';
+  }
+  const body = await res.json();
+  return body.data as AuthTokens;
+};
+
+// 登出当前会话, 服务端撤销令牌
+export const logout = async () => {
+  const token = await getAccessToken();
+  if (token) {
+    await fetch(`${OpenAPI.BASE}/auth/logout`, {
+      method: 'POST',
+      headers: { 'Authorization': `Bearer ${token}` },
+    }).catch(() => undefined);
+  }
+  clearToken();
+};
+
 // ==================== AI 服务 ====================
 
 export const summarizePost = async (content: string): Promise<string> => {
@@ -53,13 +214,14 @@ export const aiChatStream = async (
   onError: (error: string) => void
 ) => {
   const ctrl = new AbortController();
+  const token = await getAccessToken();
   
   try {
     await fetchEventSource(`${OpenAPI.BASE}/ai/chat/stream`, {
       method: 'POST',
       headers: {
         'Content-Type': 'application/json',
-        ...(OpenAPI.TOKEN ? { 'Authorization': `Bearer ${OpenAPI.TOKEN}` } : {}),
+        ...(token ? { 'Authorization': `Bearer ${token}` } : {}),
       },
       body: JSON.stringify({ message }),
       signal: ctrl.signal,
@@ -6,6 +6,14 @@ export default defineConfig({
       server: {
         port: 3000,
         host: '0.0.0.0',
+        // Feeds, sitemap, robots.txt, the IndexNow key file, the
+        // server-rendered post pages and share images are served by the backend
+        proxy: {
+          '^/((tags/[^/]+/)?(feed\\.xml|atom\\.xml|feed\\.json)|rss\\.xml|sitemap\\.xml|sitemaps/[0-9]+\\.xml|[A-Za-z0-9-]+\\.txt)$': 'http://localhost:8080',
+          '^/p/': 'http://localhost:8080',
+          '^/og/': 'http://localhost:8080',
+          '^/uploads/': 'http://localhost:8080',
+        },
       },
       plugins: [react()],
       resolve: {